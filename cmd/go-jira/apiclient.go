@@ -28,6 +28,9 @@ func loadDataConfig(cmd *cobra.Command) (Config, error) {
 			config.output, outputJSON, outputText,
 		)
 	}
+	if err := checkAuditLog(config.auditLog); err != nil {
+		return Config{}, err
+	}
 	return config, nil
 }
 
@@ -46,7 +49,10 @@ func resolveJiraClient(ctx context.Context, config Config) (*jira.Client, error)
 		return nil, fmt.Errorf("auth validation: %w", err)
 	}
 
-	httpClient := createHTTPClient(config, authenticator)
+	httpClient, err := createHTTPClient(config, authenticator)
+	if err != nil {
+		return nil, err
+	}
 	jiraClient, err := jira.NewClient(httpClient, config.baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("error creating jira client: %w", err)
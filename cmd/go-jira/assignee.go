@@ -2,43 +2,30 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log/slog"
-	"net/http"
+
+	"github.com/appleboy/go-jira/pkg/jiraops"
 
 	jira "github.com/andygrunwald/go-jira"
 )
 
-// processAssignee updates assignee for issues concurrently
+// processAssignee updates assignee for issues concurrently, within the limits
+// of ops (see newProcessor).
 func processAssignee(
 	ctx context.Context,
-	jiraClient *jira.Client,
+	ops *jiraops.Processor,
 	issues []*jira.Issue,
 	assignee *jira.User,
 ) error {
-	return forEachIssueConcurrent(issues, "updating assignees", func(iss *jira.Issue) error {
-		resp, err := jiraClient.Issue.UpdateAssigneeWithContext(
-			ctx,
-			iss.Key,
-			&jira.User{
-				Name: assignee.Name,
-			},
-		)
-		if resp != nil && resp.Body != nil {
-			defer resp.Body.Close()
-		}
-		if err != nil {
-			slog.Error("error updating assignee", "issue", iss.Key, "error", err)
-			return err
-		}
-		if resp.StatusCode != http.StatusNoContent {
-			slog.Error("error updating assignee", "issue", iss.Key, statusKey, resp.Status)
-			return fmt.Errorf("unexpected status: %s", resp.Status)
+	outcomes, err := ops.Assign(ctx, issues, assignee.Name)
+	for _, out := range outcomes {
+		if recordSetback(ctx, out, "error updating assignee") {
+			continue
 		}
-		slog.Info("assignee updated",
-			"issue", iss.Key,
+		issueLogger(out.Key, actionAssign).Info("assignee updated",
 			"assignee", assignee.Name,
+			logKeyDuration, out.Duration,
 		)
-		return nil
-	})
+		reportFrom(ctx).record(out.Key, actionAssign, outcomeOK, "")
+	}
+	return err
 }
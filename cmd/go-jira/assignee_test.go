@@ -313,7 +313,8 @@ func TestProcessAssignee(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			err = processAssignee(ctx, jiraClient, tt.issues, tt.assignee)
+			ops := newProcessor(ctx, jiraClient, Config{})
+			err = processAssignee(ctx, ops, tt.issues, tt.assignee)
 
 			if tt.wantErr {
 				if err == nil {
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appleboy/go-jira/pkg/util"
+)
+
+// GitHub Actions variables identifying the workflow run in audit entries.
+const (
+	envGitHubServerURL = "GITHUB_SERVER_URL"
+	envGitHubRunID     = "GITHUB_RUN_ID"
+	envGitHubSHA       = "GITHUB_SHA"
+)
+
+// auditEntry is one line of the audit log: a mutating Jira call, who made
+// it, against which issue, and a hash of what was sent.
+type auditEntry struct {
+	Time          string `json:"time"`
+	Actor         string `json:"actor"`
+	Action        string `json:"action"`
+	Issue         string `json:"issue,omitempty"`
+	Method        string `json:"method"`
+	URL           string `json:"url"`
+	Status        int    `json:"status,omitempty"`
+	Error         string `json:"error,omitempty"`
+	PayloadSHA256 string `json:"payload_sha256,omitempty"`
+	CIRun         string `json:"ci_run,omitempty"`
+}
+
+// auditMu serializes appends to audit logs across all clients, so lines
+// from concurrent issues never interleave.
+var auditMu sync.Mutex
+
+// checkAuditLog verifies that the audit log at path can be appended to, so a
+// run fails before its first change rather than after it. An empty path
+// disables auditing.
+func checkAuditLog(path string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+	return f.Close()
+}
+
+// appendAudit writes e as one JSON line at the end of the audit log at path
+// and syncs it to disk. The file is only ever opened for appending.
+func appendAudit(path string, e auditEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// auditTransport is a RoundTripper that appends every mutating Jira call to
+// the audit log. It wraps the authenticated chain, so the actor is looked up
+// once, on the first mutation, with the client's own credentials; with
+// --project-credentials each project's account is recorded as itself.
+type auditTransport struct {
+	base    http.RoundTripper
+	path    string
+	selfURL string
+
+	once  sync.Once
+	actor string
+}
+
+func newAuditTransport(base http.RoundTripper, config Config) *auditTransport {
+	return &auditTransport{
+		base:    base,
+		path:    config.auditLog,
+		selfURL: strings.TrimSuffix(config.baseURL, "/") + "/rest/api/2/myself",
+	}
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isMutation(req) {
+		return t.base.RoundTrip(req)
+	}
+	sum, err := payloadHash(req)
+	if err != nil {
+		return nil, err
+	}
+	t.once.Do(func() { t.actor = t.lookupActor(req) })
+
+	resp, err := t.base.RoundTrip(req)
+	action, issue := auditAction(req.Method, req.URL.Path)
+	e := auditEntry{
+		Time:          time.Now().UTC().Format(time.RFC3339Nano),
+		Actor:         t.actor,
+		Action:        action,
+		Issue:         issue,
+		Method:        req.Method,
+		URL:           req.URL.Scheme + "://" + req.URL.Host + req.URL.Path,
+		PayloadSHA256: sum,
+		CIRun:         ciRunURL(),
+	}
+	if resp != nil {
+		e.Status = resp.StatusCode
+	}
+	if err != nil {
+		e.Error = util.Redact(err.Error())
+	}
+	if werr := appendAudit(t.path, e); werr != nil {
+		slog.Error("could not write audit log", "path", t.path, "action", action,
+			"issue", issue, "error", werr)
+	}
+	return resp, err
+}
+
+// lookupActor returns the login name (or Cloud account ID) of the account
+// behind the client's credentials, or "unknown" when Jira does not say.
+func (t *auditTransport) lookupActor(orig *http.Request) string {
+	req, err := http.NewRequestWithContext(orig.Context(), http.MethodGet, t.selfURL, nil)
+	if err != nil {
+		return "unknown"
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return "unknown"
+	}
+	defer drainBody(resp.Body)
+	var u struct {
+		Name      string `json:"name"`
+		AccountID string `json:"accountId"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&u) != nil {
+		return "unknown"
+	}
+	if u.Name != "" {
+		return u.Name
+	}
+	if u.AccountID != "" {
+		return u.AccountID
+	}
+	return "unknown"
+}
+
+// isMutation reports whether req changes data in Jira. Searches sent as
+// POST only read.
+func isMutation(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return !strings.HasSuffix(req.URL.Path, "/search")
+}
+
+// payloadHash returns the hex SHA-256 of the request body, or "" without
+// one. The body is read through GetBody when possible and otherwise
+// buffered and put back, so the request still sends it.
+func payloadHash(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	var body io.ReadCloser
+	if req.GetBody != nil {
+		b, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+		body = b
+	} else {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		body = io.NopCloser(bytes.NewReader(data))
+	}
+	defer body.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// auditAction names the change a mutating call makes and the issue it
+// targets, from its method and REST path, e.g. POST
+// /rest/api/2/issue/GAIA-1/transitions is a transition of GAIA-1.
+func auditAction(method, path string) (action, issue string) {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segs {
+		switch s {
+		case "issueLink":
+			return "link", ""
+		case "issue":
+			if i < 2 || segs[i-2] != "api" {
+				continue
+			}
+			rest := segs[i+1:]
+			if len(rest) == 0 {
+				return "create", ""
+			}
+			issue = rest[0]
+			if len(rest) == 1 {
+				if method == http.MethodDelete {
+					return "delete", issue
+				}
+				return "edit", issue
+			}
+			switch rest[1] {
+			case "transitions":
+				return "transition", issue
+			case "comment":
+				return "comment", issue
+			case "assignee":
+				return "assign", issue
+			default:
+				return rest[1], issue
+			}
+		}
+	}
+	return strings.ToLower(method), ""
+}
+
+// ciRunURL links the GitHub Actions run that made the change, or the GitLab
+// CI pipeline, or returns "" outside both.
+func ciRunURL() string {
+	repo, runID := os.Getenv(envGitHubRepository), os.Getenv(envGitHubRunID)
+	if repo == "" || runID == "" {
+		return os.Getenv(envGitLabPipelineURL)
+	}
+	if _, err := strconv.ParseUint(runID, 10, 64); err != nil {
+		return ""
+	}
+	server := os.Getenv(envGitHubServerURL)
+	if server == "" {
+		server = "https://github.com"
+	}
+	return strings.TrimSuffix(server, "/") + "/" + repo + "/actions/runs/" + runID
+}
+
+// repoBlobURL is the URL of the repository's files at the commit being
+// built in GitHub Actions or GitLab CI, which relative Markdown links
+// resolve against by default, or "" outside both.
+func repoBlobURL() string {
+	repo, sha := os.Getenv(envGitHubRepository), os.Getenv(envGitHubSHA)
+	if repo != "" && sha != "" {
+		server := os.Getenv(envGitHubServerURL)
+		if server == "" {
+			server = "https://github.com"
+		}
+		return strings.TrimSuffix(server, "/") + "/" + repo + "/blob/" + sha + "/"
+	}
+	project, sha := os.Getenv(envGitLabProjectURL), os.Getenv(envGitLabCommitSHA)
+	if project != "" && sha != "" {
+		return strings.TrimSuffix(project, "/") + "/-/blob/" + sha + "/"
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditAction(t *testing.T) {
+	tests := []struct {
+		method, path  string
+		action, issue string
+	}{
+		{http.MethodPost, "/rest/api/2/issue/GAIA-1/transitions", "transition", "GAIA-1"},
+		{http.MethodPost, "/jira/rest/api/2/issue/GAIA-1/comment", "comment", "GAIA-1"},
+		{http.MethodPut, "/rest/api/2/issue/GAIA-1/assignee", "assign", "GAIA-1"},
+		{http.MethodPut, "/rest/api/2/issue/GAIA-1", "edit", "GAIA-1"},
+		{http.MethodDelete, "/rest/api/2/issue/GAIA-1", "delete", "GAIA-1"},
+		{http.MethodPost, "/rest/api/2/issue", "create", ""},
+		{http.MethodPost, "/rest/api/2/issueLink", "link", ""},
+		{http.MethodPost, "/rest/agile/1.0/sprint/7/issue", "post", ""},
+	}
+	for _, tt := range tests {
+		action, issue := auditAction(tt.method, tt.path)
+		if action != tt.action || issue != tt.issue {
+			t.Errorf("auditAction(%s %s) = %q, %q; want %q, %q",
+				tt.method, tt.path, action, issue, tt.action, tt.issue)
+		}
+	}
+}
+
+func TestAuditTransport(t *testing.T) {
+	t.Setenv(envGitHubRepository, "acme/app")
+	t.Setenv(envGitHubRunID, "42")
+	t.Setenv(envGitHubServerURL, "")
+	selfCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			selfCalls++
+			_, _ = w.Write([]byte(`{"name":"svc-ci"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	client := mustHTTPClient(t, Config{baseURL: server.URL, auditLog: path}, nil)
+	payload := `{"transition":{"id":"31"}}`
+	for _, req := range []*http.Request{
+		mustRequest(t, http.MethodGet, server.URL+"/rest/api/2/issue/GAIA-1", ""),
+		mustRequest(t, http.MethodPost, server.URL+"/rest/api/2/issue/GAIA-1/transitions", payload),
+		mustRequest(t, http.MethodPost, server.URL+"/rest/api/2/issue/GAIA-2/comment", "{}"),
+		mustRequest(t, http.MethodPost, server.URL+"/rest/api/2/search", "{}"),
+	} {
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		drainBody(resp.Body)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("decode %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d audit entries, want 2: %+v", len(entries), entries)
+	}
+	sum := sha256.Sum256([]byte(payload))
+	e := entries[0]
+	if e.Actor != "svc-ci" || e.Action != "transition" || e.Issue != "GAIA-1" ||
+		e.Status != http.StatusNoContent || e.PayloadSHA256 != hex.EncodeToString(sum[:]) ||
+		e.CIRun != "https://github.com/acme/app/actions/runs/42" {
+		t.Errorf("entry = %+v", e)
+	}
+	if entries[1].Action != "comment" || entries[1].Actor != "svc-ci" {
+		t.Errorf("entry = %+v", entries[1])
+	}
+	if selfCalls != 1 {
+		t.Errorf("actor looked up %d times, want 1", selfCalls)
+	}
+}
+
+func TestCheckAuditLog(t *testing.T) {
+	if err := checkAuditLog(""); err != nil {
+		t.Errorf("empty path: %v", err)
+	}
+	err := checkAuditLog(filepath.Join(t.TempDir(), "missing", "audit.jsonl"))
+	if err == nil || !strings.Contains(err.Error(), "audit log") {
+		t.Errorf("expected an audit log error, got %v", err)
+	}
+}
+
+func mustRequest(t *testing.T, method, url, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestRepoBlobURL(t *testing.T) {
+	for _, key := range []string{
+		envGitHubServerURL, envGitHubRepository, envGitHubSHA,
+		envGitLabProjectURL, envGitLabCommitSHA,
+	} {
+		t.Setenv(key, "")
+	}
+	if got := repoBlobURL(); got != "" {
+		t.Errorf("outside CI: repoBlobURL() = %q, want empty", got)
+	}
+
+	t.Setenv(envGitLabProjectURL, "https://gitlab.com/group/app")
+	t.Setenv(envGitLabCommitSHA, "abc123")
+	if got, want := repoBlobURL(), "https://gitlab.com/group/app/-/blob/abc123/"; got != want {
+		t.Errorf("GitLab: repoBlobURL() = %q, want %q", got, want)
+	}
+
+	t.Setenv(envGitHubRepository, "appleboy/go-jira")
+	t.Setenv(envGitHubSHA, "def456")
+	if got, want := repoBlobURL(), "https://github.com/appleboy/go-jira/blob/def456/"; got != want {
+		t.Errorf("GitHub: repoBlobURL() = %q, want %q", got, want)
+	}
+}
@@ -60,7 +60,7 @@ func runBoards(cmd *cobra.Command) error {
 		SearchOptions:  jira.SearchOptions{MaxResults: limit},
 	})
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return fmt.Errorf("error listing boards for project %s: %w", project, err)
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+)
+
+// Branch of a GitHub Actions run: the pull request's source branch, or the
+// short name of the pushed ref.
+const (
+	envGitHubHeadRef = "GITHUB_HEAD_REF"
+	envGitHubRefName = "GITHUB_REF_NAME"
+)
+
+// branchRule maps a branch glob to the transition applied on matching
+// branches.
+type branchRule struct {
+	pattern    string
+	transition string
+}
+
+// parseBranchRules parses the --branch-transitions value: "pattern=Transition"
+// pairs separated by semicolons or newlines, e.g.
+// "feature/*=In Progress;hotfix/*=In Review;main=Done". Patterns use
+// path.Match syntax, so * does not cross a "/".
+func parseBranchRules(s string) ([]branchRule, error) {
+	var rules []branchRule
+	for _, part := range splitRules(s) {
+		pattern, transition, ok := strings.Cut(part, "=")
+		pattern, transition = strings.TrimSpace(pattern), strings.TrimSpace(transition)
+		if !ok || pattern == "" || transition == "" {
+			return nil, fmt.Errorf("invalid branch rule %q: want \"pattern=Transition\"", part)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid branch rule %q: %w", part, err)
+		}
+		rules = append(rules, branchRule{pattern: pattern, transition: transition})
+	}
+	return rules, nil
+}
+
+// splitRules splits a rules table on semicolons and newlines, dropping blank
+// entries; the newline form lets a multi-line CI input list one rule per line.
+func splitRules(s string) []string {
+	var parts []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ';' || r == '\n'
+	}) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// matchBranchRule returns the transition of the first rule matching branch.
+func matchBranchRule(rules []branchRule, branch string) (string, bool) {
+	for _, r := range rules {
+		if ok, _ := path.Match(r.pattern, branch); ok {
+			return r.transition, true
+		}
+	}
+	return "", false
+}
+
+// currentBranch returns the branch that triggered the run: --branch when
+// set, otherwise the GitHub Actions pull request source branch or pushed ref,
+// then the GitLab CI merge request source branch or pipeline ref.
+func currentBranch(override string) string {
+	if override != "" {
+		return override
+	}
+	for _, env := range []string{
+		envGitHubHeadRef, envGitHubRefName, envGitLabMRSource, envGitLabRefName,
+	} {
+		if b := os.Getenv(env); b != "" {
+			return b
+		}
+	}
+	return ""
+}
+
+// branchTransition resolves the transition for the run: the first branch
+// rule matching the current branch, falling back to toTransition (which may
+// be empty) when no rule matches.
+func branchTransition(config Config) (string, error) {
+	rules, err := parseBranchRules(config.branchTransitions)
+	if err != nil || len(rules) == 0 {
+		return config.toTransition, err
+	}
+	branch := currentBranch(config.branch)
+	if transition, ok := matchBranchRule(rules, branch); ok {
+		slog.Info("branch rule matched", "branch", branch, "transition", transition)
+		return transition, nil
+	}
+	slog.Info("no branch rule matched", "branch", branch, "transition", config.toTransition)
+	return config.toTransition, nil
+}
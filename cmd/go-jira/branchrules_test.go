@@ -0,0 +1,91 @@
+package main
+
+import "testing"
+
+func TestParseBranchRules(t *testing.T) {
+	rules, err := parseBranchRules("feature/*=In Progress; hotfix/*=In Review\nmain=Done;")
+	if err != nil {
+		t.Fatalf("parseBranchRules: %v", err)
+	}
+	want := []branchRule{
+		{"feature/*", "In Progress"},
+		{"hotfix/*", "In Review"},
+		{"main", "Done"},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("rules = %+v, want %+v", rules, want)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"main", "=Done", "main=", "[=Done"} {
+		if _, err := parseBranchRules(bad); err == nil {
+			t.Errorf("parseBranchRules(%q): expected error", bad)
+		}
+	}
+}
+
+func TestBranchTransition(t *testing.T) {
+	rules := "feature/*=In Progress;hotfix/*=In Review;main=Done"
+	tests := []struct {
+		name    string
+		config  Config
+		headRef string
+		refName string
+		want    string
+	}{
+		{
+			name:    "pull request source branch",
+			config:  Config{branchTransitions: rules},
+			headRef: "feature/login",
+			refName: "42/merge",
+			want:    "In Progress",
+		},
+		{
+			name:    "pushed branch",
+			config:  Config{branchTransitions: rules},
+			refName: "main",
+			want:    "Done",
+		},
+		{
+			name:    "explicit branch wins",
+			config:  Config{branchTransitions: rules, branch: "hotfix/crash"},
+			refName: "main",
+			want:    "In Review",
+		},
+		{
+			name:    "no match falls back to --to-transition",
+			config:  Config{branchTransitions: rules, toTransition: "Review"},
+			refName: "release/1.2",
+			want:    "Review",
+		},
+		{
+			name:    "star does not cross a slash",
+			config:  Config{branchTransitions: rules},
+			refName: "feature/a/b",
+			want:    "",
+		},
+		{
+			name:    "no rules",
+			config:  Config{toTransition: "Done"},
+			refName: "feature/x",
+			want:    "Done",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envGitHubHeadRef, tt.headRef)
+			t.Setenv(envGitHubRefName, tt.refName)
+			got, err := branchTransition(tt.config)
+			if err != nil {
+				t.Fatalf("branchTransition: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("branchTransition = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/appleboy/go-jira/pkg/util"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/spf13/cobra"
+)
+
+// Jira permission keys verified by check and --precheck, as named by the
+// mypermissions API.
+const (
+	permBrowse     = "BROWSE_PROJECTS"
+	permEdit       = "EDIT_ISSUES"
+	permTransition = "TRANSITION_ISSUES"
+	permComment    = "ADD_COMMENTS"
+	permAssign     = "ASSIGN_ISSUES"
+)
+
+// checkPermissionKeys are the permissions `check` verifies by default: what
+// run needs to browse, edit, transition, comment on, and assign issues.
+var checkPermissionKeys = []string{permBrowse, permEdit, permTransition, permComment, permAssign}
+
+// checkResult is the outcome of one pre-flight check, with a hint on how to
+// fix it when it failed.
+type checkResult struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// newCheckCmd builds the `check` subcommand: a read-only pre-flight of the
+// base URL, the credentials, and the permissions run needs on the given
+// projects, so a misconfigured pipeline fails before it mutates anything.
+func newCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "check",
+		Short:   "Verify connectivity, authentication, and project permissions",
+		GroupID: groupConfig,
+		Long: `Run read-only pre-flight checks against Jira:
+
+  1. the base URL is reachable (server info),
+  2. the credentials authenticate (current user),
+  3. the user holds the browse, edit, transition, comment, and assign
+     permissions on each --project (or the project of --key).
+
+Every check is reported with a hint on how to fix it, and the command exits
+non-zero when any check fails. run performs the permission part itself when
+--precheck is set.`,
+		Example: `  # Check connectivity, auth, and permissions on two projects
+  go-jira check --project GAIA,OPS --output text
+
+  # Check the permissions on one sample issue
+  go-jira check --key GAIA-123`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runCheck(cmd)
+		},
+	}
+	addCommonFlags(cmd)
+	addOAuthFlags(cmd)
+	addAuthFlags(cmd)
+	addOutputFlag(cmd)
+	cmd.Flags().String(flagProject, "", "Comma-separated project keys to check permissions on")
+	cmd.Flags().String(flagKey, "", "Sample issue key to check permissions on, e.g. GAIA-123")
+	return cmd
+}
+
+func runCheck(cmd *cobra.Command) error {
+	config, err := loadDataConfig(cmd)
+	if err != nil {
+		return err
+	}
+	project, _ := cmd.Flags().GetString(flagProject)
+	key, _ := cmd.Flags().GetString(flagKey)
+	projects := splitCSV(project)
+
+	ctx, cancel := cmdContextWithTimeout(cmd, time.Minute)
+	defer cancel()
+
+	results := []checkResult{checkReachable(ctx, config)}
+	if results[0].OK {
+		results = append(results, checkAuth(ctx, config, projects, key)...)
+	}
+
+	if err := emitResult(config, results, func() {
+		for _, r := range results {
+			mark := "ok  "
+			if !r.OK {
+				mark = "FAIL"
+			}
+			fmt.Fprintf(os.Stdout, "%s\t%s\t%s\n", mark, r.Name, r.Detail)
+			if r.Hint != "" {
+				fmt.Fprintf(os.Stdout, "\t→ %s\n", r.Hint)
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if !r.OK {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// checkReachable fetches the server info without credentials, which tells a
+// network, proxy, or TLS problem apart from an authentication one.
+func checkReachable(ctx context.Context, config Config) checkResult {
+	res := checkResult{Name: "reachable " + config.baseURL}
+	httpClient, err := createHTTPClient(config, nil)
+	if err != nil {
+		res.Detail = err.Error()
+		return res
+	}
+	jiraClient, err := jira.NewClient(httpClient, config.baseURL)
+	if err != nil {
+		res.Detail = err.Error()
+		return res
+	}
+	req, err := jiraClient.NewRequestWithContext(ctx, http.MethodGet, "rest/api/2/serverInfo", nil)
+	if err != nil {
+		res.Detail = err.Error()
+		return res
+	}
+	var info struct {
+		Version        string `json:"version"`
+		DeploymentType string `json:"deploymentType"`
+	}
+	resp, err := jiraClient.Do(req, &info)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil && resp == nil {
+		res.Detail = util.Redact(err.Error())
+		res.Hint = "check base_url, the proxy settings, and the TLS trust (--ca-cert) from this runner"
+		return res
+	}
+	// Any HTTP answer proves the server is reachable; some instances only
+	// serve server info to authenticated users.
+	res.OK = true
+	res.Detail = strings.Join(strings.Fields("Jira "+info.Version+" "+info.DeploymentType), " ")
+	if err != nil {
+		res.Detail = "reachable, server info answered " + resp.Status
+	}
+	return res
+}
+
+// checkAuth verifies the credentials and then the permissions on every
+// project, and on the project of key when set.
+func checkAuth(ctx context.Context, config Config, projects []string, key string) []checkResult {
+	res := checkResult{Name: "authenticated"}
+	jiraClient, err := resolveJiraClient(ctx, config)
+	var user *jira.User
+	if err == nil {
+		user, err = getSelf(ctx, jiraClient)
+	}
+	if err != nil {
+		res.Detail = util.Redact(err.Error())
+		res.Hint = `verify the token or username/password, or run "go-jira login" / ` +
+			`"go-jira token refresh" for OAuth`
+		return []checkResult{res}
+	}
+	res.OK = true
+	res.Detail = fmt.Sprintf("%s <%s> (%s)", user.DisplayName, user.EmailAddress, userID(user))
+	results := []checkResult{res}
+
+	scopes := make([]permissionScope, 0, len(projects)+1)
+	for _, p := range projects {
+		scopes = append(scopes, permissionScope{project: strings.ToUpper(p)})
+	}
+	if key != "" {
+		scopes = append(scopes, permissionScope{issue: key})
+	}
+	for _, scope := range scopes {
+		perms, err := checkPermissions(ctx, jiraClient, scope, checkPermissionKeys)
+		if err != nil {
+			results = append(results, checkResult{
+				Name:   "permissions on " + scope.String(),
+				Detail: util.Redact(err.Error()),
+				Hint:   "check that " + scope.String() + " exists and is visible to " + userID(user),
+			})
+			continue
+		}
+		for i := range perms {
+			if !perms[i].OK {
+				perms[i].Hint = fmt.Sprintf(
+					"ask a Jira admin to grant %s to %s in the permission scheme of %s",
+					strings.TrimPrefix(perms[i].Name, "permission "), userID(user), scope.String())
+			}
+		}
+		results = append(results, perms...)
+	}
+	return results
+}
+
+// userID returns the login name, or the Cloud account ID when there is none.
+func userID(u *jira.User) string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.AccountID
+}
+
+// permissionScope is a project or an issue permissions are checked against.
+type permissionScope struct {
+	project string
+	issue   string
+}
+
+func (s permissionScope) String() string {
+	if s.issue != "" {
+		return s.issue
+	}
+	return s.project
+}
+
+// checkPermissions asks Jira whether the current user holds each of perms in
+// scope, one result per permission. A permission the server does not report
+// counts as missing.
+func checkPermissions(
+	ctx context.Context,
+	jiraClient *jira.Client,
+	scope permissionScope,
+	perms []string,
+) ([]checkResult, error) {
+	q := url.Values{"permissions": {strings.Join(perms, ",")}}
+	if scope.issue != "" {
+		q.Set("issueKey", scope.issue)
+	} else {
+		q.Set("projectKey", scope.project)
+	}
+	req, err := jiraClient.NewRequestWithContext(
+		ctx, http.MethodGet, "rest/api/2/mypermissions?"+q.Encode(), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Permissions map[string]struct {
+			HavePermission bool `json:"havePermission"`
+		} `json:"permissions"`
+	}
+	resp, err := jiraClient.Do(req, &body)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error checking permissions on %s: %w", scope, err)
+	}
+	results := make([]checkResult, 0, len(perms))
+	for _, p := range perms {
+		res := checkResult{Name: "permission " + p, Detail: "on " + scope.String()}
+		if got, ok := body.Permissions[p]; ok {
+			res.OK = got.HavePermission
+		} else {
+			res.Detail += ", not reported by the server"
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// runPermissions returns the permissions run needs for the configured
+// actions: browse always, plus transition, comment, and assign when used.
+func runPermissions(config Config) []string {
+	perms := []string{permBrowse}
+	if config.toTransition != "" || config.closingTransition != "" ||
+		config.commitTypeTransitions != "" {
+		perms = append(perms, permTransition)
+	}
+	if config.comment != "" {
+		perms = append(perms, permComment)
+	}
+	if config.assignee != "" {
+		perms = append(perms, permAssign)
+	}
+	return perms
+}
+
+// precheckRun verifies, before run changes anything, that the account used
+// for each project of issues holds the permissions the run needs there. Every
+// missing permission is logged with a hint and the run is refused.
+func precheckRun(
+	ctx context.Context,
+	jiraClient *jira.Client,
+	config Config,
+	issues []*jira.Issue,
+) error {
+	perms := runPermissions(config)
+	seen := map[string]bool{}
+	var errs []error
+	for _, iss := range issues {
+		project := strings.ToUpper(issueProject(iss.Key))
+		if seen[project] {
+			continue
+		}
+		seen[project] = true
+		results, err := checkPermissions(ctx, clientFor(ctx, jiraClient, iss.Key),
+			permissionScope{project: project}, perms)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, r := range results {
+			if r.OK {
+				continue
+			}
+			perm := strings.TrimPrefix(r.Name, "permission ")
+			slog.Error("missing Jira permission", "project", project, "permission", perm,
+				"hint", "ask a Jira admin to grant it in the project's permission scheme, "+
+					"or run \"go-jira check --project "+project+"\"")
+			errs = append(errs, fmt.Errorf("missing %s permission on project %s", perm, project))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("precheck failed: %w", errors.Join(errs...))
+	}
+	slog.Info("precheck passed", "projects", len(seen), "permissions", strings.Join(perms, ","))
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// newCheckServer serves server info, the current user, and mypermissions,
+// granting every permission except the ones in denied.
+func newCheckServer(t *testing.T, denied ...string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/serverInfo":
+			_, _ = w.Write([]byte(`{"version":"9.12.0","deploymentType":"Server"}`))
+		case "/rest/api/2/myself":
+			_, _ = w.Write([]byte(`{"name":"svc-ci","displayName":"CI Bot","emailAddress":"ci@example.com"}`))
+		case "/rest/api/2/mypermissions":
+			perms := map[string]any{}
+			for _, p := range strings.Split(r.URL.Query().Get("permissions"), ",") {
+				have := true
+				for _, d := range denied {
+					have = have && p != d
+				}
+				perms[p] = map[string]any{"key": p, "havePermission": have}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"permissions": perms})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestCheckCmd(t *testing.T) {
+	server := newCheckServer(t)
+	defer server.Close()
+
+	out, err := runDataCmd(t, newCheckCmd(), server.URL, "--project", "gaia")
+	if err != nil {
+		t.Fatalf("check returned error: %v", err)
+	}
+	var results []checkResult
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out)
+	}
+	// reachable + authenticated + one result per permission.
+	if len(results) != 2+len(checkPermissionKeys) {
+		t.Fatalf("got %d results: %+v", len(results), results)
+	}
+	if !strings.Contains(results[0].Detail, "9.12.0") || !strings.Contains(results[1].Detail, "svc-ci") {
+		t.Errorf("results = %+v", results[:2])
+	}
+}
+
+func TestCheckCmdMissingPermission(t *testing.T) {
+	server := newCheckServer(t, permTransition)
+	defer server.Close()
+
+	out, err := runDataCmd(t, newCheckCmd(), server.URL, "--project", "GAIA", "--output", "text")
+	if err == nil || !strings.Contains(err.Error(), "1 of 7 checks failed") {
+		t.Fatalf("expected one failed check, got %v", err)
+	}
+	if !strings.Contains(out, "FAIL\tpermission TRANSITION_ISSUES") ||
+		!strings.Contains(out, "grant TRANSITION_ISSUES to svc-ci") {
+		t.Errorf("output missing the failed check and its hint:\n%s", out)
+	}
+}
+
+func TestPrecheckRun(t *testing.T) {
+	server := newCheckServer(t, permComment)
+	defer server.Close()
+	jiraClient, err := jira.NewClient(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := []*jira.Issue{{Key: "GAIA-1"}, {Key: "GAIA-2"}, {Key: "OPS-3"}}
+
+	config := Config{toTransition: "Done"}
+	if err := precheckRun(context.Background(), jiraClient, config, issues); err != nil {
+		t.Fatalf("transition only: %v", err)
+	}
+
+	config.comment = "deployed"
+	err = precheckRun(context.Background(), jiraClient, config, issues)
+	if err == nil || !strings.Contains(err.Error(), "missing ADD_COMMENTS permission on project GAIA") ||
+		!strings.Contains(err.Error(), "project OPS") {
+		t.Fatalf("expected missing comment permission on both projects, got %v", err)
+	}
+}
+
+func TestRunPermissions(t *testing.T) {
+	got := runPermissions(Config{closingTransition: "Done", assignee: "jdoe"})
+	if strings.Join(got, ",") != "BROWSE_PROJECTS,TRANSITION_ISSUES,ASSIGN_ISSUES" {
+		t.Errorf("runPermissions = %v", got)
+	}
+}
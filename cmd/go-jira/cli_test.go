@@ -136,3 +136,38 @@ func TestWhoamiBearer(t *testing.T) {
 		t.Fatalf("runWhoami: %v", err)
 	}
 }
+
+func TestWhoamiJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/myself" || r.URL.Query().Get("expand") != "groups" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"accountId":"5b10ac8d82e05b22cc7d4ef5","displayName":"CI Bot",` +
+			`"emailAddress":"ci@example.com","groups":{"size":2,` +
+			`"items":[{"name":"jira-software-users"},{"name":"release-bots"}]}}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("INPUT_BASE_URL", srv.URL)
+	t.Setenv("INPUT_TOKEN", "pat-123")
+	t.Setenv("INPUT_INSECURE", "true")
+
+	cmd := newWhoamiCmd()
+	if err := cmd.ParseFlags([]string{"--output", "json"}); err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	var runErr error
+	out := captureStdout(t, func() { runErr = runWhoami(cmd) })
+	if runErr != nil {
+		t.Fatalf("runWhoami: %v", runErr)
+	}
+	var got whoamiResult
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out)
+	}
+	if got.AccountID != "5b10ac8d82e05b22cc7d4ef5" || got.DisplayName != "CI Bot" ||
+		strings.Join(got.Groups, ",") != "jira-software-users,release-bots" || got.AuthMode == "" {
+		t.Errorf("whoami = %+v", got)
+	}
+}
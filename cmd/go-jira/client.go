@@ -2,10 +2,11 @@ package main
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/appleboy/go-jira/pkg/auth"
@@ -13,37 +14,119 @@ import (
 	jira "github.com/andygrunwald/go-jira"
 )
 
+// defaultIdleConnsPerHost is the idle connection pool kept per host when
+// --max-concurrency is unset. The stdlib default of 2 makes the per-issue
+// fan-out dial (and TLS-handshake) a fresh connection for most requests.
+const defaultIdleConnsPerHost = 32
+
+// maxDrainBytes bounds how much of an unread response body drainBody discards
+// to keep the connection reusable; a larger remainder is cheaper to drop.
+const maxDrainBytes = 1 << 20
+
 // createHTTPClient creates an HTTP client with optional TLS configuration and
 // authentication. It clones http.DefaultTransport so all standard-library
 // defaults (proxy, connection pool, timeouts, HTTP/2) are preserved, only
-// overriding TLSClientConfig when --insecure is set, and layers the
-// authenticator's credentials on top via its RoundTripper.
-func createHTTPClient(config Config, authenticator auth.Authenticator) *http.Client {
+// overriding TLSClientConfig when --insecure, a CA bundle, or a client
+// certificate is set and Proxy when an explicit --proxy is given, and layers
+// the authenticator's credentials on top via its RoundTripper. The idle pool
+// is sized to the run's concurrency and every body is drained on close (see
+// drainTransport) so the fan-out reuses its connections; cacheTransport adds
+// gzip and ETag revalidation for repeated lookups, and metricsTransport counts
+// every call that reaches the network. tracingTransport records a span per
+// call when OTLP tracing is on, and auditTransport appends every mutating call
+// to --audit-log.
+//
+// The TLS material, proxy URL, and custom headers are validated up front by
+// validateConfig and requireBaseURL; should one still fail to load here, its
+// error is returned rather than falling back to the stdlib defaults.
+func createHTTPClient(config Config, authenticator auth.Authenticator) (*http.Client, error) {
 	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	httpTransport.MaxIdleConnsPerHost = defaultIdleConnsPerHost
+	if config.maxConcurrency > 0 {
+		httpTransport.MaxIdleConnsPerHost = config.maxConcurrency
+	}
 
 	if config.insecure {
 		slog.Warn("Skipping SSL certificate verification is insecure and not recommended")
-		httpTransport.TLSClientConfig = &tls.Config{
-			MinVersion:         tls.VersionTLS12,
-			InsecureSkipVerify: true, // #nosec G402 -- opt-in via flag
-		}
+	}
+	tlsConfig, err := tlsClientConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	if tlsConfig != nil {
+		httpTransport.TLSClientConfig = tlsConfig
+	}
+	proxy, err := proxyFunc(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy configuration: %w", err)
+	}
+	httpTransport.Proxy = proxy
+	headers, err := parseHeaders(config.headers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom headers: %w", err)
 	}
 
-	// Layer the authenticator's credentials on top when present, then wrap the
-	// whole chain in diagTransport (in a single place) so error-status responses
-	// are recorded regardless of whether authentication is configured.
-	var base http.RoundTripper = httpTransport
+	// Layer the authenticator's credentials on top when present, add the
+	// custom --headers outside it (so the credentials, set later in the chain,
+	// always win), set the User-Agent outside that (so a User-Agent in
+	// --headers overrides it), then wrap the whole chain in diagTransport (in
+	// a single place) so error-status responses are recorded regardless of
+	// whether authentication is configured.
+	var base http.RoundTripper = &cacheTransport{
+		base: &drainTransport{base: &metricsTransport{base: httpTransport}},
+	}
 	if authenticator != nil {
-		base = authenticator.Transport(httpTransport)
+		base = authenticator.Transport(base)
+	}
+	if len(headers) > 0 {
+		base = &headerTransport{base: base, headers: headers}
 	}
-	return &http.Client{Transport: &diagTransport{base: base}}
+	base = &userAgentTransport{base: base, userAgent: userAgent()}
+	if config.auditLog != "" {
+		base = newAuditTransport(base, config)
+	}
+	base = &tracingTransport{base: base}
+	return &http.Client{Transport: &diagTransport{base: base}}, nil
+}
+
+// drainBody discards what is left of a response body, up to maxDrainBytes,
+// before closing it. The JSON decoder stops at the end of the value, and a
+// body closed before EOF makes the transport drop the connection instead of
+// returning it to the idle pool.
+func drainBody(body io.ReadCloser) {
+	_ = drainingBody{body}.Close()
+}
+
+// drainingBody is a response body whose Close drains it first.
+type drainingBody struct {
+	io.ReadCloser
+}
+
+func (b drainingBody) Close() error {
+	_, _ = io.Copy(io.Discard, io.LimitReader(b.ReadCloser, maxDrainBytes))
+	return b.ReadCloser.Close()
+}
+
+// drainTransport makes every response body drain on Close, including the
+// ones the Jira library decodes and closes itself, which the per-call
+// drainBody cannot reach.
+type drainTransport struct {
+	base http.RoundTripper
+}
+
+func (t *drainTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if resp != nil && resp.Body != nil && resp.Body != http.NoBody {
+		resp.Body = drainingBody{resp.Body}
+	}
+	return resp, err
 }
 
 // getSelf retrieves the current authenticated user
 func getSelf(ctx context.Context, jiraClient *jira.Client) (*jira.User, error) {
 	user, resp, err := jiraClient.User.GetSelfWithContext(ctx)
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return nil, err
@@ -60,7 +143,7 @@ func getUser(ctx context.Context, jiraClient *jira.Client, username string) (*ji
 
 	user, resp, err := jiraClient.User.GetByUsernameWithContext(ctx, username)
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return nil, err
@@ -69,6 +152,49 @@ func getUser(ctx context.Context, jiraClient *jira.Client, username string) (*ji
 	return user, nil
 }
 
+// findUserByEmail returns the mention name of the Jira user with email: the
+// login name on Server and Data Center, or "accountid:<id>" on Cloud, which
+// has no login names. It returns "" when no single user has the address.
+func findUserByEmail(ctx context.Context, jiraClient *jira.Client, email string) (string, error) {
+	query := url.QueryEscape(email)
+	users, resp, err := jiraClient.User.FindWithContext(ctx, query)
+	if resp != nil && resp.Body != nil {
+		drainBody(resp.Body)
+	}
+	if err != nil {
+		// Server and Data Center search by the username parameter, which
+		// Cloud rejects, rather than by query.
+		users, resp, err = jiraClient.User.FindWithContext(ctx, query, jira.WithUsername(query))
+		if resp != nil && resp.Body != nil {
+			drainBody(resp.Body)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	var match *jira.User
+	for i := range users {
+		// Cloud may hide e-mail addresses, so a sole result with a hidden
+		// address is taken as is; a visible one must match.
+		hidden := len(users) == 1 && users[i].EmailAddress == ""
+		if hidden || strings.EqualFold(users[i].EmailAddress, email) {
+			if match != nil {
+				return "", nil
+			}
+			match = &users[i]
+		}
+	}
+	switch {
+	case match == nil:
+		return "", nil
+	case match.Name != "":
+		return match.Name, nil
+	case match.AccountID != "":
+		return "accountid:" + match.AccountID, nil
+	}
+	return "", nil
+}
+
 // getResolutionID retrieves the resolution ID by name
 func getResolutionID(
 	ctx context.Context,
@@ -77,7 +203,7 @@ func getResolutionID(
 ) (string, error) {
 	resolutions, resp, err := jiraClient.Resolution.GetListWithContext(ctx)
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return "", err
@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/appleboy/go-jira/pkg/auth"
@@ -95,7 +98,7 @@ func TestCreateHTTPClient(t *testing.T) {
 				Password: tt.config.password,
 				Token:    tt.config.token,
 			})
-			client := createHTTPClient(tt.config, authenticator)
+			client := mustHTTPClient(t, tt.config, authenticator)
 			tt.verify(t, client)
 		})
 	}
@@ -438,3 +441,70 @@ func TestGetResolutionID(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateHTTPClientIdlePool(t *testing.T) {
+	for _, tt := range []struct {
+		maxConcurrency int
+		want           int
+	}{
+		{0, defaultIdleConnsPerHost},
+		{8, 8},
+	} {
+		client := mustHTTPClient(t, Config{maxConcurrency: tt.maxConcurrency}, nil)
+		rt := client.Transport.(*diagTransport).base.(*tracingTransport).base.(*userAgentTransport).
+			base.(*cacheTransport).base.(*drainTransport).base.(*metricsTransport).base
+		tr, ok := rt.(*http.Transport)
+		if !ok {
+			t.Fatalf("innermost transport = %T, want *http.Transport", rt)
+		}
+		if tr.MaxIdleConnsPerHost != tt.want {
+			t.Errorf("maxConcurrency %d: MaxIdleConnsPerHost = %d, want %d",
+				tt.maxConcurrency, tr.MaxIdleConnsPerHost, tt.want)
+		}
+	}
+}
+
+func TestDrainBodyReusesConnection(t *testing.T) {
+	var mu sync.Mutex
+	conns := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// Trailing whitespace past the JSON value is left unread by the
+		// decoder, as with a pretty-printed or padded response.
+		_ = json.NewEncoder(w).Encode(jira.User{Name: "jdoe"})
+		_, _ = w.Write([]byte(strings.Repeat(" ", 768<<10)))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(mustHTTPClient(t, Config{}, nil), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 5 {
+		if _, err := getSelf(context.Background(), jiraClient); err != nil {
+			t.Fatalf("getSelf: %v", err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if conns != 1 {
+		t.Errorf("opened %d connections for 5 sequential requests, want 1", conns)
+	}
+}
+
+// mustHTTPClient is createHTTPClient for a config known to be valid.
+func mustHTTPClient(t *testing.T, config Config, authenticator auth.Authenticator) *http.Client {
+	t.Helper()
+	client, err := createHTTPClient(config, authenticator)
+	if err != nil {
+		t.Fatalf("createHTTPClient: %v", err)
+	}
+	return client
+}
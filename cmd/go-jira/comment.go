@@ -2,56 +2,32 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"io"
-	"log/slog"
-	"net/http"
+
+	"github.com/appleboy/go-jira/pkg/jiraops"
 
 	jira "github.com/andygrunwald/go-jira"
 )
 
-// addComments adds comments to issues concurrently
+// addComments adds comments to issues concurrently, within the limits of ops
+// (see newProcessor).
 func addComments(
 	ctx context.Context,
-	jiraClient *jira.Client,
+	ops *jiraops.Processor,
 	comment string,
 	issues []*jira.Issue,
 	user *jira.User,
 ) error {
-	return forEachIssueConcurrent(issues, "adding comments", func(iss *jira.Issue) error {
-		item, resp, err := jiraClient.Issue.AddCommentWithContext(
-			ctx,
-			iss.Key,
-			&jira.Comment{
-				Name: user.Name,
-				Body: comment,
-			},
-		)
-		if resp != nil && resp.Body != nil {
-			defer resp.Body.Close()
-		}
-		if err != nil {
-			slog.Error("error adding comment", "issue", iss.Key, "error", err)
-			return err
-		}
-
-		if resp.StatusCode != http.StatusCreated {
-			body, _ := io.ReadAll(resp.Body)
-			slog.Error(
-				"error adding comment",
-				"issue",
-				iss.Key,
-				statusKey,
-				resp.StatusCode,
-				"body",
-				string(body),
-			)
-			return fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
+	outcomes, err := ops.Comment(ctx, issues, comment, user.Name)
+	for _, out := range outcomes {
+		if recordSetback(ctx, out, "error adding comment") {
+			continue
 		}
-		slog.Info("added comment to issue",
-			"issue", iss.Key,
-			"comment", item.Body,
+		issueLogger(out.Key, actionComment).Info("added comment to issue",
+			"comment", comment,
+			logKeyDuration, out.Duration,
 		)
-		return nil
-	})
+		reportFrom(ctx).record(out.Key, actionComment, outcomeOK, "")
+		reportFrom(ctx).setCommentID(out.Key, out.CommentID)
+	}
+	return err
 }
@@ -417,7 +417,8 @@ func TestAddComments(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			err = addComments(ctx, jiraClient, tt.comment, tt.issues, tt.user)
+			ops := newProcessor(ctx, jiraClient, Config{})
+			err = addComments(ctx, ops, tt.comment, tt.issues, tt.user)
 
 			if tt.wantErr {
 				if err == nil {
@@ -1,44 +1,52 @@
 package main
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"sync"
+
+	"github.com/appleboy/go-jira/pkg/jiraops"
 
 	jira "github.com/andygrunwald/go-jira"
 )
 
-// forEachIssueConcurrent runs fn for every issue in parallel. fn is responsible
-// for its own logging; any error it returns is counted. When one or more issues
-// fail, a single summarizing error is returned, using noun (e.g. "adding
-// comments") to describe the operation.
-func forEachIssueConcurrent(issues []*jira.Issue, noun string, fn func(*jira.Issue) error) error {
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(issues))
-
-	for _, issue := range issues {
-		wg.Add(1)
-		go func(iss *jira.Issue) {
-			defer wg.Done()
-			if err := fn(iss); err != nil {
-				errChan <- err
-			}
-		}(issue)
-	}
+// defaultIssueTimeout bounds each issue's work when --issue-timeout is unset.
+const defaultIssueTimeout = jiraops.DefaultIssueTimeout
 
-	wg.Wait()
-	close(errChan)
+// newProcessor returns the jiraops processor that runs the per-issue phases
+// of run (fetch, transition, assign, comment) against jiraClient, or the
+// --project-credentials client of each issue's project. The run's
+// --max-concurrency, --issue-timeout, and --fail-fast become its options, so
+// the cap is shared by every phase run through it.
+func newProcessor(ctx context.Context, jiraClient *jira.Client, config Config) *jiraops.Processor {
+	return jiraops.New(jiraops.Wrap(jiraClient), jiraops.Options{
+		ClientFor: func(key string) jiraops.JiraClient {
+			return jiraops.Wrap(clientFor(ctx, jiraClient, key))
+		},
+		Concurrency:  config.maxConcurrency,
+		IssueTimeout: config.issueTimeout,
+		FailFast:     config.failFast,
+	})
+}
 
-	// Collect the actual errors, not just a count, so the real cause (HTTP
-	// status, message) survives into the returned/serialized error instead of
-	// only reaching the per-issue logs.
-	var errs []error
-	for err := range errChan {
-		errs = append(errs, err)
-	}
-	if len(errs) > 0 {
-		return fmt.Errorf("encountered %d errors while %s: %w",
-			len(errs), noun, errors.Join(errs...))
+// recordSetback logs and records a skipped or failed jiraops outcome and
+// reports whether it was one. A failure is logged under msg; a skip, such as
+// a read-only issue or one not attempted under --fail-fast, is only warned
+// about.
+func recordSetback(ctx context.Context, out jiraops.Outcome, msg string) bool {
+	log := issueLogger(out.Key, out.Action)
+	switch out.Status {
+	case jiraops.StatusSkipped:
+		if errors.Is(out.Err, jiraops.ErrReadOnly) {
+			log.Warn("skipped: read-only", "reason", out.Err)
+		} else {
+			log.Warn("skipped", "reason", out.Err)
+		}
+		reportFrom(ctx).record(out.Key, out.Action, outcomeSkipped, out.Reason())
+		return true
+	case jiraops.StatusFailed:
+		log.Error(msg, "error", out.Err, logKeyDuration, out.Duration)
+		reportFrom(ctx).record(out.Key, out.Action, outcomeFailed, out.Reason())
+		return true
 	}
-	return nil
+	return false
 }
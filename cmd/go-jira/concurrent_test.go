@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// newPhaseServer answers every transition with handle and counts the calls.
+func newPhaseServer(t *testing.T, handle func(w http.ResponseWriter, r *http.Request)) (
+	*jira.Client, *atomic.Int32,
+) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		handle(w, r)
+	}))
+	t.Cleanup(server.Close)
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jiraClient, &calls
+}
+
+func TestNewProcessorFailFast(t *testing.T) {
+	for _, tt := range []struct {
+		failFast  bool
+		wantCalls int32
+	}{
+		{failFast: false, wantCalls: 5},
+		{failFast: true, wantCalls: 1},
+	} {
+		jiraClient, calls := newPhaseServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		// A cap of one runs the issues one at a time, so under fail-fast
+		// the first failure is seen before any other issue starts.
+		config := Config{maxConcurrency: 1, failFast: tt.failFast}
+		r := newRunReport("")
+		ctx := withReport(context.Background(), r)
+
+		ops := newProcessor(ctx, jiraClient, config)
+		err := processTransitions(ctx, ops, "Done", "", createManyIssues(5))
+		if err == nil {
+			t.Fatalf("failFast=%t: expected an error", tt.failFast)
+		}
+		if got := calls.Load(); got != tt.wantCalls {
+			t.Errorf("failFast=%t: %d transitions sent, want %d", tt.failFast, got, tt.wantCalls)
+		}
+		skipped := 0
+		for _, res := range r.results() {
+			for _, a := range res.Actions {
+				if a.Outcome == outcomeSkipped {
+					skipped++
+				}
+			}
+		}
+		if want := 5 - int(tt.wantCalls); skipped != want {
+			t.Errorf("failFast=%t: %d issues recorded as skipped, want %d", tt.failFast, skipped, want)
+		}
+	}
+}
+
+func TestNewProcessorIssueTimeout(t *testing.T) {
+	issues := createManyIssues(3)
+	hung := issues[0].Key
+	release := make(chan struct{})
+	defer close(release)
+	jiraClient, _ := newPhaseServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, hung) {
+			// A request that never answers is cut off by the issue deadline.
+			<-release
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r := newRunReport("")
+	ctx := withReport(context.Background(), r)
+
+	ops := newProcessor(ctx, jiraClient, Config{issueTimeout: 20 * time.Millisecond})
+	err := processTransitions(ctx, ops, "Done", "", issues)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if got := r.failedCount(); got != 1 {
+		t.Errorf("failed = %d, want only the hung issue", got)
+	}
+}
+
+func TestNewProcessorConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	jiraClient, _ := newPhaseServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ctx := context.Background()
+	ops := newProcessor(ctx, jiraClient, Config{maxConcurrency: 2})
+	if err := processTransitions(ctx, ops, "Done", "", createManyIssues(10)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", p)
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/appleboy/go-jira/pkg/issuekey"
 	"github.com/appleboy/go-jira/pkg/util"
 
 	"github.com/spf13/cobra"
@@ -29,6 +32,10 @@ type Config struct {
 	password     string
 	token        string
 	ref          string
+	refFile      string
+	refs         string
+	commitRange  string
+	prNumber     int
 	issuePattern string
 	toTransition string
 	resolution   string
@@ -37,6 +44,130 @@ type Config struct {
 	insecure     bool
 	markdown     bool
 	debug        bool
+	// codeDefaultLang is the language of Markdown code blocks that name none;
+	// empty renders them as {noformat}.
+	codeDefaultLang string
+	// mermaidImageURL is the service Markdown mermaid diagrams are rendered
+	// with; empty renders them as code.
+	mermaidImageURL string
+	// markdownBaseURL is what relative Markdown links and images resolve
+	// against.
+	markdownBaseURL string
+	// linkIssueKeys links the issue keys in a Markdown comment to their
+	// Jira pages.
+	linkIssueKeys bool
+	// mentions and mentionsFile map Git usernames to Jira users for
+	// Markdown @mentions.
+	mentions     string
+	mentionsFile string
+	// mentionMode is which Markdown @name text becomes a mention.
+	mentionMode string
+	// imagesAttached renders Markdown images named by a bare file name as
+	// attachments of the issue.
+	imagesAttached bool
+	// headingOffset shifts Markdown heading levels down.
+	headingOffset int
+	// toc is the table of contents put before a Markdown comment.
+	toc string
+	// lineBreaks is how line breaks inside a Markdown paragraph render.
+	lineBreaks string
+	// Use the legacy cookie login (rest/auth/1/session) with username and
+	// password instead of Basic Auth, for servers that disable Basic Auth.
+	sessionAuth bool
+	// Kerberos / SPNEGO credentials: a keytab with its principal, or a ticket
+	// cache. krb5Config and krb5SPN override the krb5.conf path and the
+	// service principal (default HTTP/<host>).
+	krb5Keytab    string
+	krb5Principal string
+	krb5CCache    string
+	krb5Config    string
+	krb5SPN       string
+
+	// PEM bundle (file path or inline content) of extra CAs trusted for the
+	// Jira connection, an alternative to --insecure for internal CAs.
+	caCert string
+	// Extra headers sent on every Jira request, as "Name: value" pairs
+	// separated by semicolons or newlines.
+	headers string
+	// Explicit proxy URL (http, https, socks5) for every Jira request; when
+	// empty the HTTP_PROXY / HTTPS_PROXY / NO_PROXY env vars apply.
+	proxy string
+	// Mutual TLS client certificate and key presented to Jira, each either a
+	// file path or inline PEM content.
+	tlsCert string
+	tlsKey  string
+
+	// Scheduling controls for run: jitter delays the start by up to this window
+	// (spread per jitterKey), and maxConcurrency caps the Jira requests in
+	// flight (0 = unlimited).
+	jitter         time.Duration
+	jitterKey      string
+	maxConcurrency int
+	// failMode is the raw --fail-mode policy for per-issue failures; see
+	// parseFailMode.
+	failMode string
+	// failOnNoIssues fails run when the ref references no issue keys, for
+	// repos that enforce referencing an issue in every change.
+	failOnNoIssues bool
+	// failFast cancels the rest of a phase on its first per-issue error.
+	failFast bool
+	// issueTimeout bounds each issue's request within the run's overall
+	// timeout (0 = defaultIssueTimeout).
+	issueTimeout time.Duration
+	// reportFile is where run writes its JSON report (see writeReportFile).
+	reportFile string
+	// metricsFile and metricsPushgateway export the run's metrics as a
+	// node_exporter textfile and to a Prometheus Pushgateway (see metrics.go).
+	metricsFile        string
+	metricsPushgateway string
+	// precheck makes run verify its Jira permissions on every project
+	// before changing anything (see precheckRun).
+	precheck bool
+	// auditLog is the JSONL file every mutating Jira call is appended to
+	// (see auditTransport); empty disables auditing.
+	auditLog string
+	// preHook and postHook are shell commands run before anything changes
+	// and at the end of run, with JSON on stdin (see exechook.go).
+	preHook  string
+	postHook string
+
+	// branchTransitions maps branch globs to transitions ("feature/*=In
+	// Progress;main=Done"); branch overrides the branch detected from the
+	// GitHub Actions environment.
+	branchTransitions string
+	branch            string
+	// commitTypes limits run to issues referenced by Conventional Commits of
+	// these comma-separated types; commitTypeTransitions picks a transition
+	// per commit type ("feat=In Review;fix=Done").
+	commitTypes           string
+	commitTypeTransitions string
+	// trailersOnly reads issue keys only from Git trailers whose token is in
+	// the comma-separated trailers list (default Jira, Refs, Issue).
+	trailersOnly bool
+	trailers     string
+	// closingTransition is applied to issues referenced with a closing
+	// keyword ("Closes ABC-1"), leaving plain mentions to --to-transition.
+	closingTransition string
+	// projects and excludeProjects are comma-separated project keys that
+	// restrict which extracted issue keys run acts on.
+	projects        string
+	excludeProjects string
+	// validateProjects drops keys whose project does not exist on the server
+	// before fetching them.
+	validateProjects bool
+	// issueTypes limits run to fetched issues of these comma-separated type
+	// names, e.g. "Bug,Task".
+	issueTypes string
+	// filterJQL is a JQL condition the extracted issues must also match.
+	filterJQL string
+	// maxIssues caps how many issues run acts on (0 = no cap); past it the
+	// run aborts, or keeps the first maxIssues in "truncate" mode.
+	maxIssues     int
+	maxIssuesMode string
+
+	// projectCredentials maps projects to their own service account, for
+	// projects that restrict who may transition (see projectcreds.go).
+	projectCredentials string
 
 	// Output format for the data subcommands: "json" (default) or "text".
 	output string
@@ -61,6 +192,15 @@ type Config struct {
 	// broker; brokerToken is the optional caller bearer token sent to it.
 	brokerURL   string
 	brokerToken string
+
+	// secretErr is the error reading a TOKEN_FILE / PASSWORD_FILE secret.
+	// loadConfig cannot fail, so validateConfig and requireBaseURL report it
+	// before any request.
+	secretErr error
+	// parseErr joins the errors parsing integer and duration env values, such
+	// as MAX_ISSUES=50x; validateConfig reports it rather than letting the
+	// setting fall back to 0, which for max_issues would mean no cap.
+	parseErr error
 }
 
 // loadConfig resolves configuration from CLI flags (when explicitly set)
@@ -68,42 +208,179 @@ type Config struct {
 //
 // The INPUT_<KEY> → <KEY> lookup order inside util.GetGlobalValue is preserved
 // verbatim, so GitHub Actions (which sets INPUT_*) and local .env usage both
-// keep working. Passing cmd == nil sends every action lookup straight to the
-// environment, keeping tests and non-cobra callers working unchanged.
+// keep working. Env keys renamed in deprecatedEnvKeys are still read under
+// their old names, with a warning. Passing cmd == nil sends every action
+// lookup straight to the environment, keeping tests and non-cobra callers
+// working unchanged.
 func loadConfig(cmd *cobra.Command) Config {
-	getString := func(flagName, envKey string) string {
+	// envValue looks up envKey and then its aliases, e.g. the JIRA_-prefixed
+	// names the docs and the auth-resolver error message use, so an alias
+	// only applies when envKey is unset.
+	envValue := func(envKey string, aliases []string) string {
+		return util.GetGlobalValue(append([]string{resolveEnvKey(envKey)}, aliases...)...)
+	}
+	// getString reads a value verbatim: flag values are already expanded by
+	// the invoking shell, and env values such as ref carry commit text that
+	// may contain a literal ${...}.
+	getString := func(flagName, envKey string, aliases ...string) string {
 		if cmd != nil && cmd.Flags().Lookup(flagName) != nil && cmd.Flags().Changed(flagName) {
 			v, _ := cmd.Flags().GetString(flagName)
 			return v
 		}
-		return util.GetGlobalValue(envKey)
+		return envValue(envKey, aliases)
+	}
+	// getExpanded is getString that expands ${GITHUB_*}/${CI_*} references in
+	// env values, e.g. INPUT_COMMENT="Deployed by ${GITHUB_ACTOR}", which a
+	// composite action passes through unexpanded.
+	getExpanded := func(flagName, envKey string, aliases ...string) string {
+		if flagChanged(cmd, flagName) {
+			return getString(flagName, envKey)
+		}
+		return util.ExpandEnv(envValue(envKey, aliases))
+	}
+	// getSecret is getString that also reads the secret from the file named
+	// by <KEY>_FILE, for Docker and Kubernetes secrets mounted as files. The
+	// aliases come last: flag > INPUT_<KEY>/<KEY> > <KEY>_FILE > aliases.
+	var secretErr error
+	getSecret := func(flagName, envKey string, aliases ...string) string {
+		if flagChanged(cmd, flagName) {
+			v, _ := cmd.Flags().GetString(flagName)
+			return v
+		}
+		v, err := util.GetSecretValue(resolveEnvKey(envKey))
+		if err != nil && secretErr == nil {
+			secretErr = err
+		}
+		if v == "" && len(aliases) > 0 {
+			if v = util.GetGlobalValue(aliases...); v != "" {
+				util.Mask(v)
+			}
+		}
+		return v
+	}
+	// getPathOrSecret is for settings holding a file path or the secret
+	// itself, like tls_key: the value is read unmasked, as a path must stay
+	// readable in logs, and <KEY>_FILE through getSecret. Inline secrets are
+	// masked by registerConfigSecrets.
+	getPathOrSecret := func(flagName, envKey string) string {
+		if v := getString(flagName, envKey); v != "" {
+			return v
+		}
+		return getSecret(flagName, envKey)
 	}
-	getBool := func(flagName, envKey string) bool {
+	getBool := func(flagName, envKey string, aliases ...string) bool {
 		if cmd != nil && cmd.Flags().Lookup(flagName) != nil && cmd.Flags().Changed(flagName) {
 			v, _ := cmd.Flags().GetBool(flagName)
 			return v
 		}
-		return util.ToBool(util.GetGlobalValue(envKey))
+		return util.ToBool(envValue(envKey, aliases))
+	}
+	// getDuration and getInt follow the same flag > env order. An unparseable
+	// env value is collected into parseErrs for validateConfig to report.
+	var parseErrs []error
+	getDuration := func(flagName, envKey string) time.Duration {
+		if flagChanged(cmd, flagName) {
+			v, _ := cmd.Flags().GetDuration(flagName)
+			return v
+		}
+		d, err := util.GetDuration(resolveEnvKey(envKey), 0)
+		if err != nil {
+			parseErrs = append(parseErrs, err)
+		}
+		return d
+	}
+	getInt := func(flagName, envKey string) int {
+		if flagChanged(cmd, flagName) {
+			return flagIntValue(cmd, flagName)
+		}
+		n, err := util.GetInt(resolveEnvKey(envKey), 0)
+		if err != nil {
+			parseErrs = append(parseErrs, err)
+		}
+		return n
 	}
 
 	cfg := Config{
-		baseURL:      getString(flagBaseURL, "base_url"),
-		insecure:     getBool(flagInsecure, "insecure"),
-		username:     getString(flagUsername, "username"),
-		password:     getString(flagPassword, "password"),
-		token:        getString(flagToken, "token"),
+		baseURL:      getExpanded(flagBaseURL, "base_url", envBaseURL),
+		insecure:     getBool(flagInsecure, "insecure", envInsecure),
+		username:     getString(flagUsername, "username", envUsername),
+		password:     getSecret(flagPassword, "password", envPassword),
+		token:        getSecret(flagToken, "token", envToken),
+		sessionAuth:  getBool(flagSessionAuth, "session_auth"),
 		ref:          getString(flagRef, "ref"),
+		refFile:      getString(flagRefFile, "ref_file"),
+		refs:         getString(flagRefs, "refs"),
+		commitRange:  getString(flagCommitRange, "commit_range"),
 		issuePattern: getString(flagIssueFormat, "issue_format"),
-		toTransition: getString(flagToTransition, "transition"),
-		resolution:   getString(flagResolution, "resolution"),
-		comment:      getString(flagComment, "comment"),
+		toTransition: getExpanded(flagToTransition, "transition"),
+		resolution:   getExpanded(flagResolution, "resolution"),
+		comment:      getExpanded(flagComment, "comment"),
 		assignee:     getString(flagAssignee, "assignee"),
 		markdown:     getBool(flagMarkdown, "markdown"),
 		debug:        getBool(flagDebug, "debug"),
 		output:       getString(flagOutput, "output"),
 		epicField:    getString(flagEpicField, "epic_field"),
 		sprintField:  getString(flagSprintField, "sprint_field"),
+		caCert:       getString(flagCACert, "ca_cert"),
+		proxy:        getString(flagProxy, "proxy"),
+		headers:      getString(flagHeaders, "headers"),
+		tlsCert:      getString(flagClientCert, "tls_cert"),
+		tlsKey:       getPathOrSecret(flagClientKey, "tls_key"),
+
+		krb5Keytab:    getString(flagKrb5Keytab, "krb5_keytab"),
+		krb5Principal: getString(flagKrb5Principal, "krb5_principal"),
+		krb5CCache:    getString(flagKrb5CCache, "krb5_ccache"),
+		krb5Config:    getString(flagKrb5Config, "krb5_config"),
+		krb5SPN:       getString(flagKrb5SPN, "krb5_spn"),
+
+		jitter:         getDuration(flagJitter, "jitter"),
+		jitterKey:      getString(flagJitterKey, "jitter_key"),
+		maxConcurrency: getInt(flagMaxConcurrency, "max_concurrency"),
+		prNumber:       getInt(flagPRNumber, "pr_number"),
+		maxIssues:      getInt(flagMaxIssues, "max_issues"),
+		failMode:       getString(flagFailMode, "fail_mode"),
+		failOnNoIssues: getBool(flagFailOnNoIssues, "fail_on_no_issues"),
+		failFast:       getBool(flagFailFast, "fail_fast"),
+		issueTimeout:   getDuration(flagIssueTimeout, "issue_timeout"),
+		reportFile:     getString(flagReportFile, "report_file"),
+		metricsFile:    getString(flagMetricsFile, "metrics_file"),
+		precheck:       getBool(flagPrecheck, "precheck"),
+		auditLog:       getString(flagAuditLog, "audit_log"),
+		preHook:        getString(flagPreHook, "pre_hook"),
+		postHook:       getString(flagPostHook, "post_hook"),
+
+		metricsPushgateway: getString(flagMetricsPush, "metrics_pushgateway"),
+		codeDefaultLang:    getString(flagCodeLang, "code_default_lang"),
+		mermaidImageURL:    getString(flagMermaidURL, "mermaid_image_url"),
+		markdownBaseURL:    getExpanded(flagMarkdownBase, "markdown_base_url"),
+		linkIssueKeys:      getBool(flagLinkIssues, "link_issue_keys"),
+		mentions:           getString(flagMentions, "mentions"),
+		mentionsFile:       getString(flagMentionsFile, "mentions_file"),
+		mentionMode:        getString(flagMentionMode, "mention_mode"),
+		imagesAttached:     getBool(flagImagesAttach, "images_attached"),
+		headingOffset:      getInt(flagHeadingShift, "heading_offset"),
+		toc:                getString(flagTOC, "toc"),
+		lineBreaks:         getString(flagLineBreaks, "line_breaks"),
+
+		branchTransitions: getExpanded(flagBranchRules, "branch_transitions"),
+		branch:            getString(flagBranch, "branch"),
+
+		commitTypes:           getString(flagCommitTypes, "commit_types"),
+		commitTypeTransitions: getExpanded(flagTypeRules, "commit_type_transitions"),
+		trailersOnly:          getBool(flagTrailersOnly, "trailers_only"),
+		trailers:              getString(flagTrailers, "trailers"),
+		closingTransition:     getExpanded(flagClosingRule, "closing_transition"),
+		projects:              getString(flagProjects, "projects"),
+		excludeProjects:       getString(flagExcludeProjs, "exclude_projects"),
+		validateProjects:      getBool(flagValidateProj, "validate_projects"),
+		issueTypes:            getString(flagIssueTypes, "issue_types"),
+		filterJQL:             getExpanded(flagFilterJQL, "filter_jql"),
+		maxIssuesMode:         getString(flagMaxIssuesOn, "max_issues_mode"),
+		projectCredentials:    getString(flagProjectCreds, "project_credentials"),
+
+		secretErr: secretErr,
 	}
+	cfg.parseErr = errors.Join(parseErrs...)
 
 	// Output defaults to JSON (machine-readable, matching the Python CLI).
 	if cfg.output == "" {
@@ -117,25 +394,9 @@ func loadConfig(cmd *cobra.Command) Config {
 	if cfg.sprintField == "" {
 		cfg.sprintField = defaultSprintField
 	}
-
-	// Accept the JIRA_-prefixed env vars as aliases (lowest precedence: flag >
-	// INPUT_<KEY>/<KEY> > JIRA_<KEY>), so the JIRA_* examples in the docs and
-	// the auth-resolver error message work as written.
-	if cfg.baseURL == "" {
-		cfg.baseURL = os.Getenv(envBaseURL)
-	}
-	if cfg.username == "" {
-		cfg.username = os.Getenv(envUsername)
-	}
-	if cfg.password == "" {
-		cfg.password = os.Getenv(envPassword)
-	}
-	if cfg.token == "" {
-		cfg.token = os.Getenv(envToken)
-	}
-	if !cfg.insecure && !flagChanged(cmd, flagInsecure) &&
-		util.GetGlobalValue("insecure") == "" {
-		cfg.insecure = util.ToBool(os.Getenv(envInsecure))
+	// Relative Markdown links point into the repository being built.
+	if cfg.markdownBaseURL == "" {
+		cfg.markdownBaseURL = repoBlobURL()
 	}
 
 	// OAuth fields use fixed JIRA_-prefixed env vars (see main.go), not the
@@ -167,6 +428,9 @@ func loadConfig(cmd *cobra.Command) Config {
 
 	warnOnSecretFlags(cmd)
 	warnOnInsecureBrokerURL(cfg.brokerURL)
+	// Every credential is masked from logs, the debug dump, and error output
+	// from here on, so pasted logs cannot leak it.
+	registerConfigSecrets(cfg)
 	return cfg
 }
 
@@ -189,6 +453,51 @@ func warnOnInsecureBrokerURL(brokerURL string) {
 	}
 }
 
+// deprecatedEnvKey records an env key that was renamed: the old name is still
+// read, with a deprecation warning, while the new one is unset.
+type deprecatedEnvKey struct {
+	oldKey string
+	newKey string
+	since  string // release that introduced the new name
+}
+
+// deprecatedEnvKeys lists the renamed env keys, each lowercase without the
+// INPUT_/PLUGIN_ prefix like the loadConfig keys. Add an entry here when
+// renaming a key, e.g. {oldKey: "issue_format", newKey: "issue_pattern", since:
+// "v1.9.0"}, so existing workflows keep working for a deprecation period.
+var deprecatedEnvKeys []deprecatedEnvKey
+
+// warnedEnvKeys holds the deprecated env keys already warned about, so a
+// process that loads its config more than once warns only once per key.
+var warnedEnvKeys = struct {
+	sync.Mutex
+	keys map[string]bool
+}{keys: map[string]bool{}}
+
+// resolveEnvKey returns the env key loadConfig reads for key: key itself
+// unless only a deprecated name for it is set, in which case that name is
+// returned and a warning asks, once, to rename it.
+func resolveEnvKey(key string) string {
+	if util.GetGlobalValue(key) != "" {
+		return key
+	}
+	for _, d := range deprecatedEnvKeys {
+		if d.newKey != key || util.GetGlobalValue(d.oldKey) == "" {
+			continue
+		}
+		warnedEnvKeys.Lock()
+		warned := warnedEnvKeys.keys[d.oldKey]
+		warnedEnvKeys.keys[d.oldKey] = true
+		warnedEnvKeys.Unlock()
+		if !warned {
+			slog.Warn("env var is deprecated; rename it", "env", strings.ToUpper(d.oldKey),
+				"use", strings.ToUpper(d.newKey), "since", d.since)
+		}
+		return d.oldKey
+	}
+	return key
+}
+
 // warnOnSecretFlags warns when secrets arrive via CLI flag — they leak into ps
 // / /proc/<pid>/cmdline / shell history. Env vars and .env files don't.
 func warnOnSecretFlags(cmd *cobra.Command) {
@@ -355,19 +664,55 @@ func validateBaseURL(config Config) error {
 
 // validateConfig validates the run-action configuration. Authentication
 // selection (including OAuth) is handled by auth.Resolve; this only enforces
-// the base URL, ref, and the basic-auth pairing rule.
+// the base URL, ref, and the basic-auth pairing rule, and that the rule tables
+// and the issue pattern parse. Every problem is reported, joined with
+// errors.Join, so a workflow can be fixed in one iteration.
 func validateConfig(config Config) error {
-	if err := validateBaseURL(config); err != nil {
-		return err
+	var errs []error
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
 	}
-	if config.ref == "" {
-		return errors.New("ref is required")
+	check(validateBaseURL(config))
+	check(config.secretErr)
+	check(config.parseErr)
+	check(validateTLS(config))
+	check(validateProxy(config))
+	check(validateHeaders(config))
+	if config.ref == "" && config.refs == "" {
+		check(errors.New("ref is required"))
 	}
 	if config.username != "" && config.password == "" {
-		return errors.New("password is required when username is provided")
+		check(errors.New("password is required when username is provided"))
 	}
 	if config.password != "" && config.username == "" {
-		return errors.New("username is required when password is provided")
-	}
-	return nil
+		check(errors.New("username is required when password is provided"))
+	}
+	_, err := issuekey.Compile(config.issuePattern)
+	check(err)
+	_, err = parseFailMode(config.failMode)
+	check(err)
+	_, err = parseBranchRules(config.branchTransitions)
+	check(err)
+	_, err = parseTypeTransitions(config.commitTypeTransitions)
+	check(err)
+	if config.maxIssues < 0 {
+		check(errors.New("max_issues must not be negative"))
+	}
+	check(validateMaxIssuesMode(config.maxIssuesMode))
+	if config.headingOffset < 0 || config.headingOffset > 5 {
+		check(errors.New("heading_offset must be between 0 and 5"))
+	}
+	_, err = parseTOCMode(config.toc)
+	check(err)
+	_, err = parseLineBreakStyle(config.lineBreaks)
+	check(err)
+	_, err = parseProjectCredentials(config.projectCredentials)
+	check(err)
+	_, err = loadMentions(config)
+	check(err)
+	_, err = parseMentionMode(config.mentionMode)
+	check(err)
+	return errors.Join(errs...)
 }
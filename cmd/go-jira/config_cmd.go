@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/appleboy/go-jira/pkg/auth"
@@ -16,6 +18,10 @@ import (
 // resolved from an environment variable.
 const sourceEnv = "env"
 
+// sourceFile is the SOURCE column value reported when a secret was read from
+// the file named by its <KEY>_FILE variable.
+const sourceFile = "file"
+
 // newConfigCmd builds the `config` command group.
 func newConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -46,6 +52,8 @@ func newConfigShowCmd() *cobra.Command {
 	cmd.Flags().String(flagToken, "", "Jira API token")
 	cmd.Flags().String(flagUsername, "", "Jira username")
 	cmd.Flags().String(flagPassword, "", "Jira password")
+	cmd.Flags().Bool(flagSessionAuth, false, "Use session cookie login instead of Basic Auth")
+	addKerberosFlags(cmd)
 	return cmd
 }
 
@@ -62,6 +70,17 @@ func runConfigShow(cmd *cobra.Command) error {
 		fmt.Fprintf(w, "%s\t%s\t%s\n", field, redactIfSecret(field, value),
 			configSource(cmd, flagName, envKey, aliasEnv))
 	}
+	// secretRow is row for secrets that may also be read from <KEY>_FILE,
+	// which loadConfig consults after INPUT_<KEY>/<KEY> and before the alias.
+	secretRow := func(field, value, flagName, envKey, aliasEnv string) {
+		src := configSource(cmd, flagName, envKey, "")
+		if src == "default/unset" && util.GetGlobalValue(envKey+"_file") != "" {
+			src = sourceFile
+		} else if src == "default/unset" {
+			src = configSource(cmd, "", "", aliasEnv)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", field, redactIfSecret(field, value), src)
+	}
 	// oauthRow is row's sibling for fields that resolve env > flag (resolveWithEnv
 	// precedence): their SOURCE must be reported env-first via oauthValueSource, not
 	// the flag-first configSource — otherwise an env-overridden value is mislabelled
@@ -73,9 +92,20 @@ func runConfigShow(cmd *cobra.Command) error {
 
 	row("base_url", config.baseURL, flagBaseURL, "base_url", envBaseURL)
 	row("insecure", fmt.Sprintf("%t", config.insecure), flagInsecure, "insecure", envInsecure)
-	row("token", config.token, flagToken, "token", envToken)
+	row("proxy", redactURL(config.proxy), flagProxy, "proxy", "")
+	row("headers", config.headers, flagHeaders, "headers", "")
+	row("ca_cert", pemDisplay(config.caCert), flagCACert, "ca_cert", "")
+	row("tls_cert", pemDisplay(config.tlsCert), flagClientCert, "tls_cert", "")
+	secretRow("tls_key", pemDisplay(config.tlsKey), flagClientKey, "tls_key", "")
+	secretRow("token", config.token, flagToken, "token", envToken)
 	row("username", config.username, flagUsername, "username", envUsername)
-	row("password", config.password, flagPassword, "password", envPassword)
+	secretRow("password", config.password, flagPassword, "password", envPassword)
+	row("session_auth", fmt.Sprintf("%t", config.sessionAuth), flagSessionAuth, "session_auth", "")
+	row("krb5_keytab", config.krb5Keytab, flagKrb5Keytab, "krb5_keytab", "")
+	row("krb5_principal", config.krb5Principal, flagKrb5Principal, "krb5_principal", "")
+	row("krb5_ccache", config.krb5CCache, flagKrb5CCache, "krb5_ccache", "")
+	row("krb5_config", config.krb5Config, flagKrb5Config, "krb5_config", "")
+	row("krb5_spn", config.krb5SPN, flagKrb5SPN, "krb5_spn", "")
 	oauthRow("oauth_client_id", config.oauthClientID, flagClientID, envOAuthClientID,
 		DefaultOAuthClientID)
 	row("scope", config.scope, flagScope, "", "")
@@ -146,26 +176,29 @@ func configSource(cmd *cobra.Command, flagName, envKey, aliasEnv string) string
 	if envKey != "" && util.GetGlobalValue(envKey) != "" {
 		return sourceEnv
 	}
-	if aliasEnv != "" && os.Getenv(aliasEnv) != "" {
+	if aliasEnv != "" && util.GetGlobalValue(aliasEnv) != "" {
 		return sourceEnv
 	}
 	return "default/unset"
 }
 
 // detectAuthMode reports which auth mode run would select, mirroring
-// auth.Resolve's priority (oauth-env > oauth-storage > bearer > basic) and the
-// run path's gating: it only looks up stored OAuth tokens when no explicit
+// auth.Resolve's priority (oauth-env > oauth-storage > bearer > spnego >
+// session/basic) and the run path's gating: it only looks up stored OAuth tokens when no explicit
 // bearer/basic credential is set. That lookup can probe the OS keyring (a
 // write+delete, not strictly read-only) but performs no network I/O.
 func detectAuthMode(config Config) string {
-	hasExplicitCred := config.token != "" || (config.username != "" && config.password != "")
 	switch {
 	case config.oauthRefreshToken != "":
 		return auth.ModeOAuthEnv
-	case !hasExplicitCred && storedTokenExists(config):
+	case !config.hasExplicitCred() && storedTokenExists(config):
 		return auth.ModeOAuthStorage
 	case config.token != "":
 		return auth.ModeBearer
+	case config.krb5Keytab != "" || config.krb5CCache != "":
+		return auth.ModeSPNEGO
+	case config.username != "" && config.password != "" && config.sessionAuth:
+		return auth.ModeSession
 	case config.username != "" && config.password != "":
 		return auth.ModeBasic
 	default:
@@ -179,9 +212,27 @@ func redactIfSecret(field, value string) string {
 		return "(unset)"
 	}
 	switch field {
-	case flagToken, flagPassword, "oauth_refresh_token", "broker_token":
+	case flagToken, flagPassword, "oauth_refresh_token", "broker_token", flagHeaders:
 		return "(set, redacted)"
 	default:
 		return value
 	}
 }
+
+// pemDisplay shortens an inline PEM value to a placeholder so a multi-line
+// certificate does not break the table; file paths are shown as-is.
+func pemDisplay(v string) string {
+	if strings.Contains(v, pemBlockPrefix) {
+		return "(inline PEM)"
+	}
+	return v
+}
+
+// redactURL masks the password of a URL's userinfo (proxy credentials); values
+// that don't parse are shown unchanged.
+func redactURL(v string) string {
+	if u, err := url.Parse(v); err == nil {
+		return u.Redacted()
+	}
+	return v
+}
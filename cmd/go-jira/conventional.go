@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// commitHeaderPattern matches a Conventional Commits header such as
+// "feat(parser)!: add X" and captures the type. Only lowercase types match,
+// so capitalised trailers like "Refs: GAIA-1" are not mistaken for a header.
+var commitHeaderPattern = regexp.MustCompile(`^\s*([a-z]+)(?:\([^)]*\))?!?:\s`)
+
+// commitSection is the text of one commit within the ref: the header line
+// and everything up to the next header. kind is the commit type, or "" for
+// text before the first header.
+type commitSection struct {
+	kind string
+	text string
+}
+
+// splitCommits splits ref into sections at each Conventional Commits header,
+// which works for git log output, event payloads, and pasted messages alike.
+func splitCommits(ref string) []commitSection {
+	var sections []commitSection
+	cur := commitSection{}
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			cur.text = b.String()
+			sections = append(sections, cur)
+		}
+		b.Reset()
+	}
+	for line := range strings.Lines(ref) {
+		if m := commitHeaderPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			cur = commitSection{kind: m[1]}
+		}
+		b.WriteString(line)
+	}
+	flush()
+	return sections
+}
+
+// parseCommitTypes parses the comma-separated --commit-types filter into a
+// set of lowercase types. An empty set means every commit is acted on.
+func parseCommitTypes(s string) map[string]bool {
+	types := map[string]bool{}
+	for _, t := range splitCSV(s) {
+		types[strings.ToLower(t)] = true
+	}
+	return types
+}
+
+// parseTypeTransitions parses --commit-type-transitions: "type=Transition"
+// pairs separated by semicolons or newlines, e.g. "feat=In Review;fix=Done".
+func parseTypeTransitions(s string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, part := range splitRules(s) {
+		kind, transition, ok := strings.Cut(part, "=")
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		transition = strings.TrimSpace(transition)
+		if !ok || kind == "" || transition == "" {
+			return nil, fmt.Errorf("invalid commit type transition %q: want \"type=Transition\"", part)
+		}
+		out[kind] = transition
+	}
+	return out, nil
+}
+
+// issueCommitTypes maps every issue key in ref to the type of the first
+// commit referencing it. When allowed is non-empty, commits of other types
+// (and text outside any commit header) are ignored, so their keys are
+// absent from the result.
+func issueCommitTypes(config Config, allowed map[string]bool) (map[string]string, error) {
+	types := map[string]string{}
+	for _, text := range refTexts(config) {
+		for _, sec := range splitCommits(text) {
+			if len(allowed) > 0 && !allowed[sec.kind] {
+				continue
+			}
+			keys, err := scanKeys(sec.text, config)
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range keys {
+				if _, seen := types[k]; !seen {
+					types[k] = sec.kind
+				}
+			}
+		}
+	}
+	return types, nil
+}
+
+// filterCommitTypes drops the keys that only appear in commits whose type is
+// not in --commit-types, e.g. so a chore commit referencing an issue does not
+// close it. Keys keep their order; without a filter they are returned as-is.
+func filterCommitTypes(keys []string, config Config) ([]string, error) {
+	allowed := parseCommitTypes(config.commitTypes)
+	if len(allowed) == 0 {
+		return keys, nil
+	}
+	types, err := issueCommitTypes(config, allowed)
+	if err != nil {
+		return nil, err
+	}
+	kept := keys[:0:0]
+	for _, k := range keys {
+		if _, ok := types[k]; ok {
+			kept = append(kept, k)
+		}
+	}
+	return kept, nil
+}
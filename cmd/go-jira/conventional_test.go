@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const conventionalLog = `feat(parser)!: add streaming GAIA-1
+
+Refs: GAIA-2
+fix: handle empty input GAIA-3
+chore: bump deps for GAIA-4 and GAIA-1
+Merge branch 'GAIA-5-cleanup'
+`
+
+func TestSplitCommits(t *testing.T) {
+	var kinds []string
+	for _, s := range splitCommits("preamble GAIA-9\n" + conventionalLog) {
+		kinds = append(kinds, s.kind)
+	}
+	// The capitalised "Refs:" trailer stays inside the feat commit.
+	if got := strings.Join(kinds, ","); got != ",feat,fix,chore" {
+		t.Errorf("kinds = %q", got)
+	}
+}
+
+func TestFilterCommitTypes(t *testing.T) {
+	keys, err := getIssueKeys(conventionalLog, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		types string
+		want  string
+	}{
+		{"", "GAIA-1,GAIA-2,GAIA-3,GAIA-4,GAIA-5"},
+		{"fix,feat", "GAIA-1,GAIA-2,GAIA-3"},
+		{" FIX ", "GAIA-3"},
+		{"chore", "GAIA-1,GAIA-4,GAIA-5"},
+	}
+	for _, tt := range tests {
+		got, err := filterCommitTypes(keys, Config{ref: conventionalLog, commitTypes: tt.types})
+		if err != nil {
+			t.Fatalf("%q: %v", tt.types, err)
+		}
+		if strings.Join(got, ",") != tt.want {
+			t.Errorf("commitTypes %q: keys = %v, want %s", tt.types, got, tt.want)
+		}
+	}
+}
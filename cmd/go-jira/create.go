@@ -35,6 +35,7 @@ func newCreateCmd() *cobra.Command {
 	addCommonFlags(cmd)
 	addOAuthFlags(cmd)
 	addAuthFlags(cmd)
+	addAuditLogFlag(cmd)
 	addOutputFlag(cmd)
 	addCustomFieldFlags(cmd)
 	addEditableIssueFlags(cmd)
@@ -95,10 +96,11 @@ func runCreate(cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
+	adaptFieldsForProject(ctx, jiraClient, config, project, fields.Unknowns)
 
 	created, resp, err := jiraClient.Issue.CreateWithContext(ctx, &jira.Issue{Fields: fields})
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return fmt.Errorf("error creating issue: %w", err)
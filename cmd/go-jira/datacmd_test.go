@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -502,3 +505,190 @@ func TestLinkCmd(t *testing.T) {
 		t.Errorf("link request body unexpected: %+v", gotBody)
 	}
 }
+
+func TestCreateCmdTeamManagedProject(t *testing.T) {
+	var gotBody map[string]any
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/rest/api/2/project/TEAM":
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"key": "TEAM", "style": "next-gen", "simplified": true,
+				})
+			case r.URL.Path == "/rest/api/2/field":
+				_ = json.NewEncoder(w).Encode([]map[string]any{
+					{"id": "summary", "schema": map[string]string{"system": "summary"}},
+					{"id": "customfield_10020", "schema": map[string]string{
+						"custom": "com.pyxis.greenhopper.jira:gh-sprint",
+					}},
+				})
+			case r.Method == http.MethodPost:
+				_ = json.NewDecoder(r.Body).Decode(&gotBody)
+				w.WriteHeader(http.StatusCreated)
+				_ = json.NewEncoder(w).Encode(map[string]string{"key": "TEAM-3"})
+			default:
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+		}),
+	)
+	defer server.Close()
+
+	_, err := runDataCmd(t, newCreateCmd(), server.URL,
+		"--project", "TEAM", "--summary", "s", "--epic", "TEAM-1", "--sprint", "7")
+	if err != nil {
+		t.Fatalf("create returned error: %v", err)
+	}
+	fields := gotBody["fields"].(map[string]any)
+	if p, _ := fields["parent"].(map[string]any); p == nil || p["key"] != "TEAM-1" {
+		t.Errorf("parent = %v, want key TEAM-1", fields["parent"])
+	}
+	if _, exists := fields["customfield_10101"]; exists {
+		t.Errorf("epic link field should not be sent for team-managed projects: %v", fields)
+	}
+	if sprint, _ := fields["customfield_10020"].(float64); sprint != 7 {
+		t.Errorf("discovered sprint field = %v, want 7", fields["customfield_10020"])
+	}
+	if _, exists := fields["customfield_10100"]; exists {
+		t.Errorf("default sprint field should be replaced: %v", fields)
+	}
+}
+
+func TestSearchAllPaginates(t *testing.T) {
+	const total = 130
+	var starts []string
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			starts = append(starts, q.Get("startAt"))
+			start, _ := strconv.Atoi(q.Get("startAt"))
+			// Like Jira Cloud, clamp every page to 50 issues whatever was asked.
+			end := min(start+50, total)
+			issues := []jira.Issue{}
+			for i := start; i < end; i++ {
+				issues = append(issues, jira.Issue{Key: fmt.Sprintf("GAIA-%d", i+1)})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"startAt": start, "maxResults": 50, "total": total, "issues": issues,
+			})
+		}),
+	)
+	defer server.Close()
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		limit      int
+		want       int
+		wantStarts string
+	}{
+		{limit: 0, want: total, wantStarts: ",50,100"},
+		{limit: 120, want: 120, wantStarts: ",50,100"},
+		{limit: 20, want: 20, wantStarts: ""},
+	}
+	for _, tt := range tests {
+		starts = nil
+		issues, err := searchAll(context.Background(), jiraClient, "project = GAIA", nil, tt.limit)
+		if err != nil {
+			t.Fatalf("limit %d: searchAll: %v", tt.limit, err)
+		}
+		if len(issues) != tt.want {
+			t.Errorf("limit %d: got %d issues, want %d", tt.limit, len(issues), tt.want)
+		}
+		if got := strings.Join(starts, ","); got != tt.wantStarts {
+			t.Errorf("limit %d: startAt sequence = %q, want %q", tt.limit, got, tt.wantStarts)
+		}
+	}
+}
+
+func TestTransitionsCmd(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/rest/api/2/issue/GAIA-1/transitions" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(`{"transitions":[
+				{"id":"11","name":"Start Progress","to":{"name":"In Progress"}},
+				{"id":"31","name":"Resolve","to":{"name":"Resolved"},
+				 "fields":{"resolution":{"required":true},"comment":{"required":false}}}]}`))
+		}),
+	)
+	defer server.Close()
+
+	out, err := runDataCmd(t, newTransitionsCmd(), server.URL, "GAIA-1")
+	if err != nil {
+		t.Fatalf("transitions returned error: %v", err)
+	}
+	var got []transitionInfo
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out)
+	}
+	if len(got) != 2 || got[1].To != "Resolved" ||
+		strings.Join(got[1].RequiredFields, ",") != "resolution" {
+		t.Errorf("transitions = %+v", got)
+	}
+
+	out, err = runDataCmd(t, newTransitionsCmd(), server.URL, "--key", "GAIA-1", "--output", "text")
+	if err != nil {
+		t.Fatalf("transitions text returned error: %v", err)
+	}
+	if !strings.Contains(out, "31\tResolve\t→ Resolved\t(requires resolution)") {
+		t.Errorf("text output = %q", out)
+	}
+
+	if _, err := runDataCmd(t, newTransitionsCmd(), server.URL); err == nil {
+		t.Error("expected an error without an issue key")
+	}
+}
+
+func TestMetaCmd(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/rest/api/2/resolution":
+				_, _ = w.Write([]byte(`[{"id":"1","name":"Erledigt","description":"Fertig."}]`))
+			case "/rest/api/2/status":
+				_, _ = w.Write([]byte(`[{"id":"3","name":"In Arbeit",
+					"statusCategory":{"name":"In Progress"}}]`))
+			case "/rest/api/2/issueLinkType":
+				_, _ = w.Write([]byte(`{"issueLinkTypes":[
+					{"id":"10000","name":"Blocks","inward":"is blocked by","outward":"blocks"}]}`))
+			default:
+				http.NotFound(w, r)
+			}
+		}),
+	)
+	defer server.Close()
+
+	out, err := runDataCmd(t, newMetaListCmd("resolutions", "", fetchResolutions), server.URL)
+	if err != nil {
+		t.Fatalf("meta resolutions returned error: %v", err)
+	}
+	var got []metaItem
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out)
+	}
+	if len(got) != 1 || got[0].Name != "Erledigt" || got[0].Description != "Fertig." {
+		t.Errorf("resolutions = %+v", got)
+	}
+
+	out, err = runDataCmd(t, newMetaListCmd("statuses", "", fetchStatuses), server.URL,
+		"--output", "text")
+	if err != nil {
+		t.Fatalf("meta statuses returned error: %v", err)
+	}
+	if out != "3\tIn Arbeit\tIn Progress\n" {
+		t.Errorf("statuses text = %q", out)
+	}
+
+	out, err = runDataCmd(t, newMetaListCmd("link-types", "", fetchLinkTypes), server.URL,
+		"--output", "text")
+	if err != nil {
+		t.Fatalf("meta link-types returned error: %v", err)
+	}
+	if out != "10000\tBlocks\tblocks / is blocked by\n" {
+		t.Errorf("link-types text = %q", out)
+	}
+}
@@ -29,6 +29,7 @@ func newDeleteCmd() *cobra.Command {
 	addCommonFlags(cmd)
 	addOAuthFlags(cmd)
 	addAuthFlags(cmd)
+	addAuditLogFlag(cmd)
 	addOutputFlag(cmd)
 	cmd.Flags().String(flagKey, "", "Issue key to delete, e.g. GAIA-123 (required)")
 	_ = cmd.MarkFlagRequired(flagKey)
@@ -73,7 +74,7 @@ func runDelete(cmd *cobra.Command) error {
 	}
 	resp, err := jiraClient.Do(req, nil)
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return fmt.Errorf("error deleting issue %s: %w", key, err)
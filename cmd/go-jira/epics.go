@@ -63,7 +63,7 @@ func runEpics(cmd *cobra.Command) error {
 			SearchOptions: jira.SearchOptions{MaxResults: limit},
 		})
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return fmt.Errorf("error listing epics for board %d: %w", boardID, err)
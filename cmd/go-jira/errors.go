@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/appleboy/go-jira/pkg/jiraops"
 	"github.com/appleboy/go-jira/pkg/oauth"
+	"github.com/appleboy/go-jira/pkg/util"
 
 	"github.com/spf13/cobra"
 )
@@ -33,6 +37,23 @@ const (
 	kindRateLimit = "rate_limit"
 )
 
+// Sentinel errors for callers that branch on a failure with errors.Is instead
+// of matching messages. API failures on an issue are wrapped with
+// ErrIssueNotFound or ErrUnauthorized by jiraops.APIError; the issue
+// sentinels are the library's, so either package's name matches.
+var (
+	// ErrIssueNotFound reports that Jira answered 404 for an issue.
+	ErrIssueNotFound = jiraops.ErrIssueNotFound
+	// ErrTransitionNotFound reports that the requested transition is not
+	// available from the issue's current status.
+	ErrTransitionNotFound = jiraops.ErrTransitionNotFound
+	// ErrUnauthorized reports that Jira rejected the credentials (401) or the
+	// account's permission (403).
+	ErrUnauthorized = jiraops.ErrUnauthorized
+	// ErrNoIssueKeys reports that the ref references no issue keys.
+	ErrNoIssueKeys = errors.New("no issue keys found")
+)
+
 // cliError carries a classified failure: the process exit code, a stable
 // machine-readable kind, and optional HTTP diagnostics surfaced for rate-limit
 // and auth failures. It is what main turns into a structured stderr payload.
@@ -49,6 +70,30 @@ type cliError struct {
 func (e *cliError) Error() string { return e.message }
 func (e *cliError) Unwrap() error { return e.err }
 
+// issueErrors returns every *jiraops.IssueError in err's tree, in order,
+// looking through both wrapped and joined errors. The per-issue phases join
+// one per failed issue, so the returned error keeps every issue's cause, not
+// just a count.
+func issueErrors(err error) []*jiraops.IssueError {
+	var out []*jiraops.IssueError
+	var walk func(error)
+	walk = func(err error) {
+		switch e := err.(type) { //nolint:errorlint // walking the tree by hand
+		case nil:
+		case *jiraops.IssueError:
+			out = append(out, e)
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+	return out
+}
+
 // requestDiag records the most recent error-status HTTP response seen by the
 // diagTransport during a single CLI invocation. The failing call is the last
 // one made (errors propagate immediately), so the last recorded value is the
@@ -90,15 +135,22 @@ func diagFrom(ctx context.Context) *requestDiag {
 }
 
 // diagTransport is a RoundTripper that records the status and Retry-After of any
-// 4xx/5xx response into the requestDiag carried by the request context. It only
-// reads headers (never the body), so it is safe to layer beneath the Jira
-// client, which still consumes the body to build its own error.
+// 4xx/5xx response into the requestDiag carried by the request context, and
+// logs every request at debug level. It only reads headers (never the body),
+// so it is safe to layer beneath the Jira client, which still consumes the
+// body to build its own error.
 type diagTransport struct {
 	base http.RoundTripper
 }
 
 func (t *diagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
 	resp, err := t.base.RoundTrip(req)
+	if resp != nil {
+		slog.DebugContext(req.Context(), "jira request",
+			"method", req.Method, "path", req.URL.Path,
+			statusKey, resp.StatusCode, logKeyDuration, time.Since(start))
+	}
 	if resp != nil && resp.StatusCode >= http.StatusBadRequest {
 		if d := diagFrom(req.Context()); d != nil {
 			d.record(resp.StatusCode, resp.Header.Get("Retry-After"))
@@ -155,6 +207,7 @@ func classify(err error, diag *requestDiag) *cliError {
 	// a refresh call with no HTTP diagnostics and no "auth ..." message prefix, so
 	// match them by error identity to keep all auth classification here.
 	if isAuthError(msg) ||
+		errors.Is(err, ErrUnauthorized) ||
 		errors.Is(err, oauth.ErrInvalidGrant) ||
 		errors.Is(err, oauth.ErrBrokerUnauthorized) {
 		return &cliError{code: exitAuth, kind: kindAuth, message: msg, err: err}
@@ -203,21 +256,40 @@ type errorPayload struct {
 	ExitCode   int    `json:"exit_code"`
 	StatusCode int    `json:"status_code,omitempty"`
 	RetryAfter string `json:"retry_after,omitempty"`
+	// Issues lists each failed issue operation behind an aggregated error.
+	Issues []issueErrorPayload `json:"issues,omitempty"`
+}
+
+type issueErrorPayload struct {
+	Issue     string `json:"issue"`
+	Operation string `json:"operation"`
+	Message   string `json:"message"`
 }
 
 // emitError writes a single structured JSON error object to stderr so agents can
 // classify a failure without scraping log lines. Rate-limit and auth failures
-// include the HTTP status and any Retry-After hint.
+// include the HTTP status and any Retry-After hint, and aggregated per-issue
+// failures list each issue, operation, and cause. Secrets are masked, since
+// the message often wraps a server response or a request URL.
 func emitError(ce *cliError) {
+	var issues []issueErrorPayload
+	for _, ie := range issueErrors(ce.err) {
+		issues = append(issues, issueErrorPayload{
+			Issue:     ie.Key,
+			Operation: ie.Action,
+			Message:   util.Redact(ie.Err.Error()),
+		})
+	}
 	enc := json.NewEncoder(os.Stderr)
 	enc.SetIndent("", "  ")
 	_ = enc.Encode(errorEnvelope{Error: errorPayload{
 		Kind:       ce.kind,
-		Message:    ce.message,
-		Hint:       ce.hint,
+		Message:    util.Redact(ce.message),
+		Hint:       util.Redact(ce.hint),
 		ExitCode:   ce.code,
 		StatusCode: ce.statusCode,
 		RetryAfter: ce.retryAfter,
+		Issues:     issues,
 	}})
 }
 
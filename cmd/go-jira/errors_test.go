@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -9,7 +10,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/appleboy/go-jira/pkg/jiraops"
+	"github.com/appleboy/go-jira/pkg/jiratest"
 	"github.com/appleboy/go-jira/pkg/oauth"
+
+	jira "github.com/andygrunwald/go-jira"
 )
 
 func TestClassifyPrefersExistingCLIError(t *testing.T) {
@@ -258,3 +263,79 @@ func TestDiagTransportRecordsErrorStatus(t *testing.T) {
 		t.Fatalf("got status=%d retry=%q, want 429/42", sc, retryAfter)
 	}
 }
+
+func TestPerIssueErrorsAreJoinedWithDetail(t *testing.T) {
+	issues := []*jira.Issue{{Key: "GAIA-1"}, {Key: "GAIA-2"}, {Key: "GAIA-3"}}
+	server := jiratest.NewServer(jiratest.Config{Failures: []jiratest.Failure{
+		{Endpoint: jiratest.EndpointTransition, Key: "GAIA-1", Message: "boom GAIA-1"},
+		{Endpoint: jiratest.EndpointTransition, Key: "GAIA-3", Message: "boom GAIA-3"},
+	}})
+	defer server.Close()
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iss := range issues {
+		iss.Transitions = []jira.Transition{{ID: "1", Name: "Done"}}
+	}
+	ctx := context.Background()
+	err = processTransitions(ctx, newProcessor(ctx, jiraClient, Config{}), "Done", "", issues)
+	err = fmt.Errorf("error processing transitions: %w", err)
+
+	got := issueErrors(err)
+	if len(got) != 2 || got[0].Key != "GAIA-1" || got[1].Key != "GAIA-3" {
+		t.Fatalf("issueErrors = %+v, want the two failed issues in order", got)
+	}
+	if want := "GAIA-1 transition: "; !strings.Contains(err.Error(), want) ||
+		!strings.Contains(err.Error(), "boom GAIA-1") {
+		t.Errorf("error %q does not carry the cause of GAIA-1", err)
+	}
+
+	out := captureStderr(t, func() { emitError(classify(err, nil)) })
+	var env struct {
+		Error errorPayload `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(out), &env); err != nil {
+		t.Fatalf("decode payload %q: %v", out, err)
+	}
+	if len(env.Error.Issues) != 2 || env.Error.Issues[1].Issue != "GAIA-3" ||
+		env.Error.Issues[1].Operation != actionTransition ||
+		!strings.Contains(env.Error.Issues[1].Message, "boom GAIA-3") {
+		t.Errorf("payload issues = %+v", env.Error.Issues)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	cause := errors.New("404 Not Found: issue does not exist")
+	for _, tt := range []struct {
+		status int
+		want   error
+	}{
+		{http.StatusNotFound, ErrIssueNotFound},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+	} {
+		resp := &jira.Response{Response: &http.Response{StatusCode: tt.status}}
+		err := fmt.Errorf("transition: %w", jiraops.APIError(resp, cause))
+		if !errors.Is(err, tt.want) || !errors.Is(err, cause) {
+			t.Errorf("status %d: %v should match %v and its cause", tt.status, err, tt.want)
+		}
+		if err.Error() != "transition: "+cause.Error() {
+			t.Errorf("status %d: message changed to %q", tt.status, err)
+		}
+	}
+	resp := &jira.Response{Response: &http.Response{StatusCode: http.StatusInternalServerError}}
+	if err := jiraops.APIError(resp, cause); err != cause { //nolint:errorlint // identity check
+		t.Errorf("500 should be returned unchanged, got %v", err)
+	}
+
+	if ce := classify(jiraops.APIError(&jira.Response{Response: &http.Response{StatusCode: http.StatusForbidden}},
+		errors.New("forbidden")), nil); ce.code != exitAuth {
+		t.Errorf("ErrUnauthorized exit code = %d, want %d", ce.code, exitAuth)
+	}
+
+	err := jiraops.TransitionNotFoundError("Done", []jira.Transition{{Name: "In Progress"}})
+	if !errors.Is(err, ErrTransitionNotFound) {
+		t.Errorf("%v should match ErrTransitionNotFound", err)
+	}
+}
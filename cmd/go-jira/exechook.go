@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// Phases of the exec hooks, passed in hookInput.Phase and GO_JIRA_HOOK.
+const (
+	hookPre  = "pre"
+	hookPost = "post"
+)
+
+// hookIssue is one fetched issue as an exec hook sees it.
+type hookIssue struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+// hookInput is the JSON an exec hook reads on stdin. The pre hook gets the
+// fetched issues and what run is about to do with them; the post hook gets
+// the run report, the same document --report-file writes.
+type hookInput struct {
+	Phase      string          `json:"phase"`
+	Keys       []string        `json:"keys"`
+	Issues     []hookIssue     `json:"issues,omitempty"`
+	Transition string          `json:"transition,omitempty"`
+	Resolution string          `json:"resolution,omitempty"`
+	Assignee   string          `json:"assignee,omitempty"`
+	Comment    string          `json:"comment,omitempty"`
+	Report     *reportDocument `json:"report,omitempty"`
+}
+
+// runExecHook runs command through the shell with input as JSON on stdin and
+// GO_JIRA_HOOK set to the phase. Its stdout and stderr both go to stderr so
+// run's own output stays machine-readable. A non-zero exit is an error.
+func runExecHook(ctx context.Context, command string, input hookInput) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	// #nosec G204 -- the hook command is the operator's own configuration.
+	c := exec.CommandContext(ctx, shell, flag, command)
+	c.Stdin = bytes.NewReader(data)
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(), "GO_JIRA_HOOK="+input.Phase)
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%s_hook: %w", input.Phase, err)
+	}
+	return nil
+}
+
+// runPreHook runs the pre hook, if configured, once the issues are fetched
+// and before anything changes, so a failing hook aborts the run untouched.
+// Keys are all the keys extracted from the ref, issues the ones to act on.
+func runPreHook(ctx context.Context, config Config, r *runReport, issues []*jira.Issue) error {
+	if config.preHook == "" {
+		return nil
+	}
+	r.mu.Lock()
+	keys := append([]string{}, r.keys...)
+	r.mu.Unlock()
+	input := hookInput{
+		Phase:      hookPre,
+		Keys:       keys,
+		Issues:     make([]hookIssue, 0, len(issues)),
+		Transition: config.toTransition,
+		Resolution: config.resolution,
+		Assignee:   config.assignee,
+		Comment:    config.comment,
+	}
+	for _, iss := range issues {
+		hi := hookIssue{Key: iss.Key}
+		if f := iss.Fields; f != nil {
+			hi.Summary = f.Summary
+			if f.Status != nil {
+				hi.Status = f.Status.Name
+			}
+			hi.Type = f.Type.Name
+		}
+		input.Issues = append(input.Issues, hi)
+	}
+	return runExecHook(ctx, config.preHook, input)
+}
+
+// runPostHook runs the post hook, if configured, with the run report,
+// including the error run returns, so it can notify on failures too.
+func runPostHook(ctx context.Context, config Config, r *runReport, runErr error) error {
+	if config.postHook == "" {
+		return nil
+	}
+	doc := r.document(runErr)
+	return runExecHook(ctx, config.postHook, hookInput{
+		Phase:  hookPost,
+		Keys:   doc.Keys,
+		Report: &doc,
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestRunPreHookInput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook command uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "input.json")
+	t.Setenv("HOOK_OUT", out)
+
+	report := newRunReport("https://jira.example.com")
+	report.setKeys([]string{"GAIA-1", "GAIA-2"})
+	config := Config{
+		preHook:      `cat > "$HOOK_OUT" && test "$GO_JIRA_HOOK" = pre`,
+		toTransition: "Done",
+	}
+	issues := []*jira.Issue{{Key: "GAIA-1", Fields: &jira.IssueFields{
+		Summary: "Fix login",
+		Status:  &jira.Status{Name: "In Progress"},
+		Type:    jira.IssueType{Name: "Bug"},
+	}}}
+	if err := runPreHook(context.Background(), config, report, issues); err != nil {
+		t.Fatalf("runPreHook: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got hookInput
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("hook stdin is not JSON: %v\n%s", err, data)
+	}
+	want := hookIssue{Key: "GAIA-1", Summary: "Fix login", Status: "In Progress", Type: "Bug"}
+	if got.Phase != hookPre || len(got.Keys) != 2 || got.Transition != "Done" ||
+		len(got.Issues) != 1 || got.Issues[0] != want {
+		t.Errorf("hook input = %+v", got)
+	}
+}
+
+func TestRunPostHookReport(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook command uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "input.json")
+	t.Setenv("HOOK_OUT", out)
+
+	report := newRunReport("")
+	report.setKeys([]string{"GAIA-1"})
+	report.record("GAIA-1", actionComment, outcomeFailed, "boom")
+	config := Config{postHook: `cat > "$HOOK_OUT"`}
+	if err := runPostHook(context.Background(), config, report, errors.New("run failed")); err != nil {
+		t.Fatalf("runPostHook: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got hookInput
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Phase != hookPost || got.Report == nil || got.Report.Failed != 1 ||
+		got.Report.Error != "run failed" {
+		t.Errorf("hook input = %s", data)
+	}
+}
+
+func TestRunExecHookUnset(t *testing.T) {
+	if err := runPreHook(context.Background(), Config{}, newRunReport(""), nil); err != nil {
+		t.Errorf("unset pre hook: %v", err)
+	}
+	if err := runPostHook(context.Background(), Config{}, newRunReport(""), nil); err != nil {
+		t.Errorf("unset post hook: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Failure policies for per-issue errors during run, set with --fail-mode.
+const (
+	failModeFail      = "fail"
+	failModeWarn      = "warn"
+	failModeThreshold = "threshold"
+)
+
+// failPolicy decides whether per-issue failures fail the run. fail (the
+// default) stops at the first phase with a failed issue, warn logs failures
+// and always succeeds, and threshold:<n> continues while at most n issues
+// have failed.
+type failPolicy struct {
+	mode string
+	max  int
+}
+
+// parseFailMode parses a --fail-mode value; empty selects fail.
+func parseFailMode(s string) (failPolicy, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", failModeFail:
+		return failPolicy{mode: failModeFail}, nil
+	case failModeWarn:
+		return failPolicy{mode: failModeWarn}, nil
+	}
+	if rest, ok := strings.CutPrefix(s, failModeThreshold+":"); ok {
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 0 {
+			return failPolicy{}, fmt.Errorf(
+				"fail_mode %q: threshold must be a non-negative integer", s)
+		}
+		return failPolicy{mode: failModeThreshold, max: n}, nil
+	}
+	return failPolicy{}, fmt.Errorf(
+		"fail_mode must be fail, warn, or threshold:<n>, got %q", s)
+}
+
+func (p failPolicy) String() string {
+	if p.mode == failModeThreshold {
+		return fmt.Sprintf("%s:%d", p.mode, p.max)
+	}
+	return p.mode
+}
+
+// apply returns the error that should end the run, given err from a
+// per-issue phase (nil once all phases are done) and the failures recorded
+// in r so far. A tolerated error is logged and nil is returned so the
+// remaining phases still run.
+func (p failPolicy) apply(r *runReport, err error) error {
+	failed := r.failedCount()
+	switch p.mode {
+	case failModeWarn:
+	case failModeThreshold:
+		if failed > p.max {
+			if err == nil {
+				err = fmt.Errorf("%d issues failed", failed)
+			}
+			return fmt.Errorf("%d failed issues exceed the fail_mode threshold of %d: %w",
+				failed, p.max, err)
+		}
+	default:
+		return err
+	}
+	if err != nil {
+		slog.Warn("continuing despite failed issues",
+			"fail_mode", p.String(), "failed", failed, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseFailMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "fail", false},
+		{"fail", "fail", false},
+		{"WARN", "warn", false},
+		{"threshold:3", "threshold:3", false},
+		{" threshold:0 ", "threshold:0", false},
+		{"threshold:", "", true},
+		{"threshold:-1", "", true},
+		{"threshold:x", "", true},
+		{"ignore", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			p, err := parseFailMode(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFailMode(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && p.String() != tt.want {
+				t.Errorf("parseFailMode(%q) = %s, want %s", tt.in, p, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailPolicyApply(t *testing.T) {
+	r := newRunReport("")
+	r.setKeys([]string{"GAIA-1", "GAIA-2", "GAIA-3"})
+	r.record("GAIA-1", actionTransition, outcomeOK, "")
+	r.record("GAIA-2", actionTransition, outcomeFailed, "403")
+	r.record("GAIA-3", actionFetch, outcomeFailed, "404")
+	phaseErr := errors.New("encountered 1 errors while processing transitions")
+
+	tests := []struct {
+		mode    string
+		err     error
+		wantErr string
+	}{
+		{"fail", phaseErr, "encountered 1 errors"},
+		{"fail", nil, ""},
+		{"warn", phaseErr, ""},
+		{"threshold:2", phaseErr, ""},
+		{"threshold:1", phaseErr, "2 failed issues exceed the fail_mode threshold of 1"},
+		// The final check (no phase error) still counts fetch failures.
+		{"threshold:1", nil, "2 issues failed"},
+	}
+	for _, tt := range tests {
+		p, err := parseFailMode(tt.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := p.apply(r, tt.err)
+		if tt.wantErr == "" {
+			if got != nil {
+				t.Errorf("%s: unexpected error %v", tt.mode, got)
+			}
+			continue
+		}
+		if got == nil || !strings.Contains(got.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want containing %q", tt.mode, got, tt.wantErr)
+		}
+	}
+}
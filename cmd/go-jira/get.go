@@ -54,7 +54,7 @@ func runGet(cmd *cobra.Command) error {
 		Fields: "summary,status",
 	})
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return fmt.Errorf("error getting issue %s: %w", key, err)
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/appleboy/go-jira/pkg/util"
+)
+
+// GitHub API settings read from the Actions environment.
+const (
+	envGitHubToken  = "GITHUB_TOKEN"
+	envGitHubAPIURL = "GITHUB_API_URL"
+
+	defaultGitHubAPIURL = "https://api.github.com"
+	// githubCommitsPerPage is the API maximum; the pull request commits
+	// endpoint returns at most 250 commits in total.
+	githubCommitsPerPage = 100
+)
+
+// githubClient reads pull requests through the GitHub REST API.
+type githubClient struct {
+	apiURL string
+	repo   string // owner/name
+	token  string
+	http   *http.Client
+}
+
+// newGitHubClient builds a client from GITHUB_API_URL (for GitHub Enterprise
+// Server), GITHUB_REPOSITORY, and GITHUB_TOKEN. The token is optional for
+// public repositories but lifts the anonymous rate limit.
+func newGitHubClient() (*githubClient, error) {
+	repo := os.Getenv(envGitHubRepository)
+	if repo == "" {
+		return nil, fmt.Errorf("pr_number requires %s (owner/name)", envGitHubRepository)
+	}
+	apiURL := os.Getenv(envGitHubAPIURL)
+	if apiURL == "" {
+		apiURL = defaultGitHubAPIURL
+	}
+	token := os.Getenv(envGitHubToken)
+	util.Mask(token)
+	return &githubClient{
+		apiURL: strings.TrimRight(apiURL, "/"),
+		repo:   repo,
+		token:  token,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// get decodes the JSON response of GET path into out.
+func (c *githubClient) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", userAgent())
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("github api: %w", err)
+	}
+	defer drainBody(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("github api: GET %s: %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("github api: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// pullRequestText returns the pull request's title, body, and every commit
+// message, one after another, for issue key extraction.
+func (c *githubClient) pullRequestText(ctx context.Context, number int) (string, error) {
+	base := fmt.Sprintf("/repos/%s/pulls/%d", c.repo, number)
+	var pr struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	if err := c.get(ctx, base, &pr); err != nil {
+		return "", err
+	}
+	parts := []string{pr.Title, pr.Body}
+	for page := 1; ; page++ {
+		var commits []struct {
+			Commit struct {
+				Message string `json:"message"`
+			} `json:"commit"`
+		}
+		path := fmt.Sprintf("%s/commits?per_page=%d&page=%d", base, githubCommitsPerPage, page)
+		if err := c.get(ctx, path, &commits); err != nil {
+			return "", err
+		}
+		for _, cm := range commits {
+			parts = append(parts, cm.Commit.Message)
+		}
+		if len(commits) < githubCommitsPerPage {
+			break
+		}
+	}
+	return joinNonEmpty(parts...), nil
+}
+
+// appendPullRequest returns ref extended with the text of pull request number
+// fetched from the GitHub API, for pull_request_target and merge-queue
+// workflows whose checkout or event payload lacks it. A number of 0 returns
+// ref unchanged.
+func appendPullRequest(ctx context.Context, ref string, number int) (string, error) {
+	if number == 0 {
+		return ref, nil
+	}
+	if number < 0 {
+		return "", fmt.Errorf("pr_number must be positive, got %d", number)
+	}
+	c, err := newGitHubClient()
+	if err != nil {
+		return "", err
+	}
+	text, err := c.pullRequestText(ctx, number)
+	if err != nil {
+		return "", err
+	}
+	return joinNonEmpty(ref, text), nil
+}
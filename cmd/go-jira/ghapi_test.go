@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAppendPullRequest(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		switch {
+		case r.URL.Path == "/repos/acme/app/pulls/7":
+			_, _ = w.Write([]byte(`{"title":"GAIA-1 add parser","body":"Closes GAIA-2"}`))
+		case r.URL.Path == "/repos/acme/app/pulls/7/commits":
+			// A full first page forces a request for the second.
+			var commits []map[string]any
+			if r.URL.Query().Get("page") == "1" {
+				for i := range githubCommitsPerPage {
+					commits = append(commits, map[string]any{
+						"commit": map[string]string{"message": fmt.Sprintf("chore %d", i)},
+					})
+				}
+				commits[0]["commit"] = map[string]string{"message": "GAIA-3 first"}
+			} else {
+				commits = append(commits, map[string]any{
+					"commit": map[string]string{"message": "GAIA-4 last"},
+				})
+			}
+			_ = json.NewEncoder(w).Encode(commits)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv(envGitHubAPIURL, srv.URL)
+	t.Setenv(envGitHubRepository, "acme/app")
+	t.Setenv(envGitHubToken, "ghs_testtoken")
+
+	got, err := appendPullRequest(context.Background(), "GAIA-9", 7)
+	if err != nil {
+		t.Fatalf("appendPullRequest: %v", err)
+	}
+	keys, _ := getIssueKeys(got, "")
+	if strings.Join(keys, ",") != "GAIA-9,GAIA-1,GAIA-2,GAIA-3,GAIA-4" {
+		t.Errorf("keys = %v", keys)
+	}
+	if gotAuth != "Bearer ghs_testtoken" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+
+	if _, err := appendPullRequest(context.Background(), "", 8); err == nil ||
+		!strings.Contains(err.Error(), "404") {
+		t.Errorf("missing PR: error = %v", err)
+	}
+	if got, _ := appendPullRequest(context.Background(), "GAIA-9", 0); got != "GAIA-9" {
+		t.Errorf("no PR number: got %q", got)
+	}
+
+	t.Setenv(envGitHubRepository, "")
+	if _, err := appendPullRequest(context.Background(), "", 7); err == nil {
+		t.Error("expected an error without GITHUB_REPOSITORY")
+	}
+}
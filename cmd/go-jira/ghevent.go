@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// The webhook payload that triggered a GitHub Actions run, and its event name.
+const (
+	envGitHubEventPath = "GITHUB_EVENT_PATH"
+	envGitHubEventName = "GITHUB_EVENT_NAME"
+)
+
+// githubEvent is the subset of the push and pull_request payloads that can
+// reference issues.
+type githubEvent struct {
+	Commits []struct {
+		Message string `json:"message"`
+	} `json:"commits"`
+	HeadCommit *struct {
+		Message string `json:"message"`
+	} `json:"head_commit"`
+	PullRequest *struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	} `json:"pull_request"`
+}
+
+// eventRef returns the text of the event payload at path worth scanning for
+// issue keys: the pull request title and body, and every pushed commit
+// message (the head commit when the payload lists none). Other event types
+// yield "".
+func eventRef(path string) (string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from the runner
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", envGitHubEventPath, err)
+	}
+	var ev githubEvent
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return "", fmt.Errorf("parse %s: %w", envGitHubEventPath, err)
+	}
+	var parts []string
+	if pr := ev.PullRequest; pr != nil {
+		parts = append(parts, pr.Title, pr.Body)
+	}
+	for _, c := range ev.Commits {
+		parts = append(parts, c.Message)
+	}
+	if len(ev.Commits) == 0 && ev.HeadCommit != nil {
+		parts = append(parts, ev.HeadCommit.Message)
+	}
+	return joinNonEmpty(parts...), nil
+}
+
+// refFromEvent falls back to the GitHub event payload when no ref source is
+// configured, so the action works without INPUT_REF. An explicit ref always
+// wins, and outside GitHub Actions ref is returned unchanged.
+func refFromEvent(ref string) (string, error) {
+	path := os.Getenv(envGitHubEventPath)
+	if ref != "" || path == "" {
+		return ref, nil
+	}
+	ref, err := eventRef(path)
+	if err != nil {
+		return "", err
+	}
+	slog.Info("using the GitHub event payload as the ref", "event", os.Getenv(envGitHubEventName))
+	return ref, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeEvent(t *testing.T, payload string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(path, []byte(payload), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEventRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{
+			name: "push",
+			payload: `{"commits":[{"message":"GAIA-1 fix"},{"message":"GAIA-2 feat\n\nbody"}],
+				"head_commit":{"message":"GAIA-2 feat\n\nbody"}}`,
+			want: "GAIA-1 fix\nGAIA-2 feat\n\nbody",
+		},
+		{
+			name:    "push without commit list",
+			payload: `{"head_commit":{"message":"GAIA-3 only head"}}`,
+			want:    "GAIA-3 only head",
+		},
+		{
+			name:    "pull request",
+			payload: `{"pull_request":{"title":"GAIA-4 title","body":"Closes GAIA-5"}}`,
+			want:    "GAIA-4 title\nCloses GAIA-5",
+		},
+		{
+			name:    "pull request with null body",
+			payload: `{"pull_request":{"title":"GAIA-6 title","body":null}}`,
+			want:    "GAIA-6 title",
+		},
+		{
+			name:    "other event",
+			payload: `{"action":"created"}`,
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eventRef(writeEvent(t, tt.payload))
+			if err != nil {
+				t.Fatalf("eventRef: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("eventRef = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefFromEvent(t *testing.T) {
+	t.Setenv(envGitHubEventPath, writeEvent(t, `{"head_commit":{"message":"GAIA-1"}}`))
+
+	if got, _ := refFromEvent("GAIA-9"); got != "GAIA-9" {
+		t.Errorf("explicit ref should win, got %q", got)
+	}
+	if got, err := refFromEvent(""); err != nil || got != "GAIA-1" {
+		t.Errorf("event fallback: got %q, %v", got, err)
+	}
+
+	t.Setenv(envGitHubEventPath, writeEvent(t, `not json`))
+	if _, err := refFromEvent(""); err == nil {
+		t.Error("expected a parse error")
+	}
+
+	t.Setenv(envGitHubEventPath, "")
+	if got, err := refFromEvent(""); err != nil || got != "" {
+		t.Errorf("outside Actions: got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Files GitHub Actions reads step outputs and the job summary from.
+const (
+	envGitHubOutput      = "GITHUB_OUTPUT"
+	envGitHubStepSummary = "GITHUB_STEP_SUMMARY"
+)
+
+// githubOutput is one name/value pair written to $GITHUB_OUTPUT.
+type githubOutput struct {
+	name  string
+	value string
+}
+
+// githubOutputs derives the step outputs from the run report, so downstream
+// steps (Slack notifications, changelogs) can consume what the run did:
+//
+//   - issues: the processed issue keys, comma-separated, in ref order;
+//   - issue_urls: their browse links, in the same order;
+//   - transitioned: the keys moved by the transition;
+//   - failed: the keys with at least one failed action.
+func githubOutputs(r *runReport) []githubOutput {
+	var keys, urls, transitioned, failed []string
+	for _, res := range r.results() {
+		keys = append(keys, res.Key)
+		if res.URL != "" {
+			urls = append(urls, res.URL)
+		}
+		var moved bool
+		for _, a := range res.Actions {
+			moved = moved || (a.Action == actionTransition && a.Outcome == outcomeOK)
+		}
+		if moved {
+			transitioned = append(transitioned, res.Key)
+		}
+		if res.failed() {
+			failed = append(failed, res.Key)
+		}
+	}
+	return []githubOutput{
+		{"issues", strings.Join(keys, ",")},
+		{"issue_urls", strings.Join(urls, ",")},
+		{"transitioned", strings.Join(transitioned, ",")},
+		{"failed", strings.Join(failed, ",")},
+	}
+}
+
+// writeGitHubOutputs appends outputs to the $GITHUB_OUTPUT file at path using
+// the name=value format, switching to the heredoc form for values that span
+// lines. It is a no-op outside GitHub Actions (empty path).
+func writeGitHubOutputs(path string, outputs []githubOutput) error {
+	if path == "" {
+		return nil
+	}
+	var b strings.Builder
+	for _, o := range outputs {
+		if strings.ContainsAny(o.value, "\r\n") {
+			delim := "GO_JIRA_EOF"
+			for strings.Contains(o.value, delim) {
+				delim += "_"
+			}
+			fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", o.name, delim, o.value, delim)
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", o.name, o.value)
+	}
+	return appendRunnerFile(envGitHubOutput, path, b.String())
+}
+
+// writeGitHubStepSummary appends the run's Markdown report to the
+// $GITHUB_STEP_SUMMARY file at path, so the result shows on the workflow run
+// page without opening the logs. It is a no-op outside GitHub Actions.
+func writeGitHubStepSummary(path string, r *runReport) error {
+	if path == "" {
+		return nil
+	}
+	var b strings.Builder
+	r.writeMarkdown(&b)
+	return appendRunnerFile(envGitHubStepSummary, path, b.String())
+}
+
+// appendRunnerFile appends data to one of the runner's command files; env
+// names the file in errors.
+func appendRunnerFile(env, path, data string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- path comes from the runner
+	if err != nil {
+		return fmt.Errorf("open %s: %w", env, err)
+	}
+	if _, err := f.WriteString(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write %s: %w", env, err)
+	}
+	return f.Close()
+}
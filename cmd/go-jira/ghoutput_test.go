@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestGitHubOutputs(t *testing.T) {
+	r := newRunReport("https://jira.example.com")
+	r.setKeys([]string{"GAIA-1", "GAIA-2", "GAIA-3"})
+	r.record("GAIA-1", actionFetch, outcomeOK, "")
+	r.record("GAIA-1", actionTransition, outcomeOK, "")
+	r.record("GAIA-2", actionFetch, outcomeOK, "")
+	r.record("GAIA-2", actionTransition, outcomeSkipped, "not found")
+	r.record("GAIA-3", actionFetch, outcomeFailed, "404")
+
+	got := map[string]string{}
+	for _, o := range githubOutputs(r) {
+		got[o.name] = o.value
+	}
+	want := map[string]string{
+		"issues": "GAIA-1,GAIA-2,GAIA-3",
+		"issue_urls": "https://jira.example.com/browse/GAIA-1," +
+			"https://jira.example.com/browse/GAIA-2,https://jira.example.com/browse/GAIA-3",
+		"transitioned": "GAIA-1",
+		"failed":       "GAIA-3",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestWriteGitHubOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(path, []byte("existing=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := writeGitHubOutputs(path, []githubOutput{
+		{"issues", "GAIA-1,GAIA-2"},
+		{"notes", "line one\nline two"},
+	})
+	if err != nil {
+		t.Fatalf("writeGitHubOutputs: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "existing=1\n" +
+		"issues=GAIA-1,GAIA-2\n" +
+		"notes<<GO_JIRA_EOF\nline one\nline two\nGO_JIRA_EOF\n"
+	if string(b) != want {
+		t.Errorf("output file =\n%q\nwant\n%q", b, want)
+	}
+
+	if err := writeGitHubOutputs("", nil); err != nil {
+		t.Errorf("empty path should be a no-op, got %v", err)
+	}
+}
+
+func TestWriteGitHubStepSummary(t *testing.T) {
+	r := newRunReport("https://jira.example.com")
+	r.setKeys([]string{"GAIA-1", "GAIA-2"})
+	r.addIssue(&jira.Issue{Key: "GAIA-1", Fields: &jira.IssueFields{
+		Summary: "Fix a | b\nparser", Status: &jira.Status{Name: "To Do"},
+	}})
+	r.record("GAIA-1", actionFetch, outcomeOK, "")
+	r.record("GAIA-1", actionTransition, outcomeOK, "")
+	r.setNewStatus("GAIA-1", "Done")
+	r.record("GAIA-2", actionFetch, outcomeFailed, "issue does not exist")
+
+	path := filepath.Join(t.TempDir(), "summary")
+	if err := writeGitHubStepSummary(path, r); err != nil {
+		t.Fatalf("writeGitHubStepSummary: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+	for _, want := range []string{
+		"| Issue | Summary | Previous status | New status | Result |\n",
+		"| [GAIA-1](https://jira.example.com/browse/GAIA-1) | Fix a \\| b parser | To Do | Done | fetch ok, transition ok |\n",
+		"| [GAIA-2](https://jira.example.com/browse/GAIA-2) | — | — | — | fetch failed: issue does not exist |\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("step summary missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := writeGitHubStepSummary("", r); err != nil {
+		t.Errorf("empty path should be a no-op, got %v", err)
+	}
+}
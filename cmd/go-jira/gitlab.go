@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+)
+
+// GitLab CI predefined variables used as fallbacks when the GitHub Actions
+// equivalents are absent. The merge request ones are only set in merge
+// request pipelines.
+const (
+	envGitLabCommitMessage = "CI_COMMIT_MESSAGE"
+	envGitLabMRTitle       = "CI_MERGE_REQUEST_TITLE"
+	envGitLabMRDescription = "CI_MERGE_REQUEST_DESCRIPTION"
+	envGitLabMRSource      = "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"
+	envGitLabRefName       = "CI_COMMIT_REF_NAME"
+	envGitLabPipelineURL   = "CI_PIPELINE_URL"
+	envGitLabProjectURL    = "CI_PROJECT_URL"
+	envGitLabCommitSHA     = "CI_COMMIT_SHA"
+)
+
+// refFromGitLab falls back to the merge request title and description and
+// the commit message of a GitLab CI pipeline when no ref is configured, the
+// GitLab counterpart of refFromEvent. An explicit ref always wins, and
+// outside GitLab CI ref is returned unchanged.
+func refFromGitLab(ref string) string {
+	if ref != "" {
+		return ref
+	}
+	ref = joinNonEmpty(
+		os.Getenv(envGitLabMRTitle),
+		os.Getenv(envGitLabMRDescription),
+		os.Getenv(envGitLabCommitMessage),
+	)
+	if ref != "" {
+		slog.Info("using the GitLab CI commit message as the ref")
+	}
+	return ref
+}
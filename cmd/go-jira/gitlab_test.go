@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestRefFromGitLab(t *testing.T) {
+	t.Setenv(envGitLabMRTitle, "GAIA-1 Add login")
+	t.Setenv(envGitLabMRDescription, "")
+	t.Setenv(envGitLabCommitMessage, "Merge GAIA-2")
+
+	if got := refFromGitLab("GAIA-9"); got != "GAIA-9" {
+		t.Errorf("explicit ref should win, got %q", got)
+	}
+	if got := refFromGitLab(""); got != "GAIA-1 Add login\nMerge GAIA-2" {
+		t.Errorf("GitLab fallback: got %q", got)
+	}
+
+	t.Setenv(envGitLabMRTitle, "")
+	t.Setenv(envGitLabCommitMessage, "")
+	if got := refFromGitLab(""); got != "" {
+		t.Errorf("outside GitLab CI: got %q", got)
+	}
+}
+
+func TestCurrentBranchGitLab(t *testing.T) {
+	t.Setenv(envGitHubHeadRef, "")
+	t.Setenv(envGitHubRefName, "")
+	t.Setenv(envGitLabMRSource, "feature/login")
+	t.Setenv(envGitLabRefName, "refs/merge-requests/1/head")
+
+	if got := currentBranch(""); got != "feature/login" {
+		t.Errorf("currentBranch = %q, want the merge request source branch", got)
+	}
+	t.Setenv(envGitLabMRSource, "")
+	t.Setenv(envGitLabRefName, "main")
+	if got := currentBranch(""); got != "main" {
+		t.Errorf("currentBranch = %q, want CI_COMMIT_REF_NAME", got)
+	}
+}
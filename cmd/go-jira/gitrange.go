@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CI variables naming the checked-out workspace.
+const (
+	envGitHubWorkspace  = "GITHUB_WORKSPACE"
+	envGitLabProjectDir = "CI_PROJECT_DIR"
+)
+
+// workspaceDir returns the absolute path of the checked-out repository:
+// GITHUB_WORKSPACE on GitHub Actions, CI_PROJECT_DIR on GitLab CI, and the
+// working directory otherwise.
+func workspaceDir() (string, error) {
+	for _, key := range []string{envGitHubWorkspace, envGitLabProjectDir} {
+		if dir := os.Getenv(key); dir != "" {
+			return filepath.Abs(dir)
+		}
+	}
+	return os.Getwd()
+}
+
+// commitRangeMessages returns the full messages of the commits in rng (e.g.
+// "v1.2.0..HEAD"), read with `git log` in the current directory, so a release
+// workflow can scan them for issue keys without its own `git log` step.
+func commitRangeMessages(ctx context.Context, rng string) (string, error) {
+	rng = strings.TrimSpace(rng)
+	// A leading "-" would be parsed by git as an option.
+	if strings.HasPrefix(rng, "-") {
+		return "", fmt.Errorf("commit_range %q must not start with '-'", rng)
+	}
+	dir, err := workspaceDir()
+	if err != nil {
+		return "", fmt.Errorf("commit_range: %w", err)
+	}
+	var stdout, stderr bytes.Buffer
+	// Marking the workspace as a safe.directory lets the container image,
+	// which runs as its own user, read the checkout owned by the runner's
+	// user, without trusting every repository on the machine.
+	// #nosec G204 -- rng is passed as a single argument, never through a shell.
+	c := exec.CommandContext(ctx, "git", "-c", "safe.directory="+dir,
+		"log", "--format=%B", rng, "--")
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", errors.New("commit_range requires git on PATH")
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git log %s: %s", rng, msg)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// appendCommitRange returns ref extended with the messages of the commits in
+// rng. An empty rng returns ref unchanged.
+func appendCommitRange(ctx context.Context, ref, rng string) (string, error) {
+	if rng == "" {
+		return ref, nil
+	}
+	messages, err := commitRangeMessages(ctx, rng)
+	if err != nil {
+		return "", err
+	}
+	return joinNonEmpty(ref, messages), nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitRepo initialises a repository in a temp dir with one commit per message,
+// tags the first one v1, and makes it the working directory.
+func gitRepo(t *testing.T, messages ...string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	git := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Env = append(c.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	for i, m := range messages {
+		git("commit", "-q", "--allow-empty", "-m", m)
+		if i == 0 {
+			git("tag", "v1")
+		}
+	}
+}
+
+func TestAppendCommitRange(t *testing.T) {
+	gitRepo(t, "GAIA-1 initial", "GAIA-2 add parser", "fix: GAIA-3 crash\n\nRefs GAIA-4")
+
+	got, err := appendCommitRange(context.Background(), "GAIA-9", "v1..HEAD")
+	if err != nil {
+		t.Fatalf("appendCommitRange: %v", err)
+	}
+	keys, err := getIssueKeys(got, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(keys, ",") != "GAIA-9,GAIA-3,GAIA-4,GAIA-2" {
+		t.Errorf("keys = %v, from ref %q", keys, got)
+	}
+	if strings.Contains(got, "GAIA-1") {
+		t.Errorf("range should exclude the tagged commit: %q", got)
+	}
+}
+
+func TestAppendCommitRangeErrors(t *testing.T) {
+	gitRepo(t, "GAIA-1 initial")
+
+	if got, err := appendCommitRange(context.Background(), "GAIA-9", ""); err != nil || got != "GAIA-9" {
+		t.Errorf("empty range: got %q, %v", got, err)
+	}
+	if _, err := appendCommitRange(context.Background(), "", "--output=/tmp/x"); err == nil {
+		t.Error("expected an option-like range to be rejected")
+	}
+	_, err := appendCommitRange(context.Background(), "", "v9..HEAD")
+	if err == nil || !strings.Contains(err.Error(), "git log v9..HEAD") {
+		t.Errorf("unknown revision: error = %v", err)
+	}
+}
+
+func TestWorkspaceDir(t *testing.T) {
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+	// t.TempDir may sit behind a symlink (e.g. /tmp on macOS); compare with
+	// the path the process reports.
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name, github, gitlab, want string
+	}{
+		{name: "github", github: "/home/runner/work/repo", gitlab: "/builds/repo",
+			want: "/home/runner/work/repo"},
+		{name: "gitlab", gitlab: "/builds/repo", want: "/builds/repo"},
+		{name: "relative", gitlab: "repo", want: filepath.Join(cwd, "repo")},
+		{name: "cwd", want: cwd},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envGitHubWorkspace, tt.github)
+			t.Setenv(envGitLabProjectDir, tt.gitlab)
+			got, err := workspaceDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("workspaceDir = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// validateHeaders checks that the --headers value parses.
+func validateHeaders(config Config) error {
+	_, err := parseHeaders(config.headers)
+	return err
+}
+
+// parseHeaders parses the --headers value: "Name: value" pairs separated by
+// semicolons or newlines, e.g. "X-Forwarded-User: ci;X-Org: platform". The
+// newline form lets a multi-line CI input list one header per line.
+func parseHeaders(s string) (http.Header, error) {
+	h := http.Header{}
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ';' || r == '\n'
+	}) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header %q: want \"Name: value\"", part)
+		}
+		h.Add(textproto.CanonicalMIMEHeaderKey(name), strings.TrimSpace(value))
+	}
+	return h, nil
+}
+
+// headerTransport is a RoundTripper that sets a fixed set of headers on every
+// request, for gateways and WAFs in front of Jira that require them. It wraps
+// the authenticator's transport, which sets the credentials afterwards, so a
+// configured header can never clobber them.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request, so set the headers
+	// on a clone.
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
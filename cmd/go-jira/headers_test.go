@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/appleboy/go-jira/pkg/auth"
+)
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    http.Header
+		wantErr bool
+	}{
+		{name: "empty", in: "", want: http.Header{}},
+		{
+			name: "semicolon separated",
+			in:   "X-Forwarded-User: ci;x-org: platform",
+			want: http.Header{"X-Forwarded-User": {"ci"}, "X-Org": {"platform"}},
+		},
+		{
+			name: "newline separated with value colons",
+			in:   "X-Trace: a:b\n\nX-Org: platform\n",
+			want: http.Header{"X-Trace": {"a:b"}, "X-Org": {"platform"}},
+		},
+		{name: "missing colon", in: "X-Org platform", wantErr: true},
+		{name: "empty name", in: ": value", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHeaders(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHeaders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseHeaders() = %v, want %v", got, tt.want)
+			}
+			for k := range tt.want {
+				if got.Get(k) != tt.want.Get(k) {
+					t.Errorf("header %s = %q, want %q", k, got.Get(k), tt.want.Get(k))
+				}
+			}
+		})
+	}
+}
+
+func TestCreateHTTPClientSendsCustomHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := mustHTTPClient(t, Config{
+		baseURL: srv.URL,
+		headers: "X-Org: platform;Authorization: Basic spoofed",
+	}, &auth.BearerAuth{Token: "secret"})
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got.Get("X-Org") != "platform" {
+		t.Errorf("X-Org = %q, want platform", got.Get("X-Org"))
+	}
+	if got.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Authorization = %q, credentials must win over custom headers",
+			got.Get("Authorization"))
+	}
+	if req.Header.Get("X-Org") != "" {
+		t.Error("custom headers must not be written to the caller's request")
+	}
+}
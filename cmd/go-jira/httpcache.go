@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Bounds on the conditional-request cache: responses larger than
+// maxCachedBody are not kept, and once maxCacheEntries URLs are cached new
+// ones are passed through uncached.
+const (
+	maxCachedBody   = 1 << 20
+	maxCacheEntries = 256
+)
+
+// cachedResponse is a GET response kept for revalidation with its ETag.
+type cachedResponse struct {
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// cacheTransport is a RoundTripper that asks for gzip-compressed responses
+// and revalidates repeated GETs with If-None-Match when the server sent an
+// ETag, so metadata looked up more than once (resolutions, transitions,
+// projects) costs a 304 instead of the full body. A 304 is answered from the
+// cache as a 200. Each client gets its own cache, so responses are never
+// shared across credentials.
+type cacheTransport struct {
+	base http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	// Setting Accept-Encoding turns off the stdlib's transparent gzip, so
+	// the response is decoded below; a caller-set encoding is kept as-is.
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	key := req.URL.String()
+	cached, hit := t.lookup(req.Method, key)
+	if hit {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if hit && resp.StatusCode == http.StatusNotModified {
+		drainBody(resp.Body)
+		return cached.response(req), nil
+	}
+	if err := decodeGzip(resp); err != nil {
+		drainBody(resp.Body)
+		return nil, err
+	}
+	t.store(req.Method, key, resp)
+	return resp, nil
+}
+
+// lookup returns the cached response for a GET of key.
+func (t *cacheTransport) lookup(method, key string) (cachedResponse, bool) {
+	if method != http.MethodGet {
+		return cachedResponse{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.entries[key]
+	return c, ok
+}
+
+// store keeps a successful GET response that carries an ETag, buffering its
+// body so the caller still reads it in full.
+func (t *cacheTransport) store(method, key string, resp *http.Response) {
+	etag := resp.Header.Get("ETag")
+	if method != http.MethodGet || resp.StatusCode != http.StatusOK || etag == "" ||
+		strings.Contains(resp.Header.Get("Cache-Control"), "no-store") ||
+		resp.ContentLength > maxCachedBody {
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	// Hand the caller the bytes read so far followed by the rest of the body.
+	resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil || len(body) > maxCachedBody {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == nil {
+		t.entries = map[string]cachedResponse{}
+	}
+	if _, ok := t.entries[key]; !ok && len(t.entries) >= maxCacheEntries {
+		return
+	}
+	t.entries[key] = cachedResponse{etag: etag, header: resp.Header.Clone(), body: body}
+}
+
+// response rebuilds a 200 response for req from the cache.
+func (c cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       req,
+	}
+}
+
+// decodeGzip replaces a gzip-encoded body with its decompressed stream, as
+// the stdlib transport would have done had Accept-Encoding not been set.
+func decodeGzip(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") || resp.Body == http.NoBody {
+		return nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return fmt.Errorf("decode gzip response: %w", err)
+	}
+	resp.Body = readCloser{zr, resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// readCloser pairs a reader over a body with the body's own Close.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCacheTransport(t *testing.T) {
+	const body = `[{"id":"1","name":"Fixed"}]`
+	var full, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+		if r.Method == http.MethodGet && r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(body))
+		_ = zw.Close()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &cacheTransport{base: http.DefaultTransport}}
+	get := func(method string) string {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+"/rest/api/2/resolution", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s status = %d, want 200", method, resp.StatusCode)
+		}
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	for i := range 3 {
+		if got := get(http.MethodGet); got != body {
+			t.Errorf("GET #%d body = %q, want %q", i+1, got, body)
+		}
+	}
+	if full != 1 || notModified != 2 {
+		t.Errorf("full = %d, 304 = %d; want 1 full response then 304s", full, notModified)
+	}
+
+	// Other methods are neither revalidated nor answered from the cache.
+	if got := get(http.MethodPost); got != body {
+		t.Errorf("POST body = %q", got)
+	}
+	if full != 2 {
+		t.Errorf("POST was not sent in full")
+	}
+}
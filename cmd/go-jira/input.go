@@ -31,3 +31,47 @@ func resolveStdin(value string) (string, error) {
 	}
 	return strings.TrimRight(string(data), "\n"), nil
 }
+
+// appendStdin returns ref extended with the contents of stdin, for
+// `git log v1.0..HEAD | go-jira run --stdin`. A ref of "-" already names
+// stdin and is returned as-is for resolveStdin to read.
+func appendStdin(ref string) (string, error) {
+	if ref == stdinSentinel {
+		return ref, nil
+	}
+	content, err := resolveStdin(stdinSentinel)
+	if err != nil {
+		return "", err
+	}
+	return joinNonEmpty(ref, content), nil
+}
+
+// appendRefFile returns ref extended with the contents of the file at path, so
+// keys from both are scanned. The file lets a release that spans hundreds of
+// commits pass its generated git log without hitting env-var size limits. An
+// empty path returns ref unchanged.
+func appendRefFile(ref, path string) (string, error) {
+	if path == "" {
+		return ref, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the user's own ref file
+	if err != nil {
+		return "", fmt.Errorf("read ref_file: %w", err)
+	}
+	return joinNonEmpty(ref, string(data)), nil
+}
+
+// joinNonEmpty joins the parts that are not blank with newlines, trimming
+// surrounding whitespace, to combine several ref sources into one text.
+func joinNonEmpty(parts ...string) string {
+	var b strings.Builder
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			if b.Len() > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(p)
+		}
+	}
+	return b.String()
+}
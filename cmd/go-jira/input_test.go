@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -42,3 +44,47 @@ func TestResolveStdinEmptyInput(t *testing.T) {
 		t.Errorf("expected empty string, got %q", got)
 	}
 }
+
+func TestAppendRefFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gitlog.txt")
+	if err := os.WriteFile(path, []byte("GAIA-1 fix\nGAIA-2 feat\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := appendRefFile("", path)
+	if err != nil || got != "GAIA-1 fix\nGAIA-2 feat" {
+		t.Errorf("file only: got %q, %v", got, err)
+	}
+	got, err = appendRefFile("GAIA-3", path)
+	if err != nil || got != "GAIA-3\nGAIA-1 fix\nGAIA-2 feat" {
+		t.Errorf("ref and file: got %q, %v", got, err)
+	}
+	if got, _ := appendRefFile("GAIA-3", ""); got != "GAIA-3" {
+		t.Errorf("no file: got %q", got)
+	}
+	if _, err := appendRefFile("", filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestAppendStdin(t *testing.T) {
+	orig := stdinReader
+	t.Cleanup(func() { stdinReader = orig })
+
+	stdinReader = strings.NewReader("GAIA-1 fix\nGAIA-2 feat\n")
+	got, err := appendStdin("")
+	if err != nil || got != "GAIA-1 fix\nGAIA-2 feat" {
+		t.Errorf("stdin only: got %q, %v", got, err)
+	}
+
+	stdinReader = strings.NewReader("GAIA-1 fix\n")
+	got, err = appendStdin("GAIA-3")
+	if err != nil || got != "GAIA-3\nGAIA-1 fix" {
+		t.Errorf("ref and stdin: got %q, %v", got, err)
+	}
+
+	// "-" is left for resolveStdin so stdin is read only once.
+	if got, _ := appendStdin(stdinSentinel); got != stdinSentinel {
+		t.Errorf("sentinel: got %q", got)
+	}
+}
@@ -4,107 +4,97 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"net/http"
-	"regexp"
-	"sync"
+	"slices"
+	"strings"
+
+	"github.com/appleboy/go-jira/pkg/jiraops"
 
 	jira "github.com/andygrunwald/go-jira"
 )
 
-// issueAlphanumericPattern matches string that references to an alphanumeric issue, e.g. ABC-1234
-var issueAlphanumericPattern = regexp.MustCompile(`([A-Z]{1,10}-[1-9][0-9]*)`)
-
-// processIssues retrieves issues from JIRA concurrently
+// processIssues retrieves issues from JIRA, in batch searches where possible
+// and concurrently one by one for the rest (see jiraops.Processor.Fetch)
 func processIssues(
 	ctx context.Context,
 	jiraClient *jira.Client,
 	config Config,
 ) ([]*jira.Issue, error) {
-	issueKeys, err := getIssueKeys(config.ref, config.issuePattern)
+	issueKeys, err := scanRefs(config)
 	if err != nil {
 		return nil, err
 	}
+	if issueKeys, err = filterCommitTypes(issueKeys, config); err != nil {
+		return nil, err
+	}
+	issueKeys = filterProjects(issueKeys, config)
+	report := reportFrom(ctx)
+	report.setKeys(issueKeys)
 	if len(issueKeys) == 0 {
+		// Repos that require every change to reference an issue opt into
+		// failing here; by default a ref without keys is a successful no-op.
+		if config.failOnNoIssues {
+			return nil, fmt.Errorf("%w in ref and fail_on_no_issues is set", ErrNoIssueKeys)
+		}
 		slog.Warn("no issue keys found in ref")
 		return []*jira.Issue{}, nil
 	}
-
-	type result struct {
-		issue *jira.Issue
-		err   error
-		key   string
+	if config.validateProjects {
+		issueKeys = validateProjects(ctx, jiraClient, issueKeys)
 	}
-
-	results := make(chan result, len(issueKeys))
-	var wg sync.WaitGroup
-
-	// Process issues concurrently
-	for _, issueKey := range issueKeys {
-		wg.Add(1)
-		go func(key string) {
-			defer wg.Done()
-			issue, resp, err := jiraClient.Issue.GetWithContext(
-				ctx,
-				key,
-				&jira.GetQueryOptions{
-					Expand: "transitions",
-				},
-			)
-			if resp != nil && resp.Body != nil {
-				defer resp.Body.Close()
-			}
-			if err != nil {
-				results <- result{err: err, key: key}
-				return
-			}
-			if resp.StatusCode != http.StatusOK {
-				results <- result{err: fmt.Errorf("unexpected status: %s", resp.Status), key: key}
-				return
-			}
-			results <- result{issue: issue, key: key}
-		}(issueKey)
+	if issueKeys, err = filterJQL(ctx, jiraClient, issueKeys, config.filterJQL); err != nil {
+		return nil, err
+	}
+	if issueKeys, err = capIssues(ctx, issueKeys, config.maxIssues, config.maxIssuesMode); err != nil {
+		return nil, err
 	}
 
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	// Fetch failures are recorded per issue rather than failing the run, so
+	// the error joining them is not returned.
+	fetched, outcomes, _ := newProcessor(ctx, jiraClient, config).Fetch(ctx, issueKeys)
+	byKey := make(map[string]*jira.Issue, len(fetched))
+	for _, iss := range fetched {
+		byKey[iss.Key] = iss
+	}
 
+	issueTypes := splitCSV(config.issueTypes)
 	issues := []*jira.Issue{}
-	for r := range results {
-		if r.err != nil {
-			slog.Error("error getting issue", "issue", r.key, "error", r.err)
+	for _, out := range outcomes {
+		if recordSetback(ctx, out, "error getting issue") {
+			continue
+		}
+		iss := byKey[out.Key]
+		report.addIssue(iss)
+		// Issues of other types (e.g. an epic mentioned in passing) are
+		// reported but left untouched.
+		if typ := issueTypeName(iss); len(issueTypes) > 0 && !containsFold(issueTypes, typ) {
+			issueLogger(out.Key, actionFetch).Info("skipping issue type", "type", typ)
+			report.record(out.Key, actionFetch, outcomeSkipped,
+				fmt.Sprintf("issue type %q is not one of %s", typ, strings.Join(issueTypes, ", ")))
 			continue
 		}
-		issues = append(issues, r.issue)
+		report.record(out.Key, actionFetch, outcomeOK, "")
+		issues = append(issues, iss)
 	}
 
 	return issues, nil
 }
 
-// getIssueKeys extracts issue keys from a reference string using a pattern
+// getIssueKeys extracts the distinct issue keys from a reference string using
+// a pattern (see jiraops.ExtractKeys); positions are available through
+// issuekey.Extract.
 func getIssueKeys(ref, issuePattern string) ([]string, error) {
-	pattern := issueAlphanumericPattern
-	issueKeys := []string{}
-	if issuePattern != "" {
-		var err error
-		pattern, err = regexp.Compile(issuePattern)
-		if err != nil {
-			return nil, fmt.Errorf("invalid issue pattern %q: %w", issuePattern, err)
-		}
-	}
+	return jiraops.ExtractKeys(ref, issuePattern)
+}
 
-	matches := pattern.FindAllString(ref, -1)
-	// Deduplicate issue keys
-	issueKeySet := make(map[string]struct{})
-	for _, match := range matches {
-		if _, ok := issueKeySet[match]; ok {
-			continue
-		}
-		issueKeySet[match] = struct{}{}
-		issueKeys = append(issueKeys, match)
+// scanKeys extracts the distinct issue keys from text the way config asks:
+// every match of the issue pattern by default, or, with --trailers-only, only
+// the keys in Git trailers such as "Jira: ABC-123", so keys mentioned in
+// prose are not acted on.
+func scanKeys(text string, config Config) ([]string, error) {
+	if !config.trailersOnly {
+		return getIssueKeys(text, config.issuePattern)
 	}
-	return issueKeys, nil
+	return jiraops.ExtractTrailerKeys(text, config.issuePattern, splitCSV(config.trailers))
 }
 
 // issueSummary returns the issue summary, tolerating a nil Fields — a partial
@@ -116,6 +106,19 @@ func issueSummary(iss *jira.Issue) string {
 	return iss.Fields.Summary
 }
 
+// issueTypeName returns the issue type name, tolerating nil Fields.
+func issueTypeName(iss *jira.Issue) string {
+	if iss.Fields == nil {
+		return ""
+	}
+	return iss.Fields.Type.Name
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
 // issueStatusName returns the issue status name, tolerating nil Fields/Status
 // (both are pointers with omitempty in a partial response).
 func issueStatusName(iss *jira.Issue) string {
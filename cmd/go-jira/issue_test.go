@@ -3,11 +3,18 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/appleboy/go-jira/pkg/jiraops"
+
 	jira "github.com/andygrunwald/go-jira"
 )
 
@@ -27,7 +34,7 @@ func TestProcessIssues(t *testing.T) {
 			setupServer: func() *httptest.Server {
 				return httptest.NewServer(
 					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-						issueKey := r.URL.Path[len("/rest/api/2/issue/"):]
+						issueKey := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
 						w.WriteHeader(http.StatusOK)
 						issue := jira.Issue{
 							Key: issueKey,
@@ -70,7 +77,7 @@ func TestProcessIssues(t *testing.T) {
 			setupServer: func() *httptest.Server {
 				return httptest.NewServer(
 					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-						issueKey := r.URL.Path[len("/rest/api/2/issue/"):]
+						issueKey := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
 						// Simulate failure for DEF-456
 						if issueKey == "DEF-456" {
 							w.WriteHeader(http.StatusNotFound)
@@ -108,7 +115,7 @@ func TestProcessIssues(t *testing.T) {
 			setupServer: func() *httptest.Server {
 				return httptest.NewServer(
 					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-						issueKey := r.URL.Path[len("/rest/api/2/issue/"):]
+						issueKey := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
 						w.WriteHeader(http.StatusOK)
 						issue := jira.Issue{
 							Key: issueKey,
@@ -180,6 +187,84 @@ func TestProcessIssues(t *testing.T) {
 	}
 }
 
+func TestProcessIssues_FailOnNoIssues(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		config := Config{ref: "chore: bump deps", failOnNoIssues: fail}
+		issues, err := processIssues(context.Background(), nil, config)
+		if fail {
+			if !errors.Is(err, ErrNoIssueKeys) || !strings.Contains(err.Error(), "fail_on_no_issues") {
+				t.Errorf("failOnNoIssues=true: error = %v, want fail_on_no_issues error", err)
+			}
+			continue
+		}
+		if err != nil || len(issues) != 0 {
+			t.Errorf("failOnNoIssues=false: got %d issues, err %v; want none, nil", len(issues), err)
+		}
+	}
+}
+
+func TestScanKeys(t *testing.T) {
+	ref := "fix: crash while GAIA-1 is open\n\nJira: GAIA-2\nRefs: GAIA-3\nX-Ticket: GAIA-4"
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{"whole message", Config{}, "GAIA-1,GAIA-2,GAIA-3,GAIA-4"},
+		{"default trailers", Config{trailersOnly: true}, "GAIA-2,GAIA-3"},
+		{"custom trailers", Config{trailersOnly: true, trailers: "x-ticket, jira"}, "GAIA-2,GAIA-4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scanKeys(ref, tt.config)
+			if err != nil {
+				t.Fatalf("scanKeys: %v", err)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("scanKeys = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessIssues_IssueTypes(t *testing.T) {
+	types := map[string]string{"GAIA-1": "Bug", "GAIA-2": "Epic", "GAIA-3": "task"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		_ = json.NewEncoder(w).Encode(jira.Issue{Key: key, Fields: &jira.IssueFields{
+			Type: jira.IssueType{Name: types[key]},
+		}})
+	}))
+	defer server.Close()
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newRunReport("")
+	ctx := withReport(context.Background(), r)
+	issues, err := processIssues(ctx, jiraClient, Config{
+		ref:        "GAIA-1 GAIA-2 GAIA-3",
+		issueTypes: "Bug, Task",
+	})
+	if err != nil {
+		t.Fatalf("processIssues: %v", err)
+	}
+	var keys []string
+	for _, iss := range issues {
+		keys = append(keys, iss.Key)
+	}
+	slices.Sort(keys)
+	if strings.Join(keys, ",") != "GAIA-1,GAIA-3" {
+		t.Errorf("issues = %v, want GAIA-1,GAIA-3", keys)
+	}
+	for _, res := range r.results() {
+		if res.Key == "GAIA-2" && (len(res.Actions) != 1 || res.Actions[0].Outcome != outcomeSkipped) {
+			t.Errorf("GAIA-2 actions = %+v, want one skipped fetch", res.Actions)
+		}
+	}
+}
+
 func TestGetIssueKeys_ExtendedCases(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -193,6 +278,12 @@ func TestGetIssueKeys_ExtendedCases(t *testing.T) {
 			issuePattern: "",
 			want:         []string{"ABC-123", "DEF-456", "XYZ-789"},
 		},
+		{
+			name:         "skips URLs, code, and known false positives",
+			ref:          "Fix ABC-1 for UTF-8 input\n\nLog: https://ci.example.com/ABC-2 `ABC-3`",
+			issuePattern: "",
+			want:         []string{"ABC-1"},
+		},
 		{
 			name:         "issues in commit message format",
 			ref:          "[ABC-123] Fix bug\n\nAlso resolves DEF-456",
@@ -280,3 +371,88 @@ func TestGetIssueKeys_ExtendedCases(t *testing.T) {
 		})
 	}
 }
+
+// searchedKeys returns the keys of a `key in (...)` batch search request.
+func searchedKeys(r *http.Request) []string {
+	jql := r.URL.Query().Get("jql")
+	list := strings.TrimSuffix(strings.TrimPrefix(jql, "key in ("), ")")
+	return splitCSV(list)
+}
+
+// writeSearchResult answers a batch search with one issue per key.
+func writeSearchResult(t *testing.T, w http.ResponseWriter, keys []string) {
+	t.Helper()
+	issues := make([]jira.Issue, 0, len(keys))
+	for _, k := range keys {
+		issues = append(issues, jira.Issue{Key: k, Fields: &jira.IssueFields{Summary: "Issue " + k}})
+	}
+	if err := json.NewEncoder(w).Encode(map[string]any{"issues": issues, "total": len(issues)}); err != nil {
+		t.Errorf("encode search result: %v", err)
+	}
+}
+
+func TestProcessIssues_BatchFetch(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		searches [][]string
+		gets     []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/search" {
+			q := r.URL.Query()
+			if q.Get("expand") != "transitions" || q.Get("validateQuery") != "warn" ||
+				q.Get("fields") != "summary,status,issuetype" {
+				t.Errorf("search query = %v", q)
+			}
+			keys := searchedKeys(r)
+			mu.Lock()
+			searches = append(searches, keys)
+			mu.Unlock()
+			// MISSING-1 is left out as Jira does for an unknown key.
+			writeSearchResult(t, w, slices.DeleteFunc(keys, func(k string) bool { return k == "MISSING-1" }))
+			return
+		}
+		if got := r.URL.Query().Get("fields"); got != "summary,status,issuetype" {
+			t.Errorf("issue fetch fields = %q", got)
+		}
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		mu.Lock()
+		gets = append(gets, key)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist"]}`))
+	}))
+	defer server.Close()
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"MISSING-1"}
+	for i := 1; i <= 60; i++ {
+		keys = append(keys, fmt.Sprintf("GAIA-%d", i))
+	}
+	r := newRunReport("")
+	ctx := withReport(context.Background(), r)
+	issues, err := processIssues(ctx, jiraClient, Config{ref: strings.Join(keys, " ")})
+	if err != nil {
+		t.Fatalf("processIssues: %v", err)
+	}
+	if len(issues) != 60 {
+		t.Errorf("got %d issues, want 60", len(issues))
+	}
+	if len(searches) != 2 || len(searches[0])+len(searches[1]) != 61 {
+		t.Errorf("searches = %v, want 61 keys in 2 batches", searches)
+	}
+	for _, s := range searches {
+		if len(s) > jiraops.SearchBatchSize {
+			t.Errorf("batch of %d keys exceeds %d", len(s), jiraops.SearchBatchSize)
+		}
+	}
+	if !slices.Equal(gets, []string{"MISSING-1"}) {
+		t.Errorf("individual fetches = %v, want only the key the search missed", gets)
+	}
+	if r.failedCount() != 1 {
+		t.Errorf("failed = %d, want 1", r.failedCount())
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// filterJQL keeps the keys matching the --filter-jql guard, e.g.
+// `status != Done AND assignee = currentUser()`, using a single paginated
+// search for `key in (...) AND (<filter>)` instead of per-issue checks. Keys
+// left out are recorded as skipped. A failed search fails the run rather than
+// acting on unguarded issues.
+func filterJQL(ctx context.Context, jiraClient *jira.Client, keys []string, filter string) ([]string, error) {
+	if filter == "" || len(keys) == 0 {
+		return keys, nil
+	}
+	jql := fmt.Sprintf("key in (%s) AND (%s)", strings.Join(keys, ", "), filter)
+	matched := map[string]bool{}
+	err := jiraClient.Issue.SearchPagesWithContext(ctx, jql, &jira.SearchOptions{
+		Fields: []string{"key"},
+		// With strict validation Jira rejects the whole query when one of the
+		// keys does not exist; warn lets the others through.
+		ValidateQuery: "warn",
+	}, func(iss jira.Issue) error {
+		matched[iss.Key] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("filter_jql search: %w", err)
+	}
+
+	report := reportFrom(ctx)
+	kept := keys[:0:0]
+	for _, k := range keys {
+		if !matched[k] {
+			issueLogger(k, actionFetch).Info("skipping issue not matching filter_jql")
+			report.record(k, actionFetch, outcomeSkipped, "does not match filter_jql")
+			continue
+		}
+		kept = append(kept, k)
+	}
+	return kept, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestFilterJQL(t *testing.T) {
+	var gotJQL, gotValidate string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/search" {
+			http.NotFound(w, r)
+			return
+		}
+		gotJQL = r.URL.Query().Get("jql")
+		gotValidate = r.URL.Query().Get("validateQuery")
+		_, _ = w.Write([]byte(`{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"GAIA-2"}]}`))
+	}))
+	defer server.Close()
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newRunReport("")
+	ctx := withReport(context.Background(), r)
+	got, err := filterJQL(ctx, jiraClient, []string{"GAIA-1", "GAIA-2"}, "status != Done")
+	if err != nil {
+		t.Fatalf("filterJQL: %v", err)
+	}
+	if strings.Join(got, ",") != "GAIA-2" {
+		t.Errorf("keys = %v, want GAIA-2", got)
+	}
+	if gotJQL != "key in (GAIA-1, GAIA-2) AND (status != Done)" || gotValidate != "warn" {
+		t.Errorf("jql = %q, validateQuery = %q", gotJQL, gotValidate)
+	}
+	res := r.results()
+	if len(res) != 1 || res[0].Key != "GAIA-1" || res[0].Actions[0].Outcome != outcomeSkipped {
+		t.Errorf("results = %+v", res)
+	}
+
+	// Without a filter no search is made.
+	gotJQL = ""
+	if got, _ := filterJQL(ctx, jiraClient, []string{"GAIA-1"}, ""); len(got) != 1 || gotJQL != "" {
+		t.Errorf("no filter: keys = %v, jql = %q", got, gotJQL)
+	}
+}
+
+func TestFilterJQLSearchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errorMessages":["Error in the JQL Query"]}`))
+	}))
+	defer server.Close()
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := filterJQL(context.Background(), jiraClient, []string{"GAIA-1"}, "status = "); err == nil ||
+		!strings.Contains(err.Error(), "filter_jql") {
+		t.Errorf("error = %v, want a filter_jql error", err)
+	}
+}
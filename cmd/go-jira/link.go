@@ -29,6 +29,7 @@ func newLinkCmd() *cobra.Command {
 	addCommonFlags(cmd)
 	addOAuthFlags(cmd)
 	addAuthFlags(cmd)
+	addAuditLogFlag(cmd)
 	addOutputFlag(cmd)
 	cmd.Flags().String(flagFrom, "", "Inward issue key (required)")
 	cmd.Flags().String(flagTo, "", "Outward issue key (required)")
@@ -61,7 +62,7 @@ func runLink(cmd *cobra.Command) error {
 		OutwardIssue: &jira.Issue{Key: to},
 	})
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return fmt.Errorf("error linking %s -> %s: %w", from, to, err)
@@ -2,12 +2,30 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/appleboy/go-jira/pkg/util"
+)
+
+// Log formats accepted by --log-format / LOG_FORMAT.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// Structured-log keys shared by the per-issue log lines, so the same field
+// means the same thing in every line (alongside statusKey for the HTTP or
+// issue status). Log pipelines such as Loki or Datadog can index on them.
+const (
+	logKeyIssue     = "issue"
+	logKeyOperation = "operation"
+	logKeyDuration  = "duration"
 )
 
 // ANSI escape sequences used to colorize the level token when color is enabled.
@@ -19,27 +37,76 @@ const (
 	ansiGray   = "\033[90m"
 )
 
+// jsonLogs records that stderr carries JSON log lines, so other stderr output
+// (the run summary) is emitted as a log record instead of free text.
+var jsonLogs bool
+
 // setupLogging installs the process-wide slog handler used for the human-facing
 // diagnostics every command writes to stderr (the machine-readable result goes
 // to stdout). It is called once from the root PersistentPreRunE so the flags are
 // honored before any subcommand logs.
 //
-//   - quiet raises the threshold to Warn, so the informational "authenticated",
-//     "user account", etc. lines are suppressed while warnings, errors, and the
-//     result on stdout remain.
+//   - levelName sets the threshold (debug, info, warn, error; default info).
+//     debug adds a line per Jira request.
+//   - quiet raises the threshold to at least Warn, so the informational
+//     "authenticated", "user account", etc. lines are suppressed while
+//     warnings, errors, the run summary, and the result on stdout remain.
 //   - color is enabled only when neither --no-color nor NO_COLOR is set and
 //     stderr is a terminal, matching the https://no-color.org convention.
-func setupLogging(quiet, noColor bool) {
-	level := slog.LevelInfo
-	if quiet {
+//   - every handler is wrapped in util.NewRedactHandler, so credentials never
+//     reach stderr.
+//   - format "json" swaps the terse text handler for slog's JSON handler, one
+//     object per line, for log ingestion; color does not apply there.
+func setupLogging(quiet, noColor bool, levelName, format string) error {
+	level, err := parseLogLevel(levelName)
+	if err != nil {
+		return err
+	}
+	if quiet && level < slog.LevelWarn {
 		level = slog.LevelWarn
 	}
-	slog.SetDefault(slog.New(&cliHandler{
+	jsonLogs = format == logFormatJSON
+	switch format {
+	case "", logFormatText:
+	case logFormatJSON:
+		slog.SetDefault(slog.New(util.NewRedactHandler(
+			slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}),
+		)))
+		return nil
+	default:
+		return fmt.Errorf("invalid log format %q: must be %q or %q", format, logFormatText, logFormatJSON)
+	}
+	slog.SetDefault(slog.New(util.NewRedactHandler(&cliHandler{
 		mu:    &sync.Mutex{},
 		w:     os.Stderr,
 		level: level,
 		color: colorEnabled(noColor),
-	}))
+	})))
+	return nil
+}
+
+// parseLogLevel maps a --log-level / LOG_LEVEL value to a slog level; empty
+// means info.
+func parseLogLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn, or error", s)
+	}
+}
+
+// issueLogger returns the default logger scoped to one issue and operation,
+// so every line a per-issue worker writes carries the same issue/operation
+// keys.
+func issueLogger(key, operation string) *slog.Logger {
+	return slog.With(logKeyIssue, key, logKeyOperation, operation)
 }
 
 // colorEnabled reports whether ANSI color should be emitted. Color is off when
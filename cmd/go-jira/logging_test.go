@@ -2,10 +2,13 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"log/slog"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 // newTestHandler builds a cliHandler writing to buf at the given level/color so
@@ -90,7 +93,93 @@ func TestColorEnabled(t *testing.T) {
 func TestSetupLoggingDoesNotPanic(t *testing.T) {
 	// Restore the default logger after mutating the global.
 	t.Cleanup(func() { slog.SetDefault(slog.Default()) })
-	setupLogging(true, true)
+	if err := setupLogging(true, true, "", ""); err != nil {
+		t.Fatalf("setupLogging: %v", err)
+	}
 	slog.Info("suppressed") // should be dropped (quiet)
 	slog.Warn("kept")       // should pass the threshold
 }
+
+func TestSetupLoggingRejectsUnknownFormat(t *testing.T) {
+	orig, origJSON := slog.Default(), jsonLogs
+	t.Cleanup(func() { slog.SetDefault(orig); jsonLogs = origJSON })
+	if err := setupLogging(false, true, "", "xml"); err == nil {
+		t.Fatal("expected an error for an unknown log format")
+	}
+	if err := setupLogging(false, true, "", logFormatJSON); err != nil {
+		t.Fatalf("json format: %v", err)
+	}
+}
+
+// TestIssueLoggerJSONKeys checks the per-issue lines carry the shared
+// issue/operation/duration keys when rendered as JSON.
+func TestIssueLoggerJSONKeys(t *testing.T) {
+	orig := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(orig) })
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	issueLogger("GAIA-1", actionTransition).Info("issue moved to transition",
+		statusKey, "Done", logKeyDuration, 1500*time.Millisecond)
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("not a JSON line: %v\n%s", err, buf.String())
+	}
+	want := map[string]any{
+		"issue":     "GAIA-1",
+		"operation": "transition",
+		"status":    "Done",
+		"duration":  float64(1500 * time.Millisecond),
+	}
+	for k, v := range want {
+		if line[k] != v {
+			t.Errorf("%s = %v, want %v", k, line[k], v)
+		}
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{in: "", want: slog.LevelInfo},
+		{in: "debug", want: slog.LevelDebug},
+		{in: "INFO", want: slog.LevelInfo},
+		{in: "warning", want: slog.LevelWarn},
+		{in: "error", want: slog.LevelError},
+		{in: "trace", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseLogLevel(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("parseLogLevel(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseLogLevel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestSetupLoggingQuietKeepsStricterLevel checks --quiet raises the threshold
+// to warn but never lowers an explicit error level.
+func TestSetupLoggingQuietKeepsStricterLevel(t *testing.T) {
+	orig := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(orig) })
+	ctx := context.Background()
+
+	if err := setupLogging(true, true, "debug", ""); err != nil {
+		t.Fatal(err)
+	}
+	if slog.Default().Enabled(ctx, slog.LevelInfo) || !slog.Default().Enabled(ctx, slog.LevelWarn) {
+		t.Error("quiet with debug should log warn and above only")
+	}
+	if err := setupLogging(true, true, "error", ""); err != nil {
+		t.Fatal(err)
+	}
+	if slog.Default().Enabled(ctx, slog.LevelWarn) {
+		t.Error("quiet must not lower an explicit error level")
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/appleboy/go-jira/pkg/util"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
@@ -28,21 +31,83 @@ const (
 	flagEnvFile      = "env-file"
 	flagBaseURL      = "base-url"
 	flagInsecure     = "insecure"
+	flagCACert       = "ca-cert"
+	flagProxy        = "proxy"
+	flagHeaders      = "headers"
+	flagClientCert   = "client-cert"
+	flagClientKey    = "client-key"
 	flagUsername     = "username"
 	flagPassword     = "password"
 	flagToken        = "token"
+	flagSessionAuth  = "session-auth"
 	flagRef          = "ref"
+	flagRefFile      = "ref-file"
+	flagRefs         = "refs"
+	flagStdin        = "stdin"
+	flagCommitRange  = "commit-range"
+	flagPRNumber     = "pr-number"
 	flagIssueFormat  = "issue-format"
+	flagTrailersOnly = "trailers-only"
+	flagTrailers     = "trailers"
+	flagProjects     = "projects"
+	flagExcludeProjs = "exclude-projects"
+	flagValidateProj = "validate-projects"
+	flagIssueTypes   = "issue-types"
+	flagFilterJQL    = "filter-jql"
+	flagProjectCreds = "project-credentials"
+	flagMaxIssues    = "max-issues"
+	flagMaxIssuesOn  = "max-issues-mode"
 	flagToTransition = "to-transition"
+	flagBranchRules  = "branch-transitions"
+	flagClosingRule  = "closing-transition"
+	flagBranch       = "branch"
+	flagCommitTypes  = "commit-types"
+	flagTypeRules    = "commit-type-transitions"
 	flagResolution   = "resolution"
 	flagComment      = "comment"
 	flagAssignee     = "assignee"
 	flagMarkdown     = "markdown"
+	flagCodeLang     = "code-default-lang"
+	flagMermaidURL   = "mermaid-image-url"
+	flagMarkdownBase = "markdown-base-url"
+	flagLinkIssues   = "link-issue-keys"
+	flagMentions     = "mentions"
+	flagMentionsFile = "mentions-file"
+	flagMentionMode  = "mention-mode"
+	flagImagesAttach = "images-attached"
+	flagHeadingShift = "heading-offset"
+	flagTOC          = "toc"
+	flagLineBreaks   = "line-breaks"
 	flagDebug        = "debug"
 
+	// Scheduling flags for run.
+	flagJitter         = "jitter"
+	flagJitterKey      = "jitter-key"
+	flagMaxConcurrency = "max-concurrency"
+	flagFailMode       = "fail-mode"
+	flagFailOnNoIssues = "fail-on-no-issues"
+	flagFailFast       = "fail-fast"
+	flagIssueTimeout   = "issue-timeout"
+	flagReportFile     = "report-file"
+	flagMetricsFile    = "metrics-file"
+	flagMetricsPush    = "metrics-pushgateway"
+	flagPrecheck       = "precheck"
+	flagAuditLog       = "audit-log"
+	flagPreHook        = "pre-hook"
+	flagPostHook       = "post-hook"
+
+	// Kerberos / SPNEGO flags.
+	flagKrb5Keytab    = "krb5-keytab"
+	flagKrb5Principal = "krb5-principal"
+	flagKrb5CCache    = "krb5-ccache"
+	flagKrb5Config    = "krb5-config"
+	flagKrb5SPN       = "krb5-spn"
+
 	// Global presentation flags, registered as persistent flags on the root.
-	flagQuiet   = "quiet"
-	flagNoColor = "no-color"
+	flagQuiet     = "quiet"
+	flagNoColor   = "no-color"
+	flagLogFormat = "log-format"
+	flagLogLevel  = "log-level"
 
 	// Data subcommand flags (search/create/update/get/sprints/boards/link).
 	flagOutput      = "output"
@@ -226,10 +291,12 @@ requests are not retried automatically.
 
 Composability:
   Diagnostics go to stderr, results to stdout. Use --quiet to drop the
-  informational stderr logs, and --no-color (or the NO_COLOR env var) to
-  disable ANSI color. Text-bearing flags (--ref, --comment, --description,
+  informational stderr logs, --log-level and --log-format to tune them, and
+  --no-color (or the NO_COLOR env var) to disable ANSI color. Text-bearing flags (--ref, --comment, --description,
   --jql) accept "-" to read the value from stdin, e.g.
-    git log -1 --format=%B | go-jira run --ref - --to-transition Done`,
+    git log -1 --format=%B | go-jira run --ref - --to-transition Done
+  and run --stdin scans piped text for issue keys, e.g.
+    git log v1.0..HEAD | go-jira run --stdin --to-transition Done`,
 		Example: `  # Show the authenticated user and active auth mode
   go-jira whoami
 
@@ -259,16 +326,34 @@ Composability:
 	// Presentation flags shared by every subcommand. PersistentPreRunE installs
 	// the matching slog handler before any command logs.
 	cmd.PersistentFlags().BoolP(flagQuiet, "q", false,
-		"Suppress informational logs on stderr; warnings, errors, and result output remain")
+		"Suppress informational logs on stderr; warnings, errors, the run summary, and result "+
+			"output remain (env: QUIET / INPUT_QUIET)")
+	cmd.PersistentFlags().String(flagLogLevel, "",
+		`Minimum log level on stderr: debug, info (default), warn, or error (env: LOG_LEVEL / INPUT_LOG_LEVEL)`)
 	cmd.PersistentFlags().Bool(flagNoColor, false,
 		"Disable ANSI color in log output (also honored via the NO_COLOR env var)")
+	cmd.PersistentFlags().String(flagLogFormat, "",
+		`Log format on stderr: "text" (default) or "json" for log ingestion (env: LOG_FORMAT / INPUT_LOG_FORMAT)`)
 	cmd.PersistentFlags().Duration(flagTimeout, 0,
 		"Maximum time to wait for the operation to complete, e.g. 30s or 2m; "+
 			"0 uses the per-command default so agents can enforce a time budget")
 	cmd.PersistentPreRunE = func(c *cobra.Command, _ []string) error {
 		quiet, _ := c.Flags().GetBool(flagQuiet)
+		if !flagChanged(c, flagQuiet) {
+			quiet = util.ToBool(util.GetGlobalValue("quiet"))
+		}
 		noColor, _ := c.Flags().GetBool(flagNoColor)
-		setupLogging(quiet, noColor)
+		format := util.GetGlobalValue("log_format")
+		if flagChanged(c, flagLogFormat) {
+			format, _ = c.Flags().GetString(flagLogFormat)
+		}
+		level := util.GetGlobalValue("log_level")
+		if flagChanged(c, flagLogLevel) {
+			level, _ = c.Flags().GetString(flagLogLevel)
+		}
+		if err := setupLogging(quiet, noColor, level, strings.ToLower(format)); err != nil {
+			return &cliError{code: exitUsage, kind: kindUsage, message: err.Error(), err: err}
+		}
 		return nil
 	}
 
@@ -285,6 +370,8 @@ Composability:
 		newLoginCmd(),
 		newLogoutCmd(),
 		newWhoamiCmd(),
+		newCheckCmd(),
+		newMetaCmd(),
 		newTokenCmd(),
 		newBrokerCmd(),
 		newConfigCmd(),
@@ -293,6 +380,7 @@ Composability:
 		newUpdateCmd(),
 		newDeleteCmd(),
 		newGetCmd(),
+		newTransitionsCmd(),
 		newSprintsCmd(),
 		newEpicsCmd(),
 		newBoardsCmd(),
@@ -331,6 +419,13 @@ func addOutputFlag(cmd *cobra.Command) {
 		"Output format: json|text (env: OUTPUT / INPUT_OUTPUT)")
 }
 
+// addAuditLogFlag registers --audit-log for the commands that change issues.
+func addAuditLogFlag(cmd *cobra.Command) {
+	cmd.Flags().String(flagAuditLog, "",
+		"Append every mutating Jira call (action, issue, actor, payload hash) as a JSON line to "+
+			"this file (env: AUDIT_LOG / INPUT_AUDIT_LOG)")
+}
+
 // addCustomFieldFlags registers the configurable epic-link and sprint custom
 // field IDs. These are consumed by create and update (when setting the fields)
 // and by search (which appends them to the default field selection). Field IDs
@@ -349,6 +444,26 @@ func addAuthFlags(cmd *cobra.Command) {
 	cmd.Flags().String(flagUsername, "", "Jira username (env: USERNAME / INPUT_USERNAME)")
 	cmd.Flags().String(flagPassword, "", "Jira password (prefer env: PASSWORD / INPUT_PASSWORD)")
 	cmd.Flags().String(flagToken, "", "Jira API token (prefer env: TOKEN / INPUT_TOKEN)")
+	cmd.Flags().Bool(flagSessionAuth, false,
+		"Log in with username/password through the legacy session cookie endpoint instead of "+
+			"Basic Auth (env: SESSION_AUTH / INPUT_SESSION_AUTH)")
+	addKerberosFlags(cmd)
+}
+
+// addKerberosFlags registers the Kerberos / SPNEGO credential flags shared by
+// every command that authenticates against Jira.
+func addKerberosFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagKrb5Keytab, "",
+		"Kerberos keytab for SPNEGO auth, used with --krb5-principal (env: KRB5_KEYTAB / INPUT_KRB5_KEYTAB)")
+	cmd.Flags().String(flagKrb5Principal, "",
+		"Kerberos principal (user or user@REALM) to use from the keytab "+
+			"(env: KRB5_PRINCIPAL / INPUT_KRB5_PRINCIPAL)")
+	cmd.Flags().String(flagKrb5CCache, "",
+		"Kerberos ticket cache for SPNEGO auth, e.g. one filled by kinit (env: KRB5_CCACHE / INPUT_KRB5_CCACHE)")
+	cmd.Flags().String(flagKrb5Config, "",
+		"krb5.conf path, default /etc/krb5.conf (env: KRB5_CONFIG / INPUT_KRB5_CONFIG)")
+	cmd.Flags().String(flagKrb5SPN, "",
+		"Service principal to request a ticket for, default HTTP/<jira host> (env: KRB5_SPN / INPUT_KRB5_SPN)")
 }
 
 // addEditableIssueFlags registers the issue field flags shared by create and
@@ -361,7 +476,8 @@ func addEditableIssueFlags(cmd *cobra.Command) {
 	cmd.Flags().String(flagDescription, "", `Issue description body (pass "-" to read from stdin)`)
 	cmd.Flags().String(flagComponents, "", "Comma-separated component names")
 	cmd.Flags().String(flagLabels, "", "Comma-separated labels")
-	cmd.Flags().String(flagEpic, "", "Epic key for the epic-link field, e.g. GAIA-42")
+	cmd.Flags().String(flagEpic, "",
+		"Epic key for the epic-link field (the parent field on team-managed projects), e.g. GAIA-42")
 	cmd.Flags().Int(flagSprint, 0, "Sprint ID for the sprint field")
 }
 
@@ -371,6 +487,19 @@ func addCommonFlags(cmd *cobra.Command) {
 	cmd.Flags().String(flagBaseURL, "", "Jira base URL (env: BASE_URL / INPUT_BASE_URL)")
 	cmd.Flags().
 		Bool(flagInsecure, false, "Skip TLS verification (env: INSECURE / INPUT_INSECURE)")
+	cmd.Flags().String(flagProxy, "",
+		"Proxy URL for Jira requests (http, https, or socks5); defaults to HTTP_PROXY / "+
+			"HTTPS_PROXY / NO_PROXY (env: PROXY / INPUT_PROXY)")
+	cmd.Flags().String(flagHeaders, "",
+		`Extra headers for every Jira request, e.g. "X-Forwarded-User: ci;X-Org: platform" `+
+			"(env: HEADERS / INPUT_HEADERS)")
+	cmd.Flags().String(flagCACert, "",
+		"PEM bundle of extra CAs to trust, as a file path or PEM content; "+
+			"use instead of --insecure for internal CAs (env: CA_CERT / INPUT_CA_CERT)")
+	cmd.Flags().String(flagClientCert, "",
+		"mTLS client certificate, as a file path or PEM content (env: TLS_CERT / INPUT_TLS_CERT)")
+	cmd.Flags().String(flagClientKey, "",
+		"mTLS client private key, as a file path or PEM content (env: TLS_KEY / INPUT_TLS_KEY)")
 	cmd.Flags().Bool(flagDebug, false, "Dump resolved configuration (env: DEBUG / INPUT_DEBUG)")
 }
 
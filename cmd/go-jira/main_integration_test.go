@@ -1,153 +1,46 @@
 package main
 
 import (
-	"encoding/json"
 	"net/http"
-	"net/http/httptest"
 	"os"
 	"strings"
-	"sync"
 	"testing"
 
-	jira "github.com/andygrunwald/go-jira"
+	"github.com/appleboy/go-jira/pkg/jiratest"
 )
 
-// setupTestServer creates a comprehensive mock Jira server for integration tests
-func setupTestServer(options testServerOptions) *httptest.Server {
-	var mu sync.Mutex
-	requestLog := make([]string, 0)
-
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		mu.Lock()
-		requestLog = append(requestLog, r.Method+" "+r.URL.Path)
-		mu.Unlock()
-
-		// Handle /rest/api/2/myself endpoint
-		if r.URL.Path == "/rest/api/2/myself" {
-			if options.selfError {
-				w.WriteHeader(http.StatusUnauthorized)
-				_, _ = w.Write([]byte(`{"errorMessages":["Unauthorized"]}`))
-				return
-			}
-			w.WriteHeader(http.StatusOK)
-			user := jira.User{
-				Name:         "testuser",
-				DisplayName:  "Test User",
-				EmailAddress: "test@example.com",
-			}
-			_ = json.NewEncoder(w).Encode(user)
-			return
-		}
-
-		// Handle /rest/api/2/user endpoint (get user by username)
-		if r.URL.Path == "/rest/api/2/user" {
-			if options.assigneeError {
-				w.WriteHeader(http.StatusNotFound)
-				_, _ = w.Write([]byte(`{"errorMessages":["User not found"]}`))
-				return
-			}
-			w.WriteHeader(http.StatusOK)
-			user := jira.User{
-				Name:         "assignee",
-				DisplayName:  "Assignee User",
-				EmailAddress: "assignee@example.com",
-			}
-			_ = json.NewEncoder(w).Encode(user)
-			return
-		}
-
-		// Handle /rest/api/2/resolution endpoint
-		if r.URL.Path == "/rest/api/2/resolution" {
-			if options.resolutionError {
-				w.WriteHeader(http.StatusInternalServerError)
-				_, _ = w.Write([]byte(`{"errorMessages":["Internal error"]}`))
-				return
-			}
-			w.WriteHeader(http.StatusOK)
-			resolutions := []jira.Resolution{
-				{ID: "1", Name: "Fixed"},
-				{ID: "2", Name: "Done"},
-			}
-			_ = json.NewEncoder(w).Encode(resolutions)
-			return
-		}
-
-		// Handle issue retrieval /rest/api/2/issue/{issueKey}
-		if strings.HasPrefix(r.URL.Path, "/rest/api/2/issue/") && r.Method == http.MethodGet {
-			issueKey := r.URL.Path[len("/rest/api/2/issue/"):]
-
-			// Check if it's a comment or assignee endpoint
-			if strings.Contains(issueKey, "/comment") || strings.Contains(issueKey, "/assignee") ||
-				strings.Contains(issueKey, "/transitions") {
-				goto handleOtherEndpoints
-			}
-
-			if options.issueError {
-				w.WriteHeader(http.StatusNotFound)
-				_, _ = w.Write([]byte(`{"errorMessages":["Issue not found"]}`))
-				return
-			}
-			w.WriteHeader(http.StatusOK)
-			issue := jira.Issue{
-				Key: issueKey,
-				Fields: &jira.IssueFields{
-					Summary: "Test issue " + issueKey,
-					Status: &jira.Status{
-						Name: "Open",
-					},
-				},
-				Transitions: []jira.Transition{
-					{ID: "1", Name: "Done"},
-				},
-			}
-			_ = json.NewEncoder(w).Encode(issue)
-			return
-		}
-
-	handleOtherEndpoints:
-		// Handle transitions /rest/api/2/issue/{issueKey}/transitions
-		if strings.Contains(r.URL.Path, "/transitions") {
-			if r.Method == http.MethodPost {
-				if options.transitionError {
-					w.WriteHeader(http.StatusBadRequest)
-					_, _ = w.Write([]byte(`{"errorMessages":["Invalid transition"]}`))
-					return
-				}
-				w.WriteHeader(http.StatusNoContent)
-				return
-			}
-		}
-
-		// Handle assignee updates /rest/api/2/issue/{issueKey}/assignee
-		if strings.Contains(r.URL.Path, "/assignee") {
-			if options.assigneeUpdateError {
-				w.WriteHeader(http.StatusForbidden)
-				_, _ = w.Write([]byte(`{"errorMessages":["Permission denied"]}`))
-				return
-			}
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-
-		// Handle comments /rest/api/2/issue/{issueKey}/comment
-		if strings.Contains(r.URL.Path, "/comment") {
-			if options.commentError {
-				w.WriteHeader(http.StatusBadRequest)
-				_, _ = w.Write([]byte(`{"errorMessages":["Invalid comment"]}`))
-				return
-			}
-			w.WriteHeader(http.StatusCreated)
-			comment := jira.Comment{
-				ID:   "12345",
-				Body: "Test comment",
-			}
-			_ = json.NewEncoder(w).Encode(comment)
-			return
-		}
-
-		// Default response
-		w.WriteHeader(http.StatusNotFound)
-	}))
+// setupTestServer creates a mock Jira server for integration tests, failing
+// the endpoints options select.
+func setupTestServer(options testServerOptions) *jiratest.Server {
+	var failures []jiratest.Failure
+	fail := func(endpoint jiratest.Endpoint, status int, message string) {
+		failures = append(failures, jiratest.Failure{
+			Endpoint: endpoint, Status: status, Message: message,
+		})
+	}
+	if options.selfError {
+		fail(jiratest.EndpointSelf, http.StatusUnauthorized, "Unauthorized")
+	}
+	if options.assigneeError {
+		fail(jiratest.EndpointUser, http.StatusNotFound, "User not found")
+	}
+	if options.resolutionError {
+		fail(jiratest.EndpointResolutions, http.StatusInternalServerError, "Internal error")
+	}
+	if options.issueError {
+		fail(jiratest.EndpointSearch, http.StatusNotFound, "Issue not found")
+		fail(jiratest.EndpointIssue, http.StatusNotFound, "Issue not found")
+	}
+	if options.transitionError {
+		fail(jiratest.EndpointTransition, http.StatusBadRequest, "Invalid transition")
+	}
+	if options.assigneeUpdateError {
+		fail(jiratest.EndpointAssignee, http.StatusForbidden, "Permission denied")
+	}
+	if options.commentError {
+		fail(jiratest.EndpointComment, http.StatusBadRequest, "Invalid comment")
+	}
+	return jiratest.NewServer(jiratest.Config{Failures: failures})
 }
 
 type testServerOptions struct {
@@ -215,6 +108,32 @@ func TestRun(t *testing.T) {
 			serverOptions: testServerOptions{},
 			wantErr:       false,
 		},
+		{
+			name: "pre hook failure aborts the run",
+			envVars: map[string]string{
+				"INPUT_REF":        "ABC-123",
+				"INPUT_TOKEN":      "testtoken",
+				"INPUT_INSECURE":   "true",
+				"INPUT_TRANSITION": "Done",
+				"INPUT_PRE_HOOK":   "exit 3",
+			},
+			serverOptions: testServerOptions{},
+			wantErr:       true,
+			errContains:   "pre_hook: exit status 3",
+		},
+		{
+			name: "post hook failure fails the run",
+			envVars: map[string]string{
+				"INPUT_REF":       "ABC-123",
+				"INPUT_TOKEN":     "testtoken",
+				"INPUT_INSECURE":  "true",
+				"INPUT_COMMENT":   "Test comment",
+				"INPUT_POST_HOOK": "exit 4",
+			},
+			serverOptions: testServerOptions{},
+			wantErr:       true,
+			errContains:   "post_hook: exit status 4",
+		},
 		{
 			name: "missing base_url",
 			envVars: map[string]string{
@@ -381,13 +300,19 @@ func TestRun(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// When the suite itself runs in GitHub Actions, keep run from
+			// reading the workflow's event payload or writing its outputs.
+			t.Setenv(envGitHubEventPath, "")
+			t.Setenv(envGitHubOutput, "")
+			t.Setenv(envGitHubStepSummary, "")
+
 			// Save and clear environment
 			originalEnv := make(map[string]string)
 			envVars := []string{
 				"INPUT_BASE_URL", "INPUT_INSECURE", "INPUT_USERNAME", "INPUT_PASSWORD",
 				"INPUT_TOKEN", "INPUT_REF", "INPUT_ISSUE_FORMAT", "INPUT_TRANSITION",
 				"INPUT_RESOLUTION", "INPUT_COMMENT", "INPUT_ASSIGNEE", "INPUT_MARKDOWN",
-				"INPUT_DEBUG",
+				"INPUT_DEBUG", "INPUT_PRE_HOOK", "INPUT_POST_HOOK",
 			}
 			for _, key := range envVars {
 				originalEnv[key] = os.Getenv(key)
@@ -437,6 +362,32 @@ func TestRun(t *testing.T) {
 	}
 }
 
+// TestRunTransitionNotFound verifies that a transition no issue offers, as on
+// a re-run after the issues are already Done, skips the issues with a warning
+// instead of failing the run.
+func TestRunTransitionNotFound(t *testing.T) {
+	t.Setenv(envGitHubEventPath, "")
+	t.Setenv(envGitHubOutput, "")
+	t.Setenv(envGitHubStepSummary, "")
+	for _, key := range []string{
+		"INPUT_USERNAME", "INPUT_PASSWORD", "INPUT_ISSUE_FORMAT", "INPUT_RESOLUTION",
+		"INPUT_COMMENT", "INPUT_ASSIGNEE", "INPUT_PRE_HOOK", "INPUT_POST_HOOK",
+	} {
+		t.Setenv(key, "")
+	}
+	server := setupTestServer(testServerOptions{})
+	defer server.Close()
+	t.Setenv("INPUT_BASE_URL", server.URL)
+	t.Setenv("INPUT_TOKEN", "testtoken")
+	t.Setenv("INPUT_INSECURE", "true")
+	t.Setenv("INPUT_REF", "ABC-123 DEF-456")
+	t.Setenv("INPUT_TRANSITION", "Shipped")
+
+	if err := run(nil); err != nil {
+		t.Errorf("run error = %v, want the issues skipped", err)
+	}
+}
+
 func TestRunWithEnvFile(t *testing.T) {
 	// Create a temporary .env file
 	envContent := `INPUT_BASE_URL=https://jira.example.com
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/appleboy/go-jira/pkg/issuekey"
+	"github.com/appleboy/go-jira/pkg/markdown"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// markdownOptions returns the options a --markdown comment is converted
+// with. Email mentions are looked up through jiraClient.
+func markdownOptions(ctx context.Context, config Config, jiraClient *jira.Client) markdown.Options {
+	opts := markdown.Options{
+		DefaultCodeLanguage: config.codeDefaultLang,
+		MermaidImageURL:     config.mermaidImageURL,
+		BaseURL:             config.markdownBaseURL,
+		ImagesAttached:      config.imagesAttached,
+		HeadingOffset:       config.headingOffset,
+	}
+	// validateConfig already checked these settings.
+	opts.TOC, _ = parseTOCMode(config.toc)
+	opts.LineBreaks, _ = parseLineBreakStyle(config.lineBreaks)
+	opts.Mentions, _ = loadMentions(config)
+	opts.MentionMode, _ = parseMentionMode(config.mentionMode)
+	opts.ResolveEmail = newEmailMentions(ctx, jiraClient).resolve
+	if config.linkIssueKeys {
+		// validateConfig already compiled the pattern.
+		opts.IssueURL = strings.TrimSuffix(config.baseURL, "/") + "/browse/"
+		opts.IssuePattern, _ = issuekey.Compile(config.issuePattern)
+	}
+	return opts
+}
+
+// parseTOCMode parses --toc: empty for none, "macro", or "list".
+func parseTOCMode(s string) (markdown.TOCMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none":
+		return markdown.TOCNone, nil
+	case "macro":
+		return markdown.TOCMacro, nil
+	case "list":
+		return markdown.TOCList, nil
+	}
+	return 0, fmt.Errorf("invalid toc %q: want macro or list", s)
+}
+
+// parseLineBreakStyle parses --line-breaks: empty or "preserve", "space", or
+// "hard".
+func parseLineBreakStyle(s string) (markdown.LineBreakStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "preserve":
+		return markdown.LineBreakPreserve, nil
+	case "space":
+		return markdown.LineBreakSpace, nil
+	case "hard":
+		return markdown.LineBreakHard, nil
+	}
+	return 0, fmt.Errorf("invalid line_breaks %q: want preserve, space, or hard", s)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/appleboy/go-jira/pkg/markdown"
+)
+
+func TestParseTOCMode(t *testing.T) {
+	for in, want := range map[string]markdown.TOCMode{
+		"": markdown.TOCNone, "none": markdown.TOCNone,
+		"Macro": markdown.TOCMacro, "list": markdown.TOCList,
+	} {
+		if got, err := parseTOCMode(in); err != nil || got != want {
+			t.Errorf("parseTOCMode(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseTOCMode("tree"); err == nil {
+		t.Error(`parseTOCMode("tree"): want an error`)
+	}
+}
+
+func TestParseLineBreakStyle(t *testing.T) {
+	for in, want := range map[string]markdown.LineBreakStyle{
+		"": markdown.LineBreakPreserve, "preserve": markdown.LineBreakPreserve,
+		"Space": markdown.LineBreakSpace, "hard": markdown.LineBreakHard,
+	} {
+		if got, err := parseLineBreakStyle(in); err != nil || got != want {
+			t.Errorf("parseLineBreakStyle(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseLineBreakStyle("br"); err == nil {
+		t.Error(`parseLineBreakStyle("br"): want an error`)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// What run does when more issues than --max-issues are extracted.
+const (
+	maxIssuesAbort    = "abort"
+	maxIssuesTruncate = "truncate"
+)
+
+// validateMaxIssuesMode checks the --max-issues-mode value; empty means
+// abort.
+func validateMaxIssuesMode(mode string) error {
+	switch strings.ToLower(mode) {
+	case "", maxIssuesAbort, maxIssuesTruncate:
+		return nil
+	}
+	return fmt.Errorf("max_issues_mode must be %s or %s, got %q", maxIssuesAbort, maxIssuesTruncate, mode)
+}
+
+// capIssues enforces --max-issues before any issue is touched, protecting
+// against a bad pattern or ref mass-transitioning hundreds of tickets. Over
+// the cap the run aborts, or in truncate mode keeps the first max keys in
+// ref order and records the rest as skipped. A max of 0 disables the cap.
+func capIssues(ctx context.Context, keys []string, limit int, mode string) ([]string, error) {
+	if limit <= 0 || len(keys) <= limit {
+		return keys, nil
+	}
+	if !strings.EqualFold(mode, maxIssuesTruncate) {
+		return nil, fmt.Errorf("%d issue keys exceed max_issues (%d); narrow the ref or raise the cap",
+			len(keys), limit)
+	}
+	slog.Warn("too many issue keys; processing only the first max_issues",
+		"found", len(keys), "max_issues", limit)
+	report := reportFrom(ctx)
+	for _, k := range keys[limit:] {
+		report.record(k, actionFetch, outcomeSkipped, fmt.Sprintf("over the max_issues cap of %d", limit))
+	}
+	return keys[:limit], nil
+}
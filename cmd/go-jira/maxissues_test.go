@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCapIssues(t *testing.T) {
+	keys := []string{"GAIA-1", "GAIA-2", "GAIA-3"}
+
+	tests := []struct {
+		name    string
+		limit   int
+		mode    string
+		want    []string
+		wantErr string
+	}{
+		{name: "no cap", limit: 0, want: keys},
+		{name: "under cap", limit: 3, want: keys},
+		{name: "abort by default", limit: 2, wantErr: "3 issue keys exceed max_issues (2)"},
+		{name: "abort", limit: 2, mode: "abort", wantErr: "exceed max_issues"},
+		{name: "truncate", limit: 2, mode: "Truncate", want: []string{"GAIA-1", "GAIA-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := newRunReport("https://jira.example.com")
+			report.setKeys(keys)
+			ctx := withReport(context.Background(), report)
+			got, err := capIssues(ctx, keys, tt.limit, tt.mode)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMaxIssuesMode(t *testing.T) {
+	for _, mode := range []string{"", "abort", "TRUNCATE"} {
+		if err := validateMaxIssuesMode(mode); err != nil {
+			t.Errorf("validateMaxIssuesMode(%q) = %v", mode, err)
+		}
+	}
+	if err := validateMaxIssuesMode("drop"); err == nil {
+		t.Error("validateMaxIssuesMode(\"drop\") should fail")
+	}
+}
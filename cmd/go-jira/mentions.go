@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/appleboy/go-jira/pkg/markdown"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// parseMentions parses --mentions, mapping Git usernames to Jira users for
+// Markdown @mentions: "gituser=jirauser" pairs separated by semicolons or
+// newlines, or a JSON object such as {"octocat": "accountid:5b10ac..."}.
+func parseMentions(s string) (map[string]string, error) {
+	mentions := map[string]string{}
+	if s = strings.TrimSpace(s); strings.HasPrefix(s, "{") {
+		if err := json.Unmarshal([]byte(s), &mentions); err != nil {
+			return nil, fmt.Errorf("invalid mentions JSON: %w", err)
+		}
+		return mentions, nil
+	}
+	for _, part := range splitRules(s) {
+		git, user, ok := strings.Cut(part, "=")
+		git = strings.TrimPrefix(strings.TrimSpace(git), "@")
+		user = strings.TrimSpace(user)
+		if !ok || git == "" || user == "" {
+			return nil, fmt.Errorf("invalid mention %q: want \"gituser=jirauser\"", part)
+		}
+		mentions[git] = user
+	}
+	return mentions, nil
+}
+
+// parseMentionMode parses --mention-mode: "loose" (or empty), "strict", or
+// "off".
+func parseMentionMode(s string) (markdown.MentionMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "loose":
+		return markdown.MentionLoose, nil
+	case "strict":
+		return markdown.MentionStrict, nil
+	case "off":
+		return markdown.MentionOff, nil
+	}
+	return 0, fmt.Errorf("invalid mention_mode %q: want loose, strict, or off", s)
+}
+
+// loadMentions returns the mention mapping of --mentions-file and
+// --mentions, the inline entries winning over the file's.
+func loadMentions(config Config) (map[string]string, error) {
+	mentions := map[string]string{}
+	if config.mentionsFile != "" {
+		data, err := os.ReadFile(config.mentionsFile) // #nosec G304 -- the user's own mapping
+		if err != nil {
+			return nil, fmt.Errorf("read mentions_file: %w", err)
+		}
+		if mentions, err = parseMentions(string(data)); err != nil {
+			return nil, fmt.Errorf("mentions_file: %w", err)
+		}
+	}
+	inline, err := parseMentions(config.mentions)
+	if err != nil {
+		return nil, err
+	}
+	for git, user := range inline {
+		mentions[git] = user
+	}
+	return mentions, nil
+}
+
+// emailMentions resolves @user@example.com mentions in a Markdown comment
+// to Jira users with findUserByEmail, caching every answer, misses and
+// failures included, for the rest of the run.
+type emailMentions struct {
+	ctx    context.Context
+	client *jira.Client
+	mu     sync.Mutex
+	users  map[string]string
+}
+
+func newEmailMentions(ctx context.Context, client *jira.Client) *emailMentions {
+	return &emailMentions{ctx: ctx, client: client, users: map[string]string{}}
+}
+
+// resolve is the markdown.Options ResolveEmail of m.
+func (m *emailMentions) resolve(email string) (string, bool) {
+	key := strings.ToLower(email)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[key]
+	if !ok {
+		var err error
+		if user, err = findUserByEmail(m.ctx, m.client, email); err != nil {
+			slog.Warn("cannot look up the mentioned user", "email", email, "error", err)
+		}
+		m.users[key] = user
+	}
+	return user, user != ""
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/appleboy/go-jira/pkg/markdown"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", in: "", want: map[string]string{}},
+		{
+			name: "pairs",
+			in:   "appleboy=bo-yi.wu; @octocat = accountid:5b10\nmona=mona.lisa",
+			want: map[string]string{
+				"appleboy": "bo-yi.wu", "octocat": "accountid:5b10", "mona": "mona.lisa",
+			},
+		},
+		{
+			name: "json",
+			in:   ` {"appleboy": "bo-yi.wu"}`,
+			want: map[string]string{"appleboy": "bo-yi.wu"},
+		},
+		{name: "missing user", in: "appleboy=", wantErr: true},
+		{name: "bad json", in: `{"appleboy": 1}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMentions(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMentions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseMentions() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("parseMentions()[%q] = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMentionMode(t *testing.T) {
+	for in, want := range map[string]markdown.MentionMode{
+		"": markdown.MentionLoose, "loose": markdown.MentionLoose,
+		"Strict": markdown.MentionStrict, "off": markdown.MentionOff,
+	} {
+		if got, err := parseMentionMode(in); err != nil || got != want {
+			t.Errorf("parseMentionMode(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseMentionMode("never"); err == nil {
+		t.Error(`parseMentionMode("never"): want an error`)
+	}
+}
+
+func TestLoadMentions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mentions.json")
+	if err := os.WriteFile(path, []byte(`{"appleboy": "old", "mona": "mona.lisa"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadMentions(Config{mentionsFile: path, mentions: "appleboy=bo-yi.wu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["appleboy"] != "bo-yi.wu" || got["mona"] != "mona.lisa" {
+		t.Errorf("loadMentions() = %v", got)
+	}
+	if _, err := loadMentions(Config{mentionsFile: path + ".missing"}); err == nil {
+		t.Error("loadMentions() with a missing file: want an error")
+	}
+}
+
+func TestEmailMentions(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/rest/api/2/user/search" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		// Answer like Server/DC: the search needs the username parameter.
+		switch r.URL.Query().Get("username") {
+		case "":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errorMessages":["username is required"]}`))
+		case "bo-yi.wu+ci@example.com":
+			_, _ = w.Write([]byte(`[{"name":"appleboy","emailAddress":"Bo-Yi.Wu+ci@example.com"}]`))
+		case "team@example.com":
+			_, _ = w.Write([]byte(`[{"name":"a","emailAddress":"a@example.com"},` +
+				`{"name":"b","emailAddress":"b@example.com"}]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := newEmailMentions(context.Background(), client)
+	if user, ok := m.resolve("bo-yi.wu+ci@example.com"); !ok || user != "appleboy" {
+		t.Errorf("resolve() = %q, %v, want appleboy", user, ok)
+	}
+	if user, ok := m.resolve("team@example.com"); ok {
+		t.Errorf("ambiguous: resolve() = %q, want no user", user)
+	}
+	calls = 0
+	if _, ok := m.resolve("Bo-Yi.Wu+ci@example.com"); !ok || calls != 0 {
+		t.Errorf("cached: ok = %v after %d requests, want a hit with none", ok, calls)
+	}
+}
+
+func TestFindUserByEmailCloud(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") != "dev@example.com" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		// Cloud hides the address and has no login name.
+		_, _ = w.Write([]byte(`[{"accountId":"5b10ac8d82e05b22cc7d4ef5"}]`))
+	}))
+	defer server.Close()
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := findUserByEmail(context.Background(), client, "dev@example.com")
+	if err != nil || user != "accountid:5b10ac8d82e05b22cc7d4ef5" {
+		t.Errorf("findUserByEmail() = %q, %v", user, err)
+	}
+}
+
+func TestFindUserByEmailOtherAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// A fuzzy search can return a single user whose visible address is
+		// someone else's.
+		_, _ = w.Write([]byte(`[{"name":"devops","emailAddress":"devops@example.com"}]`))
+	}))
+	defer server.Close()
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := findUserByEmail(context.Background(), client, "dev@example.com")
+	if err != nil || user != "" {
+		t.Errorf("findUserByEmail() = %q, %v, want no user", user, err)
+	}
+}
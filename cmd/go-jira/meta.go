@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/spf13/cobra"
+)
+
+// metaItem is one entry of server metadata listed by `meta`. Category is set
+// for statuses, Inward and Outward for issue link types.
+type metaItem struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category,omitempty"`
+	Inward      string `json:"inward,omitempty"`
+	Outward     string `json:"outward,omitempty"`
+}
+
+// metaFetcher lists one kind of server metadata.
+type metaFetcher func(ctx context.Context, jiraClient *jira.Client) ([]metaItem, error)
+
+// newMetaCmd builds the `meta` command group, which dumps the server metadata
+// run and the data commands take by name, so values such as --resolution can
+// be copied exactly as a localized instance spells them.
+func newMetaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "meta",
+		Short:   "List server metadata: resolutions, priorities, statuses, link types",
+		GroupID: groupConfig,
+		Long: `List the metadata Jira knows by name, as the server reports it for the
+current user's locale:
+  resolutions  values for --resolution / RESOLUTION
+  priorities   issue priorities
+  statuses     workflow statuses and their categories
+  link-types   values for link --link-type, with inward/outward wording
+
+run matches these names case-insensitively.`,
+		Example: `  # Which resolutions can RESOLUTION name on this instance?
+  go-jira meta resolutions --output text
+
+  # Link types as JSON
+  go-jira meta link-types`,
+		SilenceUsage: true,
+	}
+	cmd.AddCommand(
+		newMetaListCmd("resolutions", "List issue resolutions", fetchResolutions),
+		newMetaListCmd("priorities", "List issue priorities", fetchPriorities),
+		newMetaListCmd("statuses", "List workflow statuses", fetchStatuses),
+		newMetaListCmd("link-types", "List issue link types", fetchLinkTypes),
+	)
+	return cmd
+}
+
+// newMetaListCmd builds one `meta` subcommand listing what fetch returns.
+func newMetaListCmd(use, short string, fetch metaFetcher) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          use,
+		Short:        short,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runMetaList(cmd, fetch)
+		},
+	}
+	addCommonFlags(cmd)
+	addOAuthFlags(cmd)
+	addAuthFlags(cmd)
+	addOutputFlag(cmd)
+	return cmd
+}
+
+func runMetaList(cmd *cobra.Command, fetch metaFetcher) error {
+	config, err := loadDataConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cmdContextWithTimeout(cmd, time.Minute)
+	defer cancel()
+
+	jiraClient, err := resolveJiraClient(ctx, config)
+	if err != nil {
+		return err
+	}
+	items, err := fetch(ctx, jiraClient)
+	if err != nil {
+		return err
+	}
+
+	return emitResult(config, items, func() {
+		for _, it := range items {
+			detail := it.Description
+			switch {
+			case it.Category != "":
+				detail = it.Category
+			case it.Inward != "" || it.Outward != "":
+				detail = it.Outward + " / " + it.Inward
+			}
+			fmt.Fprintf(os.Stdout, "%s\t%s\t%s\n", it.ID, it.Name, detail)
+		}
+	})
+}
+
+func fetchResolutions(ctx context.Context, jiraClient *jira.Client) ([]metaItem, error) {
+	list, resp, err := jiraClient.Resolution.GetListWithContext(ctx)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing resolutions: %w", err)
+	}
+	items := make([]metaItem, 0, len(list))
+	for _, r := range list {
+		items = append(items, metaItem{
+			ID:          r.ID,
+			Name:        r.Name,
+			Description: r.Description,
+		})
+	}
+	return items, nil
+}
+
+func fetchPriorities(ctx context.Context, jiraClient *jira.Client) ([]metaItem, error) {
+	list, resp, err := jiraClient.Priority.GetListWithContext(ctx)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing priorities: %w", err)
+	}
+	items := make([]metaItem, 0, len(list))
+	for _, p := range list {
+		items = append(items, metaItem{
+			ID:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+		})
+	}
+	return items, nil
+}
+
+func fetchStatuses(ctx context.Context, jiraClient *jira.Client) ([]metaItem, error) {
+	list, resp, err := jiraClient.Status.GetAllStatusesWithContext(ctx)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing statuses: %w", err)
+	}
+	items := make([]metaItem, 0, len(list))
+	for _, s := range list {
+		items = append(items, metaItem{
+			ID:          s.ID,
+			Name:        s.Name,
+			Description: s.Description,
+			Category:    s.StatusCategory.Name,
+		})
+	}
+	return items, nil
+}
+
+func fetchLinkTypes(ctx context.Context, jiraClient *jira.Client) ([]metaItem, error) {
+	// Jira wraps the list in an object, which IssueLinkType.GetList fails to
+	// decode, so the request is made directly.
+	req, err := jiraClient.NewRequestWithContext(
+		ctx, http.MethodGet, "rest/api/2/issueLinkType", nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error listing issue link types: %w", err)
+	}
+	var body struct {
+		IssueLinkTypes []jira.IssueLinkType `json:"issueLinkTypes"`
+	}
+	resp, err := jiraClient.Do(req, &body)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing issue link types: %w", err)
+	}
+	items := make([]metaItem, 0, len(body.IssueLinkTypes))
+	for _, l := range body.IssueLinkTypes {
+		items = append(items, metaItem{
+			ID:      l.ID,
+			Name:    l.Name,
+			Inward:  l.Inward,
+			Outward: l.Outward,
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metricsPrefix namespaces every exported metric.
+const metricsPrefix = "go_jira_"
+
+// pushTimeout bounds the Pushgateway request made when run returns.
+const pushTimeout = 10 * time.Second
+
+// latencyBuckets are the upper bounds, in seconds, of the API latency
+// histogram.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// apiMetrics accumulates the Jira API calls of a run for the metrics
+// summary: request counts per method and status code, and a latency
+// histogram per method. It is process-wide because every client built by
+// createHTTPClient feeds it through metricsTransport.
+type apiMetrics struct {
+	mu       sync.Mutex
+	requests map[[2]string]int // {method, code} -> count
+	buckets  map[string][]int  // method -> cumulative count per latencyBuckets
+	sum      map[string]float64
+	count    map[string]int
+}
+
+var runMetrics = newAPIMetrics()
+
+func newAPIMetrics() *apiMetrics {
+	return &apiMetrics{
+		requests: map[[2]string]int{},
+		buckets:  map[string][]int{},
+		sum:      map[string]float64{},
+		count:    map[string]int{},
+	}
+}
+
+// observe records one API call; code is "error" when no response arrived.
+func (m *apiMetrics) observe(method, code string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[[2]string{method, code}]++
+	b := m.buckets[method]
+	if b == nil {
+		b = make([]int, len(latencyBuckets))
+		m.buckets[method] = b
+	}
+	secs := d.Seconds()
+	for i, le := range latencyBuckets {
+		if secs <= le {
+			b[i]++
+		}
+	}
+	m.sum[method] += secs
+	m.count[method]++
+}
+
+// metricsTransport is a RoundTripper that counts every Jira API call and its
+// latency into runMetrics.
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	code := "error"
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	runMetrics.observe(req.Method, code, time.Since(start))
+	return resp, err
+}
+
+// writeMetrics renders the run's metrics in the Prometheus text exposition
+// format: issues per action and outcome from the report, API calls and
+// latency from m, and the run's duration and success.
+func writeMetrics(r *runReport, m *apiMetrics, duration time.Duration, success bool) []byte {
+	var b bytes.Buffer
+	header := func(name, typ, help string) {
+		fmt.Fprintf(&b, "# HELP %s%s %s\n# TYPE %s%s %s\n",
+			metricsPrefix, name, help, metricsPrefix, name, typ)
+	}
+
+	header("issues_total", "counter", "Issue actions by action and outcome.")
+	perAction := map[[2]string]int{}
+	for _, res := range r.results() {
+		for _, a := range res.Actions {
+			perAction[[2]string{a.Action, a.Outcome}]++
+		}
+	}
+	for _, k := range sortedPairs(perAction) {
+		fmt.Fprintf(&b, "%sissues_total{action=%q,outcome=%q} %d\n",
+			metricsPrefix, k[0], k[1], perAction[k])
+	}
+
+	m.mu.Lock()
+	header("api_requests_total", "counter", "Jira API requests by method and status code.")
+	for _, k := range sortedPairs(m.requests) {
+		fmt.Fprintf(&b, "%sapi_requests_total{method=%q,code=%q} %d\n",
+			metricsPrefix, k[0], k[1], m.requests[k])
+	}
+	header("api_request_duration_seconds", "histogram", "Jira API request latency by method.")
+	methods := make([]string, 0, len(m.count))
+	for method := range m.count {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		for i, le := range latencyBuckets {
+			fmt.Fprintf(&b, "%sapi_request_duration_seconds_bucket{method=%q,le=%q} %d\n",
+				metricsPrefix, method, strconv.FormatFloat(le, 'g', -1, 64), m.buckets[method][i])
+		}
+		fmt.Fprintf(&b, "%sapi_request_duration_seconds_bucket{method=%q,le=\"+Inf\"} %d\n",
+			metricsPrefix, method, m.count[method])
+		fmt.Fprintf(&b, "%sapi_request_duration_seconds_sum{method=%q} %g\n",
+			metricsPrefix, method, m.sum[method])
+		fmt.Fprintf(&b, "%sapi_request_duration_seconds_count{method=%q} %d\n",
+			metricsPrefix, method, m.count[method])
+	}
+	m.mu.Unlock()
+
+	header("run_duration_seconds", "gauge", "Duration of the last run.")
+	fmt.Fprintf(&b, "%srun_duration_seconds %g\n", metricsPrefix, duration.Seconds())
+	header("run_success", "gauge", "Whether the last run succeeded (1) or failed (0).")
+	ok := 0
+	if success {
+		ok = 1
+	}
+	fmt.Fprintf(&b, "%srun_success %d\n", metricsPrefix, ok)
+	return b.Bytes()
+}
+
+// sortedPairs returns the keys of a two-label counter in a stable order.
+func sortedPairs(m map[[2]string]int) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// writeMetricsFile writes metrics for the node_exporter textfile collector.
+// The file is written next to path and renamed over it, so the collector
+// never reads a partial file. An empty path is a no-op.
+func writeMetricsFile(path string, metrics []byte) error {
+	if path == "" {
+		return nil
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".go-jira-metrics-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(metrics); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// #nosec G302 -- the textfile collector runs as another user and must read it
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// pushMetrics replaces the metrics of job "go-jira" on a Prometheus
+// Pushgateway, grouped by instance (e.g. the repository) when set. An
+// instance holding a slash, such as owner/name, is sent base64-encoded as the
+// Pushgateway requires. An empty gateway URL is a no-op.
+func pushMetrics(ctx context.Context, gateway, instance string, metrics []byte) error {
+	if gateway == "" {
+		return nil
+	}
+	u, err := url.Parse(strings.TrimSuffix(gateway, "/"))
+	if err != nil {
+		return fmt.Errorf("invalid metrics_pushgateway: %w", err)
+	}
+	u = u.JoinPath("metrics", "job", "go-jira")
+	switch {
+	case strings.Contains(instance, "/"):
+		u = u.JoinPath("instance@base64", base64.RawURLEncoding.EncodeToString([]byte(instance)))
+	case instance != "":
+		u = u.JoinPath("instance", instance)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(metrics))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("push metrics: %w", err)
+	}
+	defer drainBody(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("push metrics: unexpected status: %s", resp.Status)
+	}
+	return nil
+}
+
+// exportMetrics renders the run's metrics and writes them to --metrics-file
+// and --metrics-pushgateway. Failures are logged, not returned, so exporting
+// never changes the run's outcome. The push gets its own deadline because the
+// run's context may already be done.
+func exportMetrics(
+	ctx context.Context,
+	config Config,
+	r *runReport,
+	duration time.Duration,
+	success bool,
+) {
+	if config.metricsFile == "" && config.metricsPushgateway == "" {
+		return
+	}
+	metrics := writeMetrics(r, runMetrics, duration, success)
+	if err := writeMetricsFile(config.metricsFile, metrics); err != nil {
+		slog.Warn("could not write metrics file", "error", err)
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pushTimeout)
+	defer cancel()
+	instance := os.Getenv(envGitHubRepository)
+	if err := pushMetrics(ctx, config.metricsPushgateway, instance, metrics); err != nil {
+		slog.Warn("could not push metrics", "error", err)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	r := newRunReport("https://jira.example.com")
+	r.record("GAIA-1", actionFetch, outcomeOK, "")
+	r.record("GAIA-1", actionTransition, outcomeOK, "")
+	r.record("GAIA-2", actionFetch, outcomeFailed, "404 Not Found")
+
+	m := newAPIMetrics()
+	m.observe(http.MethodGet, "200", 80*time.Millisecond)
+	m.observe(http.MethodGet, "404", 2*time.Second)
+	m.observe(http.MethodPost, "error", time.Millisecond)
+
+	out := string(writeMetrics(r, m, 3*time.Second, false))
+	for _, want := range []string{
+		"# TYPE go_jira_issues_total counter",
+		`go_jira_issues_total{action="fetch",outcome="failed"} 1`,
+		`go_jira_issues_total{action="fetch",outcome="ok"} 1`,
+		`go_jira_issues_total{action="transition",outcome="ok"} 1`,
+		`go_jira_api_requests_total{method="GET",code="404"} 1`,
+		`go_jira_api_requests_total{method="POST",code="error"} 1`,
+		"# TYPE go_jira_api_request_duration_seconds histogram",
+		`go_jira_api_request_duration_seconds_bucket{method="GET",le="0.1"} 1`,
+		`go_jira_api_request_duration_seconds_bucket{method="GET",le="2.5"} 2`,
+		`go_jira_api_request_duration_seconds_bucket{method="GET",le="+Inf"} 2`,
+		`go_jira_api_request_duration_seconds_count{method="POST"} 1`,
+		"go_jira_run_duration_seconds 3",
+		"go_jira_run_success 0",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsTransportCountsCalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	count := func() int {
+		runMetrics.mu.Lock()
+		defer runMetrics.mu.Unlock()
+		return runMetrics.requests[[2]string{http.MethodGet, "418"}]
+	}
+	before := count()
+	client := &http.Client{Transport: &metricsTransport{base: http.DefaultTransport}}
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainBody(resp.Body)
+	if after := count(); after != before+1 {
+		t.Errorf("GET 418 count = %d, want %d", after, before+1)
+	}
+}
+
+func TestWriteMetricsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go_jira.prom")
+	if err := writeMetricsFile(path, []byte("go_jira_run_success 1\n")); err != nil {
+		t.Fatalf("writeMetricsFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "go_jira_run_success 1\n" {
+		t.Errorf("file = %q", data)
+	}
+	if err := writeMetricsFile("", nil); err != nil {
+		t.Errorf("empty path should be a no-op, got %v", err)
+	}
+}
+
+func TestPushMetrics(t *testing.T) {
+	var gotPath, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+		gotPath = r.URL.EscapedPath()
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	metrics := []byte("go_jira_run_success 1\n")
+	if err := pushMetrics(context.Background(), srv.URL+"/", "acme/app", metrics); err != nil {
+		t.Fatalf("pushMetrics: %v", err)
+	}
+	if want := "/metrics/job/go-jira/instance@base64/YWNtZS9hcHA"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+	if gotBody != string(metrics) {
+		t.Errorf("body = %q", gotBody)
+	}
+
+	if err := pushMetrics(context.Background(), srv.URL, "runner-1", metrics); err != nil {
+		t.Fatalf("pushMetrics: %v", err)
+	}
+	if want := "/metrics/job/go-jira/instance/runner-1"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+	if err := pushMetrics(context.Background(), "", "", metrics); err != nil {
+		t.Errorf("empty gateway should be a no-op, got %v", err)
+	}
+}
@@ -25,9 +25,18 @@ const oauthRequestTimeout = 30 * time.Second
 // requireBaseURL validates the base URL for non-run commands (which, unlike
 // run, do not need a ref). It applies the same parse + scheme/--insecure rules
 // as run via the shared validateBaseURL, so every subcommand rejects invalid
-// or insecure URLs consistently.
+// or insecure URLs consistently, and checks the secret files, TLS material,
+// proxy, and headers the way validateConfig does.
 func requireBaseURL(config Config) error {
-	return validateBaseURL(config)
+	if err := validateBaseURL(config); err != nil {
+		return err
+	}
+	return errors.Join(
+		config.secretErr,
+		validateTLS(config),
+		validateProxy(config),
+		validateHeaders(config),
+	)
 }
 
 // loadOAuthConfig runs the common preamble for OAuth subcommands: load the env
@@ -154,13 +163,32 @@ func oauthConfigFromConfig(config Config) *oauth.Config {
 	}
 }
 
+// errTransport is a RoundTripper that fails every request with err.
+type errTransport struct {
+	err error
+}
+
+func (t errTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return nil, t.err
+}
+
 // oauthHTTPClient returns an HTTP client for OAuth token requests that honours
-// the --insecure flag; nil lets oauth.Config use its default.
+// the --insecure flag, the custom TLS material, and an explicit proxy; nil lets
+// oauth.Config use its default.
 func oauthHTTPClient(config Config) *http.Client {
-	if !config.insecure {
+	if !config.hasCustomTransport() {
 		return nil
 	}
-	client := createHTTPClient(config, nil)
+	client, err := createHTTPClient(config, nil)
+	if err != nil {
+		// requireBaseURL rejects a bad TLS or proxy setting up front; should
+		// one slip through, every token request fails with its error instead
+		// of going out over the default transport.
+		return &http.Client{Transport: errTransport{err: err}}
+	}
 	// createHTTPClient leaves Timeout unset (the main Jira client relies on the
 	// command context). Injecting it into oauth.Config would otherwise bypass
 	// pkg/oauth's default token-request timeout, so set the same 30s here.
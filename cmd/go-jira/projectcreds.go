@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// projectCredential is the service account used for one project's issues:
+// Basic Auth when username is set, otherwise secret is a bearer token.
+type projectCredential struct {
+	username string
+	secret   string
+}
+
+// parseProjectCredentials parses --project-credentials, a semicolon- or
+// newline-separated list of "PROJECT=username:secret" or "PROJECT=token"
+// entries, into a map keyed by uppercase project key. Errors name the entry
+// by position so a malformed value never echoes a secret.
+func parseProjectCredentials(s string) (map[string]projectCredential, error) {
+	creds := map[string]projectCredential{}
+	for i, part := range splitRules(s) {
+		project, value, ok := strings.Cut(part, "=")
+		project = strings.ToUpper(strings.TrimSpace(project))
+		value = strings.TrimSpace(value)
+		if !ok || project == "" || value == "" {
+			return nil, fmt.Errorf("invalid project credential #%d: want \"PROJECT=username:secret\" or \"PROJECT=token\"",
+				i+1)
+		}
+		var cred projectCredential
+		if user, secret, ok := strings.Cut(value, ":"); ok {
+			cred = projectCredential{username: strings.TrimSpace(user), secret: strings.TrimSpace(secret)}
+			if cred.username == "" || cred.secret == "" {
+				return nil, fmt.Errorf("invalid project credential for %q: username and secret are both required", project)
+			}
+		} else {
+			cred = projectCredential{secret: value}
+		}
+		creds[project] = cred
+	}
+	return creds, nil
+}
+
+// projectCredentialSecrets returns the secrets in --project-credentials so
+// they can be masked in logs; a malformed value yields none.
+func projectCredentialSecrets(s string) []string {
+	creds, _ := parseProjectCredentials(s)
+	secrets := make([]string, 0, len(creds))
+	for _, c := range creds {
+		secrets = append(secrets, c.secret)
+	}
+	return secrets
+}
+
+// newProjectClients builds one Jira client per --project-credentials entry.
+// Each shares config's base URL, TLS, proxy, and headers but authenticates
+// only with its own credential, never falling back to OAuth or Kerberos.
+func newProjectClients(ctx context.Context, config Config) (map[string]*jira.Client, error) {
+	creds, err := parseProjectCredentials(config.projectCredentials)
+	if err != nil {
+		return nil, err
+	}
+	clients := make(map[string]*jira.Client, len(creds))
+	for project, c := range creds {
+		cfg := config
+		cfg.username, cfg.password, cfg.token = c.username, "", ""
+		if c.username != "" {
+			cfg.password = c.secret
+		} else {
+			cfg.token = c.secret
+		}
+		cfg.oauthRefreshToken, cfg.oauthClientID = "", ""
+		cfg.krb5Keytab, cfg.krb5CCache = "", ""
+		client, err := resolveJiraClient(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("project %s credentials: %w", project, err)
+		}
+		clients[project] = client
+		slog.Info("using project credentials", "project", project)
+	}
+	return clients, nil
+}
+
+type projectClientsCtxKey struct{}
+
+// withProjectClients attaches the per-project clients to ctx so every
+// per-issue phase picks its client by issue key, mirroring withReport.
+func withProjectClients(ctx context.Context, clients map[string]*jira.Client) context.Context {
+	return context.WithValue(ctx, projectClientsCtxKey{}, clients)
+}
+
+// clientFor returns the client for the project of key when
+// --project-credentials configures one, or fallback otherwise.
+func clientFor(ctx context.Context, fallback *jira.Client, key string) *jira.Client {
+	clients, _ := ctx.Value(projectClientsCtxKey{}).(map[string]*jira.Client)
+	if c, ok := clients[strings.ToUpper(issueProject(key))]; ok {
+		return c
+	}
+	return fallback
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestParseProjectCredentials(t *testing.T) {
+	got, err := parseProjectCredentials("gaia=svc-gaia:s3cret-token;\nOPS = ops-pat-token")
+	if err != nil {
+		t.Fatalf("parseProjectCredentials: %v", err)
+	}
+	want := map[string]projectCredential{
+		"GAIA": {username: "svc-gaia", secret: "s3cret-token"},
+		"OPS":  {secret: "ops-pat-token"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d credentials, want %d", len(got), len(want))
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %+v, want %+v", k, got[k], v)
+		}
+	}
+
+	for _, bad := range []string{"GAIA", "=token", "GAIA=", "GAIA=:secret-value", "GAIA=user:"} {
+		_, err := parseProjectCredentials(bad)
+		if err == nil {
+			t.Errorf("parseProjectCredentials(%q) should fail", bad)
+			continue
+		}
+		if strings.Contains(err.Error(), "secret-value") {
+			t.Errorf("error leaks the secret: %v", err)
+		}
+	}
+}
+
+func TestProcessIssues_ProjectCredentials(t *testing.T) {
+	var fallbackAuth, opsAuth string
+	handler := func(seen *string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*seen = r.Header.Get("Authorization")
+			key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+			_ = json.NewEncoder(w).Encode(jira.Issue{Key: key, Fields: &jira.IssueFields{}})
+		}
+	}
+	fallbackSrv := httptest.NewServer(handler(&fallbackAuth))
+	defer fallbackSrv.Close()
+	opsSrv := httptest.NewServer(handler(&opsAuth))
+	defer opsSrv.Close()
+
+	fallback, err := jira.NewClient(nil, fallbackSrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clients, err := newProjectClients(context.Background(), Config{
+		baseURL:            opsSrv.URL,
+		projectCredentials: "OPS=ops-pat-token",
+	})
+	if err != nil {
+		t.Fatalf("newProjectClients: %v", err)
+	}
+	ctx := withProjectClients(context.Background(), clients)
+
+	issues, err := processIssues(ctx, fallback, Config{ref: "GAIA-1 OPS-2"})
+	if err != nil {
+		t.Fatalf("processIssues: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	if fallbackAuth != "" {
+		t.Errorf("GAIA-1 fetched with Authorization %q, want the unauthenticated fallback", fallbackAuth)
+	}
+	if opsAuth != "Bearer ops-pat-token" {
+		t.Errorf("OPS-2 fetched with Authorization %q, want the OPS token", opsAuth)
+	}
+}
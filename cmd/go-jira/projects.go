@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// issueProject returns the project key of an issue key, e.g. "GAIA" for
+// "GAIA-12", or "" when key has no project prefix.
+func issueProject(key string) string {
+	i := strings.LastIndex(key, "-")
+	if i < 0 {
+		return ""
+	}
+	return key[:i]
+}
+
+// projectSet parses a comma-separated project key list into an uppercase set.
+func projectSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, p := range splitCSV(s) {
+		set[strings.ToUpper(p)] = true
+	}
+	return set
+}
+
+// filterProjects keeps the keys whose project is listed in --projects (when
+// set) and not listed in --exclude-projects, so a pipeline in a monorepo only
+// touches its own Jira projects. Project keys compare case-insensitively.
+func filterProjects(keys []string, config Config) []string {
+	allow, deny := projectSet(config.projects), projectSet(config.excludeProjects)
+	if len(allow) == 0 && len(deny) == 0 {
+		return keys
+	}
+	kept := keys[:0:0]
+	for _, k := range keys {
+		project := strings.ToUpper(issueProject(k))
+		if (len(allow) > 0 && !allow[project]) || deny[project] {
+			slog.Info("skipping issue from an excluded project", logKeyIssue, k, "project", project)
+			continue
+		}
+		kept = append(kept, k)
+	}
+	return kept
+}
+
+// validateProjects drops the keys whose project does not exist on the server
+// (or is not visible to the account), fetching the project list once instead
+// of letting every such key fail its own GET with a 404. Dropped keys are
+// recorded as skipped. When the list cannot be fetched the keys are returned
+// unchanged, so validation never blocks a run.
+func validateProjects(ctx context.Context, jiraClient *jira.Client, keys []string) []string {
+	list, resp, err := jiraClient.Project.GetListWithContext(ctx)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil || list == nil {
+		slog.Warn("could not fetch the project list; skipping project validation", "error", err)
+		return keys
+	}
+	known := make(map[string]bool, len(*list))
+	for _, p := range *list {
+		known[strings.ToUpper(p.Key)] = true
+	}
+	report := reportFrom(ctx)
+	kept := keys[:0:0]
+	for _, k := range keys {
+		project := issueProject(k)
+		if !known[strings.ToUpper(project)] {
+			issueLogger(k, actionFetch).Warn("skipping issue from an unknown project", "project", project)
+			report.record(k, actionFetch, outcomeSkipped, fmt.Sprintf("project %q not found", project))
+			continue
+		}
+		kept = append(kept, k)
+	}
+	return kept
+}
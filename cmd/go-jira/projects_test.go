@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestFilterProjects(t *testing.T) {
+	keys := []string{"GAIA-1", "OPS-2", "WEB-3", "GAIA-4"}
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{"no filter", Config{}, "GAIA-1,OPS-2,WEB-3,GAIA-4"},
+		{"allowlist", Config{projects: "gaia, ops"}, "GAIA-1,OPS-2,GAIA-4"},
+		{"denylist", Config{excludeProjects: "OPS"}, "GAIA-1,WEB-3,GAIA-4"},
+		{"deny wins over allow", Config{projects: "GAIA,OPS", excludeProjects: "OPS"}, "GAIA-1,GAIA-4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterProjects(keys, tt.config)
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("filterProjects = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueProject(t *testing.T) {
+	for key, want := range map[string]string{"GAIA-12": "GAIA", "MY-PROJ-3": "MY-PROJ", "#42": ""} {
+		if got := issueProject(key); got != want {
+			t.Errorf("issueProject(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestProcessIssuesValidateProjects(t *testing.T) {
+	var mu sync.Mutex
+	var fetched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/project" {
+			_, _ = w.Write([]byte(`[{"key":"GAIA"},{"key":"OPS"}]`))
+			return
+		}
+		if r.URL.Path == "/rest/api/2/search" {
+			keys := searchedKeys(r)
+			mu.Lock()
+			fetched = append(fetched, keys...)
+			mu.Unlock()
+			writeSearchResult(t, w, keys)
+			return
+		}
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		mu.Lock()
+		fetched = append(fetched, key)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(jira.Issue{Key: key, Fields: &jira.IssueFields{}})
+	}))
+	defer server.Close()
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newRunReport("")
+	ctx := withReport(context.Background(), r)
+	issues, err := processIssues(ctx, jiraClient, Config{
+		ref:              "GAIA-1 fixes the UNKNOWN-2 case; see ops-3",
+		validateProjects: true,
+	})
+	if err != nil {
+		t.Fatalf("processIssues: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Key != "GAIA-1" {
+		t.Errorf("issues = %+v", issues)
+	}
+	if strings.Join(fetched, ",") != "GAIA-1" {
+		t.Errorf("fetched %v; unknown projects should not be requested", fetched)
+	}
+	res := r.results()
+	if len(res) != 2 || res[1].Key != "UNKNOWN-2" || res[1].Actions[0].Outcome != outcomeSkipped {
+		t.Errorf("results = %+v", res)
+	}
+}
+
+func TestValidateProjectsListError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{"GAIA-1", "OPS-2"}
+	if got := validateProjects(context.Background(), jiraClient, keys); len(got) != 2 {
+		t.Errorf("keys should pass through when the list fails, got %v", got)
+	}
+}
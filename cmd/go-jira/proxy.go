@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// proxySchemes are the proxy URL schemes http.Transport can dial through.
+var proxySchemes = map[string]bool{
+	schemeHTTP:  true,
+	schemeHTTPS: true,
+	"socks5":    true,
+	"socks5h":   true,
+}
+
+// validateProxy checks that an explicit --proxy parses as a supported URL.
+func validateProxy(config Config) error {
+	_, err := proxyFunc(config)
+	return err
+}
+
+// proxyFunc returns the Transport.Proxy func for the configured proxy. An
+// explicit --proxy (INPUT_PROXY) routes every request through that URL;
+// otherwise HTTP_PROXY / HTTPS_PROXY / NO_PROXY from the environment apply,
+// exactly as with http.DefaultTransport.
+func proxyFunc(config Config) (func(*http.Request) (*url.URL, error), error) {
+	if config.proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	u, err := url.Parse(config.proxy)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("proxy must be a valid URL, e.g. http://proxy:3128: %q", config.proxy)
+	}
+	if !proxySchemes[u.Scheme] {
+		return nil, fmt.Errorf("proxy scheme %q not supported; use http, https, socks5, or socks5h", u.Scheme)
+	}
+	return http.ProxyURL(u), nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProxyFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxy   string
+		want    string
+		wantErr string
+	}{
+		{name: "http proxy", proxy: "http://proxy.corp:3128", want: "http://proxy.corp:3128"},
+		{name: "socks5 proxy", proxy: "socks5://127.0.0.1:1080", want: "socks5://127.0.0.1:1080"},
+		{name: "missing host", proxy: "proxy.corp:3128", wantErr: "valid URL"},
+		{name: "unsupported scheme", proxy: "ftp://proxy.corp", wantErr: "not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, err := proxyFunc(Config{proxy: tt.proxy})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			req, _ := http.NewRequestWithContext(
+				context.Background(), http.MethodGet, "https://jira.example.com", nil,
+			)
+			u, err := fn(req)
+			if err != nil || u == nil || u.String() != tt.want {
+				t.Errorf("proxy for request = %v (err %v), want %s", u, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCreateHTTPClientUsesProxy checks that requests are sent to the explicit
+// proxy, which sees the absolute Jira URL in the request line.
+func TestCreateHTTPClientUsesProxy(t *testing.T) {
+	var gotURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	client := mustHTTPClient(t, Config{baseURL: "http://jira.internal", proxy: proxy.URL}, nil)
+	req, err := http.NewRequestWithContext(
+		context.Background(), http.MethodGet, "http://jira.internal/rest/api/2/myself", nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request through proxy failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if gotURL != "http://jira.internal/rest/api/2/myself" {
+		t.Errorf("proxy saw URL %q, want the absolute Jira URL", gotURL)
+	}
+}
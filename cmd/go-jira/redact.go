@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/appleboy/go-jira/pkg/util"
+)
+
+// registerConfigSecrets records every credential carried by config: tokens,
+// passwords, an inline mTLS key, custom header values, and proxy credentials.
+// A tls_key holding a file path is left readable.
+func registerConfigSecrets(config Config) {
+	values := []string{
+		config.token,
+		config.password,
+		config.oauthRefreshToken,
+		config.brokerToken,
+	}
+	if strings.Contains(config.tlsKey, pemBlockPrefix) {
+		values = append(values, config.tlsKey)
+	}
+	values = append(values, projectCredentialSecrets(config.projectCredentials)...)
+	if h, err := parseHeaders(config.headers); err == nil {
+		for _, vs := range h {
+			values = append(values, vs...)
+		}
+	}
+	if u, err := url.Parse(config.proxy); err == nil && u.User != nil {
+		if p, ok := u.User.Password(); ok {
+			values = append(values, p)
+		}
+	}
+	util.Mask(values...)
+}
+
+// redactAny returns v with every string inside it masked, for values headed
+// to the debug dump. Maps and slices are copied rather than modified.
+func redactAny(v any) any {
+	switch t := v.(type) {
+	case string:
+		return util.Redact(t)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			out[k] = redactAny(val)
+		}
+		return out
+	case []string:
+		out := make([]string, len(t))
+		for i, s := range t {
+			out[i] = util.Redact(s)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import "strings"
+
+// splitRefs splits the --refs input on newlines and commas into trimmed,
+// non-empty refs, e.g. a PR title and a merge commit subject passed as one
+// multi-line CI input.
+func splitRefs(s string) []string {
+	var refs []string
+	for _, ref := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n'
+	}) {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// refTexts returns each text run scans for issue keys: the combined ref
+// followed by every entry of --refs. Each is scanned on its own, so an
+// unterminated code fence or commit header in one cannot swallow keys in
+// another.
+func refTexts(config Config) []string {
+	var texts []string
+	if config.ref != "" {
+		texts = append(texts, config.ref)
+	}
+	return append(texts, splitRefs(config.refs)...)
+}
+
+// scanRefs scans every ref text independently and returns the union of their
+// issue keys, deduplicated across refs in first-seen order.
+func scanRefs(config Config) ([]string, error) {
+	var keys []string
+	seen := map[string]bool{}
+	for _, text := range refTexts(config) {
+		found, err := scanKeys(text, config)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range found {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys, nil
+}
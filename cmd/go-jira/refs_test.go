@@ -0,0 +1,51 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitRefs(t *testing.T) {
+	got := splitRefs("feat: GAIA-1 parser\n\n  GAIA-2 merge , GAIA-3\n")
+	want := []string{"feat: GAIA-1 parser", "GAIA-2 merge", "GAIA-3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("splitRefs = %q, want %q", got, want)
+	}
+}
+
+func TestScanRefs(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   []string
+	}{
+		{
+			name:   "refs only",
+			config: Config{refs: "GAIA-1 title\nMerge GAIA-2, GAIA-1"},
+			want:   []string{"GAIA-1", "GAIA-2"},
+		},
+		{
+			name:   "ref and refs deduplicated",
+			config: Config{ref: "GAIA-3 GAIA-2", refs: "GAIA-2\nGAIA-4"},
+			want:   []string{"GAIA-3", "GAIA-2", "GAIA-4"},
+		},
+		{
+			// An unterminated code fence in the ref must not hide the keys
+			// of the next ref.
+			name:   "scanned independently",
+			config: Config{ref: "```\nGAIA-5", refs: "GAIA-6"},
+			want:   []string{"GAIA-6"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scanRefs(tt.config)
+			if err != nil {
+				t.Fatalf("scanRefs: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
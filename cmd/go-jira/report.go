@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appleboy/go-jira/pkg/jiraops"
+	"github.com/appleboy/go-jira/pkg/util"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// Per-issue actions recorded in the run report, named as jiraops reports them.
+const (
+	actionFetch      = jiraops.ActionFetch
+	actionTransition = jiraops.ActionTransition
+	actionAssign     = jiraops.ActionAssign
+	actionComment    = jiraops.ActionComment
+)
+
+// Outcomes of a recorded action.
+const (
+	outcomeOK      = jiraops.StatusOK
+	outcomeSkipped = jiraops.StatusSkipped
+	outcomeFailed  = jiraops.StatusFailed
+)
+
+// actionResult is the outcome of one action against one issue.
+type actionResult struct {
+	Action  string `json:"action"`
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// issueResult collects everything the run did to a single issue.
+type issueResult struct {
+	Key       string         `json:"key"`
+	Summary   string         `json:"summary,omitempty"`
+	OldStatus string         `json:"old_status,omitempty"`
+	NewStatus string         `json:"new_status,omitempty"`
+	URL       string         `json:"url,omitempty"`
+	CommentID string         `json:"comment_id,omitempty"`
+	Actions   []actionResult `json:"actions"`
+}
+
+// runReport is the structured record of a run: the keys extracted from the ref
+// and, per issue, the outcome of every action attempted. The per-issue workers
+// record into it concurrently, so every method locks. All methods are no-ops
+// on a nil receiver so code paths without a report (tests, data commands) need
+// no guards.
+type runReport struct {
+	mu      sync.Mutex
+	baseURL string
+	keys    []string
+	issues  map[string]*issueResult
+}
+
+func newRunReport(baseURL string) *runReport {
+	return &runReport{baseURL: baseURL, issues: map[string]*issueResult{}}
+}
+
+type reportCtxKey struct{}
+
+// withReport attaches a runReport to ctx so the per-issue workers can record
+// outcomes without threading the report through every signature, mirroring
+// withDiag.
+func withReport(ctx context.Context, r *runReport) context.Context {
+	return context.WithValue(ctx, reportCtxKey{}, r)
+}
+
+func reportFrom(ctx context.Context) *runReport {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(reportCtxKey{}).(*runReport)
+	return r
+}
+
+// setKeys records the issue keys extracted from the ref.
+func (r *runReport) setKeys(keys []string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append([]string{}, keys...)
+}
+
+// entry returns the result for key, creating it on first use. Callers hold mu.
+func (r *runReport) entry(key string) *issueResult {
+	res, ok := r.issues[key]
+	if !ok {
+		res = &issueResult{Key: key, URL: issueBrowseURL(r.baseURL, key), Actions: []actionResult{}}
+		r.issues[key] = res
+	}
+	return res
+}
+
+// addIssue records a fetched issue's summary and current status.
+func (r *runReport) addIssue(iss *jira.Issue) {
+	if r == nil || iss == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := r.entry(iss.Key)
+	res.Summary = issueSummary(iss)
+	res.OldStatus = issueStatusName(iss)
+}
+
+// record appends the outcome of action on key.
+func (r *runReport) record(key, action, outcome, reason string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := r.entry(key)
+	res.Actions = append(res.Actions, actionResult{Action: action, Outcome: outcome, Reason: reason})
+}
+
+// setNewStatus records the status an issue was transitioned to.
+func (r *runReport) setNewStatus(key, status string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entry(key).NewStatus = status
+}
+
+// setCommentID records the ID of the comment added to an issue.
+func (r *runReport) setCommentID(key, id string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entry(key).CommentID = id
+}
+
+// results returns a snapshot of the per-issue results, ordered by the key's
+// position in the ref and then by key.
+func (r *runReport) results() []issueResult {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pos := make(map[string]int, len(r.keys))
+	for i, k := range r.keys {
+		pos[k] = i
+	}
+	out := make([]issueResult, 0, len(r.issues))
+	for _, res := range r.issues {
+		c := *res
+		c.Actions = append([]actionResult{}, res.Actions...)
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		pi, iok := pos[out[i].Key]
+		pj, jok := pos[out[j].Key]
+		if iok && jok && pi != pj {
+			return pi < pj
+		}
+		if iok != jok {
+			return iok
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// failed reports whether any action on the issue failed.
+func (res issueResult) failed() bool {
+	for _, a := range res.Actions {
+		if a.Outcome == outcomeFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// failedCount returns the number of issues with at least one failed action.
+func (r *runReport) failedCount() int {
+	n := 0
+	for _, res := range r.results() {
+		if res.failed() {
+			n++
+		}
+	}
+	return n
+}
+
+// issueBrowseURL returns the browser link for key, or "" without a base URL.
+func issueBrowseURL(baseURL, key string) string {
+	if baseURL == "" {
+		return ""
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.JoinPath("browse", key).String()
+}
+
+// writeSummary renders the compact end-of-run banner: counts per action, the
+// failed and skipped issues with one-line reasons, links to every issue, and
+// next steps derived from what went wrong. It is built only from the report,
+// so it reads the same in a terminal and in a CI log.
+func (r *runReport) writeSummary(w io.Writer) {
+	if r == nil {
+		return
+	}
+	results := r.results()
+	r.mu.Lock()
+	found := len(r.keys)
+	r.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("──── go-jira run summary ────\n")
+	fmt.Fprintf(&b, "issues: %d referenced, %d processed\n", found, len(results))
+
+	type counts struct{ ok, skipped, failed int }
+	perAction := map[string]*counts{}
+	var problems []string
+	hints := map[string]bool{}
+	for _, res := range results {
+		for _, a := range res.Actions {
+			c := perAction[a.Action]
+			if c == nil {
+				c = &counts{}
+				perAction[a.Action] = c
+			}
+			switch a.Outcome {
+			case outcomeOK:
+				c.ok++
+			case outcomeSkipped:
+				c.skipped++
+			case outcomeFailed:
+				c.failed++
+			}
+			if a.Outcome == outcomeOK {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("  %-12s %-10s %-7s %s",
+				res.Key, a.Action, a.Outcome, firstLine(a.Reason)))
+			hints[a.Action+"/"+a.Outcome] = true
+		}
+	}
+	for _, action := range []string{actionFetch, actionTransition, actionAssign, actionComment} {
+		if c := perAction[action]; c != nil {
+			fmt.Fprintf(&b, "  %-10s ok=%d skipped=%d failed=%d\n", action, c.ok, c.skipped, c.failed)
+		}
+	}
+	if len(problems) > 0 {
+		b.WriteString("needs attention:\n")
+		for _, p := range problems {
+			b.WriteString(strings.TrimRight(p, " "))
+			b.WriteByte('\n')
+		}
+	}
+	if len(results) > 0 && r.baseURL != "" {
+		b.WriteString("links:\n")
+		for _, res := range results {
+			fmt.Fprintf(&b, "  %-12s %s\n", res.Key, res.URL)
+		}
+	}
+	if steps := nextSteps(found, hints); len(steps) > 0 {
+		b.WriteString("next steps:\n")
+		for _, s := range steps {
+			fmt.Fprintf(&b, "  - %s\n", s)
+		}
+	}
+	_, _ = io.WriteString(w, b.String())
+}
+
+// writeMarkdown renders the report as a Markdown table (key, summary, previous
+// and new status, outcome) for the GitHub Actions job summary. Keys link to
+// the issue when the base URL is known.
+func (r *runReport) writeMarkdown(w io.Writer) {
+	if r == nil {
+		return
+	}
+	results := r.results()
+	var b strings.Builder
+	b.WriteString("### Jira issues\n\n")
+	if len(results) == 0 {
+		b.WriteString("No issue keys were found in the ref.\n")
+		_, _ = io.WriteString(w, b.String())
+		return
+	}
+	b.WriteString("| Issue | Summary | Previous status | New status | Result |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, res := range results {
+		key := res.Key
+		if res.URL != "" {
+			key = fmt.Sprintf("[%s](%s)", res.Key, res.URL)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			key,
+			markdownCell(res.Summary),
+			markdownCell(res.OldStatus),
+			markdownCell(res.NewStatus),
+			markdownCell(resultCell(res.Actions)))
+	}
+	_, _ = io.WriteString(w, b.String())
+}
+
+// resultCell condenses an issue's actions to one cell, e.g.
+// "fetch ok, transition skipped: transition "Done" not found".
+func resultCell(actions []actionResult) string {
+	parts := make([]string, 0, len(actions))
+	for _, a := range actions {
+		p := a.Action + " " + a.Outcome
+		if a.Reason != "" {
+			p += ": " + firstLine(a.Reason)
+		}
+		parts = append(parts, p)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// markdownCell escapes a value for a Markdown table cell: pipes would end the
+// cell and newlines the row.
+func markdownCell(s string) string {
+	if s == "" {
+		return "—"
+	}
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// reportDocument is the JSON written to --report-file: the keys extracted
+// from the ref, every issue with the outcome of each action attempted, and the
+// error that ended the run, if any.
+type reportDocument struct {
+	BaseURL string        `json:"base_url,omitempty"`
+	Keys    []string      `json:"keys"`
+	Issues  []issueResult `json:"issues"`
+	Failed  int           `json:"failed"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// writeReportFile writes the report as indented JSON to path for dashboards
+// and audit tooling, replacing any previous file. runErr is the error run
+// returns; secrets are masked in every message. An empty path is a no-op.
+func writeReportFile(path string, r *runReport, runErr error) error {
+	if path == "" || r == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(r.document(runErr), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// document builds the reportDocument of r with secrets masked in every
+// message; runErr is the error run returns.
+func (r *runReport) document(runErr error) reportDocument {
+	results := r.results()
+	for i := range results {
+		for j := range results[i].Actions {
+			results[i].Actions[j].Reason = util.Redact(results[i].Actions[j].Reason)
+		}
+	}
+	r.mu.Lock()
+	doc := reportDocument{
+		BaseURL: r.baseURL,
+		Keys:    append([]string{}, r.keys...),
+		Issues:  results,
+	}
+	r.mu.Unlock()
+	for _, res := range results {
+		if res.failed() {
+			doc.Failed++
+		}
+	}
+	if runErr != nil {
+		doc.Error = util.Redact(runErr.Error())
+	}
+	return doc
+}
+
+// printRunSummary writes the summary banner to stderr. It is the one
+// informational output kept under --quiet, so a quiet run still ends with its
+// outcome. With JSON logs the per-issue results are written as one record
+// instead, keeping stderr machine-readable; the record goes straight to the
+// handler so the level threshold does not drop it.
+func printRunSummary(ctx context.Context, r *runReport) {
+	if r == nil {
+		return
+	}
+	if jsonLogs {
+		rec := slog.NewRecord(time.Now(), slog.LevelInfo, "run summary", 0)
+		rec.AddAttrs(slog.Any("issues", r.results()))
+		_ = slog.Default().Handler().Handle(ctx, rec)
+		return
+	}
+	r.writeSummary(os.Stderr)
+}
+
+// nextSteps maps the problems seen in a run to actionable suggestions.
+func nextSteps(found int, hints map[string]bool) []string {
+	var steps []string
+	if found == 0 {
+		steps = append(steps, "No issue keys matched the ref; check --ref and --issue-format.")
+	}
+	if hints[actionFetch+"/"+outcomeFailed] {
+		steps = append(steps,
+			"Some issues could not be fetched; verify the keys exist and the account can browse them.")
+	}
+	if hints[actionTransition+"/"+outcomeSkipped] {
+		steps = append(steps,
+			"A transition was unavailable for some issues; compare --to-transition with the names listed above.")
+	}
+	if hints[actionTransition+"/"+outcomeFailed] || hints[actionAssign+"/"+outcomeFailed] ||
+		hints[actionComment+"/"+outcomeFailed] {
+		steps = append(steps,
+			"Some updates failed; check the account's project permissions and required transition fields, then re-run.")
+	}
+	return steps
+}
+
+// firstLine trims s to its first line so a multi-line error stays one row.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
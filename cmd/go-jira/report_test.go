@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/appleboy/go-jira/pkg/jiraops"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestRunReportWriteSummary(t *testing.T) {
+	r := newRunReport("https://jira.example.com/")
+	r.setKeys([]string{"GAIA-2", "GAIA-1", "GAIA-3"})
+	r.addIssue(&jira.Issue{Key: "GAIA-1", Fields: &jira.IssueFields{
+		Summary: "First", Status: &jira.Status{Name: "To Do"},
+	}})
+	r.record("GAIA-1", actionFetch, outcomeOK, "")
+	r.record("GAIA-1", actionTransition, outcomeOK, "")
+	r.setNewStatus("GAIA-1", "Done")
+	r.record("GAIA-2", actionFetch, outcomeOK, "")
+	r.record("GAIA-2", actionTransition, outcomeSkipped,
+		jiraops.TransitionNotFoundError("Done", []jira.Transition{{Name: "In Progress"}}).Error())
+	r.record("GAIA-3", actionFetch, outcomeFailed, "issue does not exist\nmore detail")
+
+	var b strings.Builder
+	r.writeSummary(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		"issues: 3 referenced, 3 processed",
+		"fetch      ok=2 skipped=0 failed=1",
+		"transition ok=1 skipped=1 failed=0",
+		`transition "Done" not found; available: In Progress`,
+		"GAIA-3       fetch      failed  issue does not exist\n",
+		"https://jira.example.com/browse/GAIA-1",
+		"verify the keys exist",
+		"compare --to-transition",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "more detail") {
+		t.Errorf("summary should keep reasons to one line:\n%s", out)
+	}
+	// Issues are listed in ref order, not alphabetically.
+	if strings.Index(out, "browse/GAIA-2") > strings.Index(out, "browse/GAIA-1") {
+		t.Errorf("links not in ref order:\n%s", out)
+	}
+
+	res := r.results()
+	if res[1].Key != "GAIA-1" || res[1].OldStatus != "To Do" || res[1].NewStatus != "Done" {
+		t.Errorf("GAIA-1 result = %+v", res[1])
+	}
+}
+
+func TestRunReportNoKeys(t *testing.T) {
+	r := newRunReport("")
+	r.setKeys(nil)
+	var b strings.Builder
+	r.writeSummary(&b)
+	if !strings.Contains(b.String(), "No issue keys matched the ref") {
+		t.Errorf("expected no-keys next step:\n%s", b.String())
+	}
+	if strings.Contains(b.String(), "links:") {
+		t.Errorf("no links expected without a base URL:\n%s", b.String())
+	}
+}
+
+// TestRunReportNilSafe checks code paths without a report in their context
+// (data commands, tests) can call the recorders freely.
+func TestRunReportNilSafe(t *testing.T) {
+	r := reportFrom(context.Background())
+	if r != nil {
+		t.Fatal("expected nil report")
+	}
+	r.setKeys([]string{"GAIA-1"})
+	r.record("GAIA-1", actionFetch, outcomeOK, "")
+	r.addIssue(&jira.Issue{Key: "GAIA-1"})
+	r.setNewStatus("GAIA-1", "Done")
+	r.writeSummary(&strings.Builder{})
+}
+
+func TestProcessTransitionsRecordsReport(t *testing.T) {
+	r := newRunReport("")
+	ctx := withReport(context.Background(), r)
+	issues := []*jira.Issue{{
+		Key:         "GAIA-1",
+		Transitions: []jira.Transition{{ID: "1", Name: "Start"}},
+	}}
+	ops := newProcessor(ctx, nil, Config{})
+	if err := processTransitions(ctx, ops, "Done", "", issues); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res := r.results()
+	if len(res) != 1 || len(res[0].Actions) != 1 {
+		t.Fatalf("results = %+v", res)
+	}
+	a := res[0].Actions[0]
+	if a.Outcome != outcomeSkipped || !strings.Contains(a.Reason, "available: Start") {
+		t.Errorf("action = %+v", a)
+	}
+}
+
+func TestWriteReportFile(t *testing.T) {
+	r := newRunReport("https://jira.example.com")
+	r.setKeys([]string{"GAIA-1", "GAIA-2"})
+	r.addIssue(&jira.Issue{Key: "GAIA-1", Fields: &jira.IssueFields{
+		Summary: "First", Status: &jira.Status{Name: "To Do"},
+	}})
+	r.record("GAIA-1", actionFetch, outcomeOK, "")
+	r.record("GAIA-1", actionTransition, outcomeOK, "")
+	r.setNewStatus("GAIA-1", "Done")
+	r.record("GAIA-1", actionComment, outcomeOK, "")
+	r.setCommentID("GAIA-1", "10042")
+	r.record("GAIA-2", actionFetch, outcomeFailed, "404 Not Found")
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := writeReportFile(path, r, errors.New("1 failed issues exceed the fail_mode threshold")); err != nil {
+		t.Fatalf("writeReportFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc reportDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, data)
+	}
+	if doc.Failed != 1 || !strings.Contains(doc.Error, "threshold") || len(doc.Issues) != 2 {
+		t.Fatalf("report = %+v", doc)
+	}
+	first := doc.Issues[0]
+	if first.Key != "GAIA-1" || first.OldStatus != "To Do" || first.NewStatus != "Done" ||
+		first.CommentID != "10042" || len(first.Actions) != 3 {
+		t.Errorf("GAIA-1 = %+v", first)
+	}
+	if second := doc.Issues[1]; second.Actions[0].Outcome != outcomeFailed ||
+		second.Actions[0].Reason != "404 Not Found" {
+		t.Errorf("GAIA-2 = %+v", second)
+	}
+
+	if err := writeReportFile("", r, nil); err != nil {
+		t.Errorf("empty path should be a no-op, got %v", err)
+	}
+}
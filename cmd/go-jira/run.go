@@ -3,9 +3,12 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/appleboy/go-jira/pkg/auth"
+	"github.com/appleboy/go-jira/pkg/issuekey"
 	"github.com/appleboy/go-jira/pkg/markdown"
 
 	jira "github.com/andygrunwald/go-jira"
@@ -25,6 +28,9 @@ func newRunCmd() *cobra.Command {
 		Example: `  # Move every issue mentioned in the latest commit to Done
   git log -1 --format=%B | go-jira run --ref - --to-transition Done
 
+  # Move every issue referenced since the last release to Done
+  git log v1.0..HEAD | go-jira run --stdin --to-transition Done
+
   # Add a Markdown comment to issues referenced in a string
   go-jira run --ref "Fixes GAIA-12" --comment "**Deployed** to staging" --markdown
 
@@ -44,12 +50,77 @@ func newRunCmd() *cobra.Command {
 		String(flagPassword, "", "Jira password — INSECURE on shared hosts, prefer env: PASSWORD / INPUT_PASSWORD")
 	cmd.Flags().
 		String(flagToken, "", "Jira API token — INSECURE on shared hosts, prefer env: TOKEN / INPUT_TOKEN")
+	cmd.Flags().Bool(flagSessionAuth, false,
+		"Log in with username/password through the legacy session cookie endpoint instead of "+
+			"Basic Auth (env: SESSION_AUTH / INPUT_SESSION_AUTH)")
+	addKerberosFlags(cmd)
 	cmd.Flags().
 		String(flagRef, "", `Commit message or text containing issue keys; pass "-" to read from stdin (env: REF / INPUT_REF)`)
+	cmd.Flags().Bool(flagStdin, false,
+		`Scan stdin for issue keys as well, e.g. piped git log output (same as --ref - when --ref is unset)`)
+	cmd.Flags().String(flagRefs, "",
+		"Newline- or comma-separated refs, e.g. a PR title and a merge commit message, each scanned "+
+			"on its own with the keys deduplicated across them (env: REFS / INPUT_REFS)")
+	cmd.Flags().String(flagRefFile, "",
+		"File whose contents are scanned for issue keys too, e.g. a generated git log "+
+			"(env: REF_FILE / INPUT_REF_FILE)")
+	cmd.Flags().String(flagCommitRange, "",
+		"Git revision range, e.g. v1.2.0..HEAD, whose commit messages are scanned for issue keys "+
+			"(env: COMMIT_RANGE / INPUT_COMMIT_RANGE)")
+	cmd.Flags().Int(flagPRNumber, 0,
+		"GitHub pull request whose title, body, and commit messages are fetched through the API "+
+			"and scanned for issue keys; uses GITHUB_TOKEN and GITHUB_REPOSITORY (env: PR_NUMBER / INPUT_PR_NUMBER)")
 	cmd.Flags().
-		String(flagIssueFormat, "", "Regex used to extract issue keys (env: ISSUE_FORMAT / INPUT_ISSUE_FORMAT)")
+		String(flagIssueFormat, "", "Regex used to extract issue keys; a (?P<key>...) group extracts only "+
+			"the key (env: ISSUE_FORMAT / INPUT_ISSUE_FORMAT)")
+	cmd.Flags().Bool(flagTrailersOnly, false,
+		`Only read issue keys from Git trailers such as "Jira: ABC-123", ignoring keys in prose `+
+			"(env: TRAILERS_ONLY / INPUT_TRAILERS_ONLY)")
+	cmd.Flags().String(flagProjects, "",
+		`Only act on issues from these comma-separated project keys, e.g. "GAIA,OPS" `+
+			"(env: PROJECTS / INPUT_PROJECTS)")
+	cmd.Flags().String(flagExcludeProjs, "",
+		"Never act on issues from these comma-separated project keys "+
+			"(env: EXCLUDE_PROJECTS / INPUT_EXCLUDE_PROJECTS)")
+	cmd.Flags().Bool(flagValidateProj, false,
+		"Fetch the project list once and skip keys whose project does not exist, instead of "+
+			"one failed lookup per key (env: VALIDATE_PROJECTS / INPUT_VALIDATE_PROJECTS)")
+	cmd.Flags().String(flagIssueTypes, "",
+		`Only act on issues of these comma-separated types, e.g. "Bug,Task"; others are skipped `+
+			"(env: ISSUE_TYPES / INPUT_ISSUE_TYPES)")
+	cmd.Flags().String(flagFilterJQL, "",
+		`JQL condition extracted issues must also match, e.g. "status != Done AND assignee = currentUser()"; `+
+			"checked with one search (env: FILTER_JQL / INPUT_FILTER_JQL)")
+	cmd.Flags().String(flagProjectCreds, "",
+		"Per-project service accounts chosen by issue key prefix, e.g. \"GAIA=svc-gaia:TOKEN;OPS=PAT\" "+
+			"(env: PROJECT_CREDENTIALS / INPUT_PROJECT_CREDENTIALS)")
+	cmd.Flags().Int(flagMaxIssues, 0,
+		"Safety cap on the number of issues acted on, 0 for no cap (env: MAX_ISSUES / INPUT_MAX_ISSUES)")
+	cmd.Flags().String(flagMaxIssuesOn, "",
+		"What to do past --max-issues: abort (default) or truncate to the first issues with a warning "+
+			"(env: MAX_ISSUES_MODE / INPUT_MAX_ISSUES_MODE)")
+	cmd.Flags().String(flagTrailers, "",
+		"Comma-separated trailer tokens read by --trailers-only (default "+
+			strings.Join(issuekey.DefaultTrailers, ",")+") (env: TRAILERS / INPUT_TRAILERS)")
 	cmd.Flags().
 		String(flagToTransition, "", "Target transition name (env: TRANSITION / INPUT_TRANSITION)")
+	cmd.Flags().String(flagClosingRule, "",
+		`Transition for issues referenced with a closing keyword ("Closes ABC-1", "fixes ABC-2"); `+
+			"plainly mentioned issues use --to-transition, which may be empty to only comment "+
+			"(env: CLOSING_TRANSITION / INPUT_CLOSING_TRANSITION)")
+	cmd.Flags().String(flagBranchRules, "",
+		`Per-branch transitions, e.g. "feature/*=In Progress;main=Done"; the first matching rule `+
+			"overrides --to-transition (env: BRANCH_TRANSITIONS / INPUT_BRANCH_TRANSITIONS)")
+	cmd.Flags().String(flagBranch, "",
+		"Branch matched against --branch-transitions; defaults to GITHUB_HEAD_REF or "+
+			"GITHUB_REF_NAME, or CI_MERGE_REQUEST_SOURCE_BRANCH_NAME or CI_COMMIT_REF_NAME on GitLab "+
+			"(env: BRANCH / INPUT_BRANCH)")
+	cmd.Flags().String(flagCommitTypes, "",
+		`Only act on issues referenced by Conventional Commits of these types, e.g. "fix,feat" `+
+			"(env: COMMIT_TYPES / INPUT_COMMIT_TYPES)")
+	cmd.Flags().String(flagTypeRules, "",
+		`Per commit type transitions, e.g. "feat=In Review;fix=Done"; other issues use --to-transition `+
+			"(env: COMMIT_TYPE_TRANSITIONS / INPUT_COMMIT_TYPE_TRANSITIONS)")
 	cmd.Flags().
 		String(flagResolution, "", "Resolution name to set (env: RESOLUTION / INPUT_RESOLUTION)")
 	cmd.Flags().
@@ -58,11 +129,85 @@ func newRunCmd() *cobra.Command {
 		String(flagAssignee, "", "Username to assign the issues to (env: ASSIGNEE / INPUT_ASSIGNEE)")
 	cmd.Flags().
 		Bool(flagMarkdown, false, "Convert comment from Markdown to Jira syntax (env: MARKDOWN / INPUT_MARKDOWN)")
+	cmd.Flags().String(flagCodeLang, "",
+		"Language for --markdown fenced code blocks that name none; empty renders them as "+
+			"{noformat} (env: CODE_DEFAULT_LANG / INPUT_CODE_DEFAULT_LANG)")
+	cmd.Flags().String(flagMermaidURL, "",
+		"Render --markdown mermaid diagrams as images from this service, e.g. "+
+			"https://mermaid.ink/img/ (env: MERMAID_IMAGE_URL / INPUT_MERMAID_IMAGE_URL)")
+	cmd.Flags().String(flagMarkdownBase, "",
+		"URL relative --markdown links and images resolve against; defaults to the repository "+
+			"at the commit in GitHub Actions and GitLab CI "+
+			"(env: MARKDOWN_BASE_URL / INPUT_MARKDOWN_BASE_URL)")
+	cmd.Flags().Bool(flagLinkIssues, false,
+		"Link the issue keys in a --markdown comment to their Jira pages instead of leaving them "+
+			"for Jira to link (env: LINK_ISSUE_KEYS / INPUT_LINK_ISSUE_KEYS)")
+	cmd.Flags().String(flagMentions, "",
+		`Map Git usernames to Jira users for --markdown @mentions: "gituser=jirauser" pairs `+
+			"separated by ; or newlines, or a JSON object (env: MENTIONS / INPUT_MENTIONS)")
+	cmd.Flags().String(flagMentionsFile, "",
+		"File holding --mentions entries, which --mentions overrides "+
+			"(env: MENTIONS_FILE / INPUT_MENTIONS_FILE)")
+	cmd.Flags().String(flagMentionMode, "",
+		"Which --markdown @name text becomes a mention: loose (default, not inside a word), "+
+			"strict (after whitespace only), or off (env: MENTION_MODE / INPUT_MENTION_MODE)")
+	cmd.Flags().Bool(flagImagesAttach, false,
+		"Render --markdown images named by a bare file name as that attachment of the issue "+
+			"(env: IMAGES_ATTACHED / INPUT_IMAGES_ATTACHED)")
+	cmd.Flags().Int(flagHeadingShift, 0,
+		"Shift --markdown heading levels down by this much, e.g. 2 renders # as h3. "+
+			"(env: HEADING_OFFSET / INPUT_HEADING_OFFSET)")
+	cmd.Flags().String(flagTOC, "",
+		"Put a table of contents before a --markdown comment with headings: macro ({toc}) or "+
+			"list (links to the headings) (env: TOC / INPUT_TOC)")
+	cmd.Flags().String(flagLineBreaks, "",
+		"How line breaks inside a --markdown paragraph render: preserve (default), space, or "+
+			"hard (\\\\ forced break) (env: LINE_BREAKS / INPUT_LINE_BREAKS)")
+	cmd.Flags().Duration(flagJitter, 0,
+		"Delay the start by up to this window, spread per --jitter-key, so scheduled runs "+
+			"across many repositories don't hit Jira at once (env: JITTER / INPUT_JITTER)")
+	cmd.Flags().String(flagJitterKey, "",
+		"Tenant key that picks a stable slot within --jitter; defaults to "+envGitHubRepository+
+			", random when empty (env: JITTER_KEY / INPUT_JITTER_KEY)")
+	cmd.Flags().Int(flagMaxConcurrency, 0,
+		"Maximum Jira requests in flight across all issues, 0 for unlimited "+
+			"(env: MAX_CONCURRENCY / INPUT_MAX_CONCURRENCY)")
+	cmd.Flags().String(flagReportFile, "",
+		"Write a JSON report of the run (per-issue actions, results, statuses, comment IDs, errors) "+
+			"to this file (env: REPORT_FILE / INPUT_REPORT_FILE)")
+	cmd.Flags().String(flagMetricsFile, "",
+		"Write Prometheus metrics of the run (issues, API calls, latency) to this file for the "+
+			"node_exporter textfile collector (env: METRICS_FILE / INPUT_METRICS_FILE)")
+	cmd.Flags().String(flagMetricsPush, "",
+		"Prometheus Pushgateway URL to push the run's metrics to, grouped by "+envGitHubRepository+
+			" when set (env: METRICS_PUSHGATEWAY / INPUT_METRICS_PUSHGATEWAY)")
+	cmd.Flags().Duration(flagIssueTimeout, defaultIssueTimeout,
+		"Deadline for each issue's request, so one hung request cannot use up the whole run "+
+			"(env: ISSUE_TIMEOUT / INPUT_ISSUE_TIMEOUT)")
+	cmd.Flags().Bool(flagPrecheck, false,
+		"Verify the browse, transition, comment, and assign permissions the run needs on every "+
+			"project before changing anything (env: PRECHECK / INPUT_PRECHECK)")
+	addAuditLogFlag(cmd)
+	cmd.Flags().String(flagPreHook, "",
+		"Shell command run before anything changes, with the fetched issues as JSON on stdin; "+
+			"a non-zero exit aborts the run (env: PRE_HOOK / INPUT_PRE_HOOK)")
+	cmd.Flags().String(flagPostHook, "",
+		"Shell command run at the end with the run report as JSON on stdin; a non-zero exit "+
+			"fails the run (env: POST_HOOK / INPUT_POST_HOOK)")
+	cmd.Flags().Bool(flagFailFast, false,
+		"Cancel the remaining issues of a phase on the first per-issue error "+
+			"(env: FAIL_FAST / INPUT_FAIL_FAST)")
+	cmd.Flags().String(flagFailMode, "",
+		"How per-issue failures affect the exit status: fail (default), warn, or threshold:<n> "+
+			"to tolerate up to n failed issues (env: FAIL_MODE / INPUT_FAIL_MODE)")
+	cmd.Flags().Bool(flagFailOnNoIssues, false,
+		"Fail when the ref references no issue keys instead of exiting 0 "+
+			"(env: FAIL_ON_NO_ISSUES / INPUT_FAIL_ON_NO_ISSUES)")
 
 	return cmd
 }
 
-func run(cmd *cobra.Command) error {
+func run(cmd *cobra.Command) (runErr error) {
 	if err := loadEnvFromCmd(cmd); err != nil {
 		return err
 	}
@@ -71,29 +216,82 @@ func run(cmd *cobra.Command) error {
 	// Allow the free-text inputs to be piped in via the "-" sentinel so run
 	// composes with other tools, e.g. `git log -1 --format=%B | go-jira run --ref -`.
 	var err error
+	if flagBoolValue(cmd, flagStdin) {
+		if config.ref, err = appendStdin(config.ref); err != nil {
+			return err
+		}
+	}
 	if config.ref, err = resolveStdin(config.ref); err != nil {
 		return err
 	}
+	if config.ref, err = appendRefFile(config.ref, config.refFile); err != nil {
+		return err
+	}
+	if config.ref, err = appendCommitRange(cmdContext(cmd), config.ref, config.commitRange); err != nil {
+		return err
+	}
+	if config.ref, err = appendPullRequest(cmdContext(cmd), config.ref, config.prNumber); err != nil {
+		return err
+	}
+	if config.refs == "" {
+		if config.ref, err = refFromEvent(config.ref); err != nil {
+			return err
+		}
+		config.ref = refFromGitLab(config.ref)
+	}
 	if config.comment, err = resolveStdin(config.comment); err != nil {
 		return err
 	}
 	if err := validateConfig(config); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
+	failMode, _ := parseFailMode(config.failMode)
+	if err := checkAuditLog(config.auditLog); err != nil {
+		return err
+	}
+	if config.toTransition, err = branchTransition(config); err != nil {
+		return err
+	}
 
 	if config.debug {
-		_ = godump.Dump(map[string]any{
+		_ = godump.Dump(redactAny(map[string]any{
 			"ref":          config.ref,
+			"refs":         config.refs,
 			"issuePattern": config.issuePattern,
 			"toTransition": config.toTransition,
 			"resolution":   config.resolution,
 			"comment":      config.comment,
 			flagAssignee:   config.assignee,
-		})
+		}))
+	}
+
+	// The jitter delay happens before the timeout starts so a wide spread
+	// window does not eat into the operation's own time budget.
+	if err := waitJitter(cmdContext(cmd), config.jitter, jitterKey(config)); err != nil {
+		return err
+	}
+	// The report is created up front so the metrics exported when run
+	// returns cover failures before the first issue too.
+	start := time.Now()
+	report := newRunReport(config.baseURL)
+	defer func() {
+		exportMetrics(cmdContext(cmd), config, report, time.Since(start), runErr == nil)
+	}()
+
+	tr, err := newTracerFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid tracing configuration: %w", err)
 	}
 
 	ctx, cancel := cmdContextWithTimeout(cmd, 5*time.Minute)
 	defer cancel()
+	ctx = withTracer(ctx, tr)
+	// The root span covers everything below; each phase and each issue gets
+	// a child span, and every Jira call one below that (see tracing.go).
+	ctx, runSpan := startSpan(ctx, "go-jira run", spanKindInternal)
+	defer func() {
+		finishTrace(ctx, tr, runSpan, runErr)
+	}()
 
 	authenticator, err := auth.Resolve(ctx, authConfigFromRun(config))
 	if err != nil {
@@ -104,12 +302,21 @@ func run(cmd *cobra.Command) error {
 	}
 	slog.Info("authenticated", "mode", authenticator.Mode())
 
-	httpClient := createHTTPClient(config, authenticator)
+	httpClient, err := createHTTPClient(config, authenticator)
+	if err != nil {
+		return err
+	}
 	jiraClient, err := jira.NewClient(httpClient, config.baseURL)
 	if err != nil {
 		return fmt.Errorf("error creating jira client: %w", err)
 	}
 
+	projectClients, err := newProjectClients(ctx, config)
+	if err != nil {
+		return err
+	}
+	ctx = withProjectClients(ctx, projectClients)
+
 	user, err := getSelf(ctx, jiraClient)
 	if err != nil {
 		return fmt.Errorf("error getting self: %w", err)
@@ -133,14 +340,46 @@ func run(cmd *cobra.Command) error {
 		)
 	}
 
-	issues, err := processIssues(ctx, jiraClient, config)
+	// Every per-issue outcome is recorded into the report, which renders the
+	// closing summary and the step outputs even when a later phase fails.
+	ctx = withReport(ctx, report)
+	defer func() {
+		printRunSummary(ctx, report)
+		if err := writeGitHubOutputs(os.Getenv(envGitHubOutput), githubOutputs(report)); err != nil {
+			slog.Warn("could not write step outputs", "error", err)
+		}
+		if err := writeGitHubStepSummary(os.Getenv(envGitHubStepSummary), report); err != nil {
+			slog.Warn("could not write step summary", "error", err)
+		}
+		if err := writeReportFile(config.reportFile, report, runErr); err != nil {
+			slog.Warn("could not write report file", "error", err)
+		}
+		// The post hook runs last, on the same report; when it fails a
+		// successful run fails with it.
+		if err := runPostHook(ctx, config, report, runErr); err != nil && runErr == nil {
+			runErr = err
+		}
+	}()
+
+	phaseCtx, phase := startSpan(ctx, "fetch issues", spanKindInternal)
+	issues, err := processIssues(phaseCtx, jiraClient, config)
+	phase.finish(err)
 	if err != nil {
 		return fmt.Errorf("error processing issues: %w", err)
 	}
 	if len(issues) == 0 {
 		slog.Warn("no issues found, skipping further processing")
-		return nil
+		return failMode.apply(report, nil)
+	}
+	if config.precheck {
+		if err := precheckRun(ctx, jiraClient, config, issues); err != nil {
+			return err
+		}
 	}
+	if err := runPreHook(ctx, config, report, issues); err != nil {
+		return err
+	}
+	ops := newProcessor(ctx, jiraClient, config)
 
 	if config.resolution != "" {
 		var resolutionID string
@@ -157,34 +396,59 @@ func run(cmd *cobra.Command) error {
 		config.resolution = resolutionID
 	}
 
-	if config.toTransition != "" {
-		if err := processTransitions(
-			ctx,
-			jiraClient,
-			config.toTransition,
+	// Each issue takes --to-transition unless its commit type maps to another
+	// transition, so the issues are moved one transition group at a time.
+	groups, err := planTransitions(issues, config)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		phaseCtx, phase := startSpan(ctx, "transition "+g.transition, spanKindInternal,
+			spanAttr{"jira.issue.count", len(g.issues)})
+		err := processTransitions(
+			phaseCtx,
+			ops,
+			g.transition,
 			config.resolution,
-			issues,
-		); err != nil {
-			return fmt.Errorf("error processing transitions: %w", err)
+			g.issues,
+		)
+		phase.finish(err)
+		if err != nil {
+			if err := failMode.apply(report, err); err != nil {
+				return fmt.Errorf("error processing transitions: %w", err)
+			}
 		}
 	}
 
 	if assignee != nil {
-		if err := processAssignee(ctx, jiraClient, issues, assignee); err != nil {
-			return fmt.Errorf("error processing assignee: %w", err)
+		phaseCtx, phase := startSpan(ctx, "assign", spanKindInternal)
+		err := processAssignee(phaseCtx, ops, issues, assignee)
+		phase.finish(err)
+		if err != nil {
+			if err := failMode.apply(report, err); err != nil {
+				return fmt.Errorf("error processing assignee: %w", err)
+			}
 		}
 	}
 
 	if config.comment != "" {
 		if config.markdown {
-			config.comment = markdown.ToJira(config.comment)
+			opts := markdownOptions(ctx, config, jiraClient)
+			config.comment = markdown.ToJiraWithOptions(config.comment, opts)
 		}
-		if err := addComments(ctx, jiraClient, config.comment, issues, user); err != nil {
-			return fmt.Errorf("error adding comments: %w", err)
+		phaseCtx, phase := startSpan(ctx, "comment", spanKindInternal)
+		err := addComments(phaseCtx, ops, config.comment, issues, user)
+		phase.finish(err)
+		if err != nil {
+			if err := failMode.apply(report, err); err != nil {
+				return fmt.Errorf("error adding comments: %w", err)
+			}
 		}
 	}
 
-	return nil
+	// Issues that failed to fetch never reach the phases above, so the
+	// threshold is checked once more against the full report.
+	return failMode.apply(report, nil)
 }
 
 // authConfigFromRun maps the run Config into an auth.Config. In oauth-env mode
@@ -195,6 +459,13 @@ func authConfigFromRun(config Config) auth.Config {
 		Username:          config.username,
 		Password:          config.password,
 		Token:             config.token,
+		Session:           config.sessionAuth,
+		BaseURL:           config.baseURL,
+		Krb5Keytab:        config.krb5Keytab,
+		Krb5Principal:     config.krb5Principal,
+		Krb5CCache:        config.krb5CCache,
+		Krb5Config:        config.krb5Config,
+		Krb5SPN:           config.krb5SPN,
 		OAuthRefreshToken: config.oauthRefreshToken,
 		OAuthClientID:     config.oauthClientID,
 		OAuthBaseURL:      config.baseURL,
@@ -212,12 +483,18 @@ func authConfigFromRun(config Config) auth.Config {
 	// Resolving a Store probes the OS keyring (a write+delete that can trigger a
 	// keychain permission prompt), so only do it when OAuth storage could win:
 	// no injected refresh token, a client ID is configured, and the user has not
-	// supplied an explicit bearer/basic credential. The OAuth client ID has a
-	// build-time embedded default, so without the credential guard a pure
+	// supplied an explicit bearer/basic/Kerberos credential. The OAuth client ID
+	// has a build-time embedded default, so without the credential guard a pure
 	// token/basic run would probe the keyring on every invocation.
-	hasExplicitCred := config.token != "" || (config.username != "" && config.password != "")
-	if config.oauthClientID != "" && config.oauthRefreshToken == "" && !hasExplicitCred {
+	if config.oauthClientID != "" && config.oauthRefreshToken == "" && !config.hasExplicitCred() {
 		cfg.Store = resolveStoreQuiet()
 	}
 	return cfg
 }
+
+// hasExplicitCred reports whether the user supplied a bearer, basic, or
+// Kerberos credential, any of which takes the place of stored OAuth tokens.
+func (c Config) hasExplicitCred() bool {
+	return c.token != "" || (c.username != "" && c.password != "") ||
+		c.krb5Keytab != "" || c.krb5CCache != ""
+}
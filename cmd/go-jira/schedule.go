@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"time"
+)
+
+// envGitHubRepository names the repository of a GitHub Actions run; it is the
+// default jitter key so every repository gets its own stable start slot.
+const envGitHubRepository = "GITHUB_REPOSITORY"
+
+// jitterOffset picks the start delay within [0, window). With a key the offset
+// is derived from its FNV-1a hash, so each tenant keeps the same slot from run
+// to run and a fleet of scheduled runs spreads evenly across the window;
+// without one the offset is random.
+func jitterOffset(window time.Duration, key string) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+	if key == "" {
+		return time.Duration(rand.Int64N(int64(window))) // #nosec G404 -- scheduling, not security
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return time.Duration(h.Sum64() % uint64(window))
+}
+
+// waitJitter delays the start of a scheduled run by jitterOffset so hundreds
+// of repositories triggered by the same cron expression don't all hit Jira at
+// the top of the hour. It returns early with ctx's error on cancellation.
+func waitJitter(ctx context.Context, window time.Duration, key string) error {
+	delay := jitterOffset(window, key)
+	if delay <= 0 {
+		return nil
+	}
+	slog.Info("delaying start by scheduling jitter", "delay", delay.Round(time.Millisecond), "key", key)
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// jitterKey returns the configured tenant key, falling back to the GitHub
+// repository name.
+func jitterKey(config Config) string {
+	if config.jitterKey != "" {
+		return config.jitterKey
+	}
+	return os.Getenv(envGitHubRepository)
+}
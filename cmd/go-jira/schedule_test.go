@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJitterOffset(t *testing.T) {
+	window := time.Hour
+	if got := jitterOffset(0, "acme/api"); got != 0 {
+		t.Errorf("zero window offset = %v, want 0", got)
+	}
+	a1 := jitterOffset(window, "acme/api")
+	a2 := jitterOffset(window, "acme/api")
+	if a1 != a2 {
+		t.Errorf("offset for the same key must be stable: %v != %v", a1, a2)
+	}
+	if a1 < 0 || a1 >= window {
+		t.Errorf("offset %v outside [0, %v)", a1, window)
+	}
+	if b := jitterOffset(window, "acme/web"); b == a1 {
+		t.Errorf("different keys should land in different slots: both %v", b)
+	}
+	for range 100 {
+		if r := jitterOffset(window, ""); r < 0 || r >= window {
+			t.Fatalf("random offset %v outside [0, %v)", r, window)
+		}
+	}
+}
+
+func TestWaitJitterCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := waitJitter(ctx, time.Hour, "acme/api")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("waitJitter error = %v, want context.Canceled", err)
+	}
+}
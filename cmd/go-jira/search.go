@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -23,6 +24,15 @@ var defaultSearchBaseFields = []string{
 	"components",
 }
 
+// searchPageSize is the page size asked for per search request; Jira clamps
+// larger values (to 50 or 100 depending on the deployment), so pages are
+// walked by startAt rather than relying on one oversized page.
+const searchPageSize = 100
+
+// maxSearchResults is the overall cap applied when --limit is 0 (every
+// result), so a broad query cannot page through an entire instance.
+const maxSearchResults = 10000
+
 // newSearchCmd builds the `search` subcommand: run a JQL query and print the
 // matching issues. Equivalent to the Python `search` subcommand.
 func newSearchCmd() *cobra.Command {
@@ -51,7 +61,8 @@ func newSearchCmd() *cobra.Command {
 	cmd.Flags().String(flagJQL, "", `JQL expression; pass "-" to read from stdin (required)`)
 	cmd.Flags().String(flagFields, "",
 		"Comma-separated fields to return (default: summary,status,assignee,labels,components + epic/sprint fields)")
-	cmd.Flags().Int(flagLimit, 20, "Maximum number of results")
+	cmd.Flags().Int(flagLimit, 20,
+		fmt.Sprintf("Maximum number of results, fetched page by page; 0 for every result up to %d", maxSearchResults))
 	_ = cmd.MarkFlagRequired(flagJQL)
 	return cmd
 }
@@ -77,13 +88,7 @@ func runSearch(cmd *cobra.Command) error {
 	}
 
 	fields := searchFields(fieldsArg, config)
-	issues, resp, err := jiraClient.Issue.SearchWithContext(ctx, jql, &jira.SearchOptions{
-		Fields:     fields,
-		MaxResults: limit,
-	})
-	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
-	}
+	issues, err := searchAll(ctx, jiraClient, jql, fields, limit)
 	if err != nil {
 		return fmt.Errorf("error searching issues: %w", err)
 	}
@@ -109,3 +114,41 @@ func searchFields(fieldsArg string, config Config) []string {
 	fields = append(fields, config.epicField, config.sprintField)
 	return fields
 }
+
+// searchAll runs jql and walks the result pages by startAt until limit issues
+// are collected or the results are exhausted, so queries matching more than
+// one page (e.g. a release with >50 issues) are returned in full. A limit of
+// 0 means every result, capped at maxSearchResults.
+func searchAll(
+	ctx context.Context,
+	jiraClient *jira.Client,
+	jql string,
+	fields []string,
+	limit int,
+) ([]jira.Issue, error) {
+	if limit <= 0 || limit > maxSearchResults {
+		limit = maxSearchResults
+	}
+	var issues []jira.Issue
+	for len(issues) < limit {
+		page, resp, err := jiraClient.Issue.SearchWithContext(ctx, jql, &jira.SearchOptions{
+			Fields:     fields,
+			StartAt:    len(issues),
+			MaxResults: min(limit-len(issues), searchPageSize),
+		})
+		if resp != nil && resp.Body != nil {
+			drainBody(resp.Body)
+		}
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, page...)
+		if len(page) == 0 || resp.StartAt+len(page) >= resp.Total {
+			break
+		}
+	}
+	if len(issues) > limit {
+		issues = issues[:limit]
+	}
+	return issues, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// projectStyleNextGen is the "style" Jira Cloud reports for team-managed
+// (formerly next-gen) projects; company-managed projects report "classic".
+const projectStyleNextGen = "next-gen"
+
+// sprintFieldSchema is the custom field type of the Jira Software sprint field,
+// used to discover its per-instance ID.
+const sprintFieldSchema = "com.pyxis.greenhopper.jira:gh-sprint"
+
+// isTeamManagedProject reports whether projectKey is a team-managed project.
+// Data Center and older servers omit both "style" and "simplified", so they are
+// always treated as company-managed.
+func isTeamManagedProject(
+	ctx context.Context,
+	jiraClient *jira.Client,
+	projectKey string,
+) (bool, error) {
+	req, err := jiraClient.NewRequestWithContext(
+		ctx, http.MethodGet, "rest/api/2/project/"+projectKey, nil,
+	)
+	if err != nil {
+		return false, err
+	}
+	var project struct {
+		Style      string `json:"style"`
+		Simplified bool   `json:"simplified"`
+	}
+	resp, err := jiraClient.Do(req, &project)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting project %s: %w", projectKey, err)
+	}
+	return project.Simplified || project.Style == projectStyleNextGen, nil
+}
+
+// discoverSprintField returns the ID of the instance's sprint custom field, or
+// "" when none exists (Jira Software not installed).
+func discoverSprintField(ctx context.Context, jiraClient *jira.Client) (string, error) {
+	fields, resp, err := jiraClient.Field.GetListWithContext(ctx)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error listing fields: %w", err)
+	}
+	for _, f := range fields {
+		if f.Schema.Custom == sprintFieldSchema {
+			return f.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// adaptFieldsForProject rewrites the epic and sprint entries of a REST "fields"
+// payload for team-managed projects, so the same --epic / --sprint inputs work
+// on both project types:
+//
+//   - the epic is set through the standard "parent" field, since team-managed
+//     projects have no Epic Link custom field;
+//   - the sprint field ID is discovered from the field list unless the caller
+//     configured one explicitly, because it rarely matches the Server/DC
+//     default there.
+//
+// Company-managed projects are left untouched, and nothing is fetched unless
+// the payload actually carries an epic or sprint value. Detection is
+// best-effort: a failed lookup is logged and the payload is sent as-is, which
+// is exactly the pre-detection behavior.
+func adaptFieldsForProject(
+	ctx context.Context,
+	jiraClient *jira.Client,
+	config Config,
+	projectKey string,
+	fields map[string]any,
+) {
+	epic, hasEpic := fields[config.epicField]
+	sprint, hasSprint := fields[config.sprintField]
+	if !hasEpic && !hasSprint {
+		return
+	}
+	teamManaged, err := isTeamManagedProject(ctx, jiraClient, projectKey)
+	if err != nil {
+		slog.Warn("could not detect project type; assuming company-managed",
+			"project", projectKey, "error", err)
+		return
+	}
+	if !teamManaged {
+		return
+	}
+	slog.Info("team-managed project detected; adapting epic/sprint fields",
+		"project", projectKey)
+
+	if hasEpic {
+		delete(fields, config.epicField)
+		fields["parent"] = map[string]any{flagKey: epic}
+	}
+	if hasSprint && config.sprintField == defaultSprintField {
+		id, err := discoverSprintField(ctx, jiraClient)
+		if err != nil {
+			slog.Warn("could not discover sprint field; using the configured one",
+				"field", config.sprintField, "error", err)
+			return
+		}
+		if id != "" && id != config.sprintField {
+			delete(fields, config.sprintField)
+			fields[id] = sprint
+		}
+	}
+}
+
+// projectKeyFromIssueKey returns the project part of an issue key, e.g. "GAIA"
+// for "GAIA-123".
+func projectKeyFromIssueKey(key string) string {
+	if i := strings.LastIndex(key, "-"); i > 0 {
+		return key[:i]
+	}
+	return key
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// pemBlockPrefix marks a value as inline PEM content rather than a file path.
+const pemBlockPrefix = "-----BEGIN"
+
+// readPEMInput returns the PEM bytes for a TLS setting that may be either a
+// file path or the PEM content itself. CI secrets are often injected as the
+// literal PEM (INPUT_TLS_CERT: ${{ secrets.CERT }}), so both forms are
+// accepted; anything containing a PEM header is treated as inline content.
+func readPEMInput(v string) ([]byte, error) {
+	if strings.Contains(v, pemBlockPrefix) {
+		return []byte(v), nil
+	}
+	b, err := os.ReadFile(v)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", v, err)
+	}
+	return b, nil
+}
+
+// hasCustomTLS reports whether the config changes the default TLS settings.
+func (c Config) hasCustomTLS() bool {
+	return c.insecure || c.caCert != "" || c.tlsCert != "" || c.tlsKey != ""
+}
+
+// hasCustomTransport reports whether a dedicated transport is needed instead
+// of the stdlib default: custom TLS settings or an explicit proxy.
+func (c Config) hasCustomTransport() bool {
+	return c.hasCustomTLS() || c.proxy != ""
+}
+
+// validateTLS loads the configured CA bundle and mTLS client certificate, so
+// a bad path or mismatched key pair fails before any request is attempted.
+func validateTLS(config Config) error {
+	_, err := tlsClientConfig(config)
+	return err
+}
+
+// tlsClientConfig builds the TLS configuration for outbound Jira requests from
+// --insecure, the custom CA bundle (--ca-cert), and the mTLS client
+// certificate (--client-cert / --client-key). It returns nil when nothing is
+// customized so the transport keeps the stdlib defaults.
+func tlsClientConfig(config Config) (*tls.Config, error) {
+	if !config.hasCustomTLS() {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.insecure {
+		tlsConfig.InsecureSkipVerify = true // #nosec G402 -- opt-in via flag
+	}
+
+	if config.caCert != "" {
+		pool, err := caCertPool(config.caCert)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.tlsCert != "" || config.tlsKey != "" {
+		if config.tlsCert == "" || config.tlsKey == "" {
+			return nil, errors.New("tls_cert and tls_key must be set together")
+		}
+		certPEM, err := readPEMInput(config.tlsCert)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		keyPEM, err := readPEMInput(config.tlsKey)
+		if err != nil {
+			return nil, fmt.Errorf("client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// caCertPool returns the system roots extended with the PEM bundle in v (a
+// file path or inline PEM). Keeping the system roots means an internal CA can
+// be added without breaking hosts that use public certificates, such as an
+// OAuth broker or proxy in front of Jira.
+func caCertPool(v string) (*x509.CertPool, error) {
+	bundle, err := readPEMInput(v)
+	if err != nil {
+		return nil, fmt.Errorf("CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, errors.New("CA bundle: no PEM certificates found")
+	}
+	return pool, nil
+}
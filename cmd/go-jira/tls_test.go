@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// genTestCertPEM returns a self-signed certificate and its EC private key,
+// both PEM-encoded.
+func genTestCertPEM(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "go-jira test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+func TestTLSClientConfig(t *testing.T) {
+	certPEM, keyPEM := genTestCertPEM(t)
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certPath, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		config    Config
+		wantNil   bool
+		wantCerts int
+		wantErr   string
+	}{
+		{
+			name:    "defaults leave TLS untouched",
+			config:  Config{},
+			wantNil: true,
+		},
+		{
+			name:   "insecure only",
+			config: Config{insecure: true},
+		},
+		{
+			name:      "cert and key from files",
+			config:    Config{tlsCert: certPath, tlsKey: keyPath},
+			wantCerts: 1,
+		},
+		{
+			name:      "cert and key as inline PEM",
+			config:    Config{tlsCert: string(certPEM), tlsKey: string(keyPEM)},
+			wantCerts: 1,
+		},
+		{
+			name:   "CA bundle as inline PEM",
+			config: Config{caCert: string(certPEM)},
+		},
+		{
+			name:    "CA bundle without certificates",
+			config:  Config{caCert: keyPath},
+			wantErr: "no PEM certificates found",
+		},
+		{
+			name:    "cert without key",
+			config:  Config{tlsCert: certPath},
+			wantErr: "must be set together",
+		},
+		{
+			name:    "missing cert file",
+			config:  Config{tlsCert: filepath.Join(dir, "nope.crt"), tlsKey: keyPath},
+			wantErr: "client certificate",
+		},
+		{
+			name:    "key does not match cert",
+			config:  Config{tlsCert: string(certPEM), tlsKey: string(certPEM)},
+			wantErr: "load client certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tlsClientConfig(tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("expected nil tls.Config, got %+v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("expected non-nil tls.Config")
+			}
+			if len(got.Certificates) != tt.wantCerts {
+				t.Errorf("certificates = %d, want %d", len(got.Certificates), tt.wantCerts)
+			}
+		})
+	}
+}
+
+// TestCreateHTTPClientPresentsClientCert checks the client certificate reaches
+// a server that requires one, as an mTLS gateway in front of Jira would.
+func TestCreateHTTPClientPresentsClientCert(t *testing.T) {
+	certPEM, keyPEM := genTestCertPEM(t)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	client := mustHTTPClient(t, Config{
+		baseURL:  srv.URL,
+		insecure: true, // the test server's cert is self-signed
+		tlsCert:  string(certPEM),
+		tlsKey:   string(keyPEM),
+	}, nil)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+// TestCreateHTTPClientTrustsCACert checks a server signed by a private CA is
+// reachable once its certificate is supplied via --ca-cert, without --insecure.
+func TestCreateHTTPClientTrustsCACert(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	get := func(config Config) error {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, err := createHTTPClient(config, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	if err := get(Config{baseURL: srv.URL}); err == nil {
+		t.Fatal("expected certificate verification failure without a CA bundle")
+	}
+	if err := get(Config{baseURL: srv.URL, caCert: string(caPEM)}); err != nil {
+		t.Fatalf("request with CA bundle failed: %v", err)
+	}
+}
+
+func TestCreateHTTPClientRejectsInvalidTLS(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.crt")
+	for _, config := range []Config{
+		{tlsCert: missing, tlsKey: missing},
+		{caCert: missing},
+	} {
+		if _, err := createHTTPClient(config, nil); err == nil ||
+			!strings.Contains(err.Error(), "invalid TLS configuration") {
+			t.Errorf("createHTTPClient(%+v) error = %v, want the TLS load error", config, err)
+		}
+		if err := validateTLS(config); err == nil {
+			t.Errorf("validateTLS(%+v) = nil, want an error", config)
+		}
+	}
+}
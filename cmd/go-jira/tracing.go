@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appleboy/go-jira/pkg/util"
+)
+
+// OpenTelemetry environment variables honored by the tracer, named as in the
+// OTel SDK specification so an existing collector setup works unchanged.
+// TRACEPARENT, as exported by CI tools such as otel-cli, makes the run a child
+// of the pipeline's own span.
+const (
+	envOTLPEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envOTLPTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	envOTLPHeaders        = "OTEL_EXPORTER_OTLP_HEADERS"
+	envOTelServiceName    = "OTEL_SERVICE_NAME"
+	envTraceParent        = "TRACEPARENT"
+)
+
+// OTLP span kinds and status codes used by the tracer.
+const (
+	spanKindInternal = 1
+	spanKindClient   = 3
+
+	spanStatusError = 2
+)
+
+// maxSpans bounds the spans buffered for one export; later spans are dropped.
+const maxSpans = 10000
+
+// traceTimeout bounds the OTLP export made when run returns.
+const traceTimeout = 10 * time.Second
+
+// tracer buffers the spans of a run and exports them in one OTLP/HTTP JSON
+// request when the run ends. It travels in the run's context (see
+// withTracer), so every Jira call made on that context traces through
+// tracingTransport. Without one, the state when no OTLP endpoint is
+// configured, nothing is recorded.
+type tracer struct {
+	endpoint string
+	headers  map[string]string
+	service  string
+	// parent is the remote span from TRACEPARENT, if any.
+	parent spanContext
+
+	mu    sync.Mutex
+	spans []*span
+}
+
+type tracerCtxKey struct{}
+
+// withTracer attaches the run's tracer to ctx for startSpan; a nil tracer
+// leaves tracing off.
+func withTracer(ctx context.Context, t *tracer) context.Context {
+	if t == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tracerCtxKey{}, t)
+}
+
+// newTracerFromEnv builds a tracer from the OTEL_* environment, or returns nil
+// when neither OTLP endpoint variable is set.
+func newTracerFromEnv() (*tracer, error) {
+	endpoint := os.Getenv(envOTLPTracesEndpoint)
+	if endpoint == "" {
+		base := os.Getenv(envOTLPEndpoint)
+		if base == "" {
+			return nil, nil
+		}
+		endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+	}
+	if u, err := url.Parse(endpoint); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP traces endpoint %q", endpoint)
+	}
+	headers, err := parseOTLPHeaders(os.Getenv(envOTLPHeaders))
+	if err != nil {
+		return nil, err
+	}
+	// Collector headers usually carry an API key.
+	for _, v := range headers {
+		util.Mask(v)
+	}
+	service := os.Getenv(envOTelServiceName)
+	if service == "" {
+		service = "go-jira"
+	}
+	parent, _ := parseTraceParent(os.Getenv(envTraceParent))
+	return &tracer{endpoint: endpoint, headers: headers, service: service, parent: parent}, nil
+}
+
+// parseOTLPHeaders parses OTEL_EXPORTER_OTLP_HEADERS: comma-separated
+// key=value pairs with URL-encoded values, e.g. "x-api-key=abc,x-team=ci".
+func parseOTLPHeaders(raw string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid %s entry %q: want key=value", envOTLPHeaders, pair)
+		}
+		value, err := url.QueryUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value for %q: %w", envOTLPHeaders, k, err)
+		}
+		headers[k] = value
+	}
+	return headers, nil
+}
+
+// spanContext identifies a span within its trace.
+type spanContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+}
+
+func (sc spanContext) valid() bool {
+	return sc.traceID != [16]byte{} && sc.spanID != [8]byte{}
+}
+
+// traceParent renders sc as a W3C traceparent header value, sampled.
+func (sc spanContext) traceParent() string {
+	return "00-" + hex.EncodeToString(sc.traceID[:]) + "-" +
+		hex.EncodeToString(sc.spanID[:]) + "-01"
+}
+
+// parseTraceParent parses a W3C traceparent header value.
+func parseTraceParent(s string) (spanContext, bool) {
+	var sc spanContext
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.traceID[:], []byte(parts[1])); err != nil {
+		return spanContext{}, false
+	}
+	if _, err := hex.Decode(sc.spanID[:], []byte(parts[2])); err != nil {
+		return spanContext{}, false
+	}
+	return sc, sc.valid()
+}
+
+// spanAttr is one span attribute; value is a string or an int.
+type spanAttr struct {
+	key   string
+	value any
+}
+
+// span is one timed operation of the run. All methods are no-ops on a nil
+// receiver, so callers need no guards when tracing is off.
+type span struct {
+	t        *tracer
+	name     string
+	kind     int
+	sc       spanContext
+	parentID [8]byte
+	start    time.Time
+
+	mu     sync.Mutex
+	attrs  []spanAttr
+	errMsg string
+	end    time.Time
+}
+
+type spanCtxKey struct{}
+
+// startSpan starts a span named name as a child of the span in ctx, or of the
+// TRACEPARENT span for the first one, and returns a context carrying it.
+// End it with finish. It records nothing unless ctx carries a tracer.
+func startSpan(
+	ctx context.Context, name string, kind int, attrs ...spanAttr,
+) (context.Context, *span) {
+	t, _ := ctx.Value(tracerCtxKey{}).(*tracer)
+	if t == nil {
+		return ctx, nil
+	}
+	s := &span{t: t, name: name, kind: kind, start: time.Now(), attrs: attrs}
+	parent := t.parent
+	if p, ok := ctx.Value(spanCtxKey{}).(*span); ok {
+		parent = p.sc
+	}
+	if parent.valid() {
+		s.sc.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		_, _ = rand.Read(s.sc.traceID[:])
+	}
+	_, _ = rand.Read(s.sc.spanID[:])
+	return context.WithValue(ctx, spanCtxKey{}, s), s
+}
+
+// setAttr adds an attribute to the span.
+func (s *span) setAttr(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, spanAttr{key: key, value: value})
+}
+
+// finish ends the span, marking it failed when err is non-nil, and queues it
+// for export.
+func (s *span) finish(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.end = time.Now()
+	if err != nil {
+		s.errMsg = util.Redact(err.Error())
+	}
+	s.mu.Unlock()
+
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	if len(s.t.spans) < maxSpans {
+		s.t.spans = append(s.t.spans, s)
+	}
+}
+
+// tracingTransport is a RoundTripper that records a client span per Jira API
+// call and propagates it in the traceparent header, so a slow run can be
+// followed down to the individual transition call.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, s := startSpan(req.Context(), req.Method+" "+req.URL.Path, spanKindClient,
+		spanAttr{"http.request.method", req.Method},
+		spanAttr{"server.address", req.URL.Hostname()},
+		spanAttr{"url.full", util.Redact(req.URL.String())},
+	)
+	if s == nil {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(ctx)
+	req.Header.Set("traceparent", s.sc.traceParent())
+	resp, err := t.base.RoundTrip(req)
+	spanErr := err
+	if resp != nil {
+		s.setAttr("http.response.status_code", resp.StatusCode)
+		if resp.StatusCode >= http.StatusBadRequest {
+			spanErr = fmt.Errorf("unexpected status: %s", resp.Status)
+		}
+	}
+	s.finish(spanErr)
+	return resp, err
+}
+
+// OTLP/HTTP JSON payload, limited to the fields the tracer sets.
+type (
+	otlpAttr struct {
+		Key   string         `json:"key"`
+		Value map[string]any `json:"value"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpSpan struct {
+		TraceID           string     `json:"traceId"`
+		SpanID            string     `json:"spanId"`
+		ParentSpanID      string     `json:"parentSpanId,omitempty"`
+		Name              string     `json:"name"`
+		Kind              int        `json:"kind"`
+		StartTimeUnixNano string     `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+		Attributes        []otlpAttr `json:"attributes,omitempty"`
+		Status            otlpStatus `json:"status"`
+	}
+)
+
+func toOTLPAttrs(attrs []spanAttr) []otlpAttr {
+	out := make([]otlpAttr, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.value.(type) {
+		case int:
+			out = append(out, otlpAttr{Key: a.key, Value: map[string]any{"intValue": strconv.Itoa(v)}})
+		default:
+			out = append(out, otlpAttr{Key: a.key, Value: map[string]any{"stringValue": fmt.Sprint(v)}})
+		}
+	}
+	return out
+}
+
+// payload renders the buffered spans as an OTLP ExportTraceServiceRequest.
+func (t *tracer) payload() ([]byte, int) {
+	t.mu.Lock()
+	spans := append([]*span{}, t.spans...)
+	t.mu.Unlock()
+
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.mu.Lock()
+		o := otlpSpan{
+			TraceID:           hex.EncodeToString(s.sc.traceID[:]),
+			SpanID:            hex.EncodeToString(s.sc.spanID[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        toOTLPAttrs(s.attrs),
+		}
+		if s.parentID != [8]byte{} {
+			o.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		if s.errMsg != "" {
+			o.Status = otlpStatus{Code: spanStatusError, Message: s.errMsg}
+		}
+		s.mu.Unlock()
+		out = append(out, o)
+	}
+	doc := map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource": map[string]any{
+				"attributes": toOTLPAttrs([]spanAttr{
+					{"service.name", t.service},
+					{"service.version", versionString()},
+				}),
+			},
+			"scopeSpans": []any{map[string]any{
+				"scope": map[string]any{"name": "github.com/appleboy/go-jira", "version": versionString()},
+				"spans": out,
+			}},
+		}},
+	}
+	data, _ := json.Marshal(doc)
+	return data, len(out)
+}
+
+// finishTrace ends the run's root span and exports the trace. Export failures
+// are logged, not returned, so tracing never changes the run's outcome. The
+// export gets its own deadline because the run's context may already be done.
+func finishTrace(ctx context.Context, t *tracer, root *span, runErr error) {
+	root.finish(runErr)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), traceTimeout)
+	defer cancel()
+	if err := t.flush(ctx); err != nil {
+		slog.Warn("could not export traces", "error", err)
+	}
+}
+
+// flush exports the buffered spans to the OTLP endpoint. A nil tracer or an
+// empty buffer is a no-op.
+func (t *tracer) flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	data, n := t.payload()
+	if n == 0 {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("export traces: %w", err)
+	}
+	defer drainBody(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("export traces: unexpected status: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewTracerFromEnv(t *testing.T) {
+	t.Setenv(envOTLPEndpoint, "")
+	t.Setenv(envOTLPTracesEndpoint, "")
+	if tr, err := newTracerFromEnv(); tr != nil || err != nil {
+		t.Fatalf("no endpoint: tracer = %v, err = %v", tr, err)
+	}
+
+	t.Setenv(envOTLPEndpoint, "http://collector:4318/")
+	t.Setenv(envOTLPHeaders, "x-api-key=abc%3D1, x-team=ci")
+	t.Setenv(envOTelServiceName, "")
+	t.Setenv(envTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	tr, err := newTracerFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.endpoint != "http://collector:4318/v1/traces" || tr.service != "go-jira" {
+		t.Errorf("tracer = %+v", tr)
+	}
+	if tr.headers["x-api-key"] != "abc=1" || tr.headers["x-team"] != "ci" {
+		t.Errorf("headers = %v", tr.headers)
+	}
+	if !tr.parent.valid() {
+		t.Error("TRACEPARENT was not parsed")
+	}
+
+	t.Setenv(envOTLPHeaders, "no-value")
+	if _, err := newTracerFromEnv(); err == nil {
+		t.Error("expected an error for a malformed header")
+	}
+}
+
+func TestTracingExportsSpans(t *testing.T) {
+	var traceParent string
+	jiraSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer jiraSrv.Close()
+
+	var payload map[string]any
+	var apiKey string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("x-api-key")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+	}))
+	defer collector.Close()
+
+	tr := &tracer{
+		endpoint: collector.URL,
+		headers:  map[string]string{"x-api-key": "k"},
+		service:  "go-jira",
+	}
+	ctx, root := startSpan(withTracer(context.Background(), tr), "go-jira run", spanKindInternal)
+	phaseCtx, phase := startSpan(ctx, "transition Done", spanKindInternal)
+	client := &http.Client{Transport: &tracingTransport{base: http.DefaultTransport}}
+	req, _ := http.NewRequestWithContext(phaseCtx, http.MethodPost,
+		jiraSrv.URL+"/rest/api/2/issue/GAIA-1/transitions", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainBody(resp.Body)
+	phase.finish(errors.New("1 failed"))
+	finishTrace(ctx, tr, root, nil)
+
+	if len(traceParent) != 55 || traceParent[3:35] != hexTraceID(root) {
+		t.Errorf("traceparent = %q, want trace %s", traceParent, hexTraceID(root))
+	}
+	if apiKey != "k" {
+		t.Errorf("x-api-key = %q", apiKey)
+	}
+	resource := payload["resourceSpans"].([]any)[0].(map[string]any)
+	spans := resource["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)
+	if len(spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(spans))
+	}
+	byName := map[string]map[string]any{}
+	for _, s := range spans {
+		m := s.(map[string]any)
+		byName[m["name"].(string)] = m
+	}
+	call := byName["POST /rest/api/2/issue/GAIA-1/transitions"]
+	if call == nil || call["parentSpanId"] != byName["transition Done"]["spanId"] {
+		t.Errorf("call span = %v", call)
+	}
+	status := byName["transition Done"]["status"].(map[string]any)
+	if status["code"] != float64(spanStatusError) {
+		t.Errorf("phase status = %v", status)
+	}
+	if _, ok := byName["go-jira run"]["parentSpanId"]; ok {
+		t.Error("root span should have no parent")
+	}
+}
+
+func TestTracingDisabled(t *testing.T) {
+	ctx, s := startSpan(withTracer(context.Background(), nil), "noop", spanKindInternal)
+	if s != nil || ctx != context.Background() {
+		t.Fatal("startSpan should be a no-op without a tracer")
+	}
+	s.setAttr("k", "v")
+	s.finish(nil)
+	if err := (*tracer)(nil).flush(ctx); err != nil {
+		t.Errorf("flush on nil tracer = %v", err)
+	}
+}
+
+func hexTraceID(s *span) string {
+	return s.sc.traceParent()[3:35]
+}
@@ -2,79 +2,112 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log/slog"
-	"net/http"
-	"strings"
+	"errors"
+
+	"github.com/appleboy/go-jira/pkg/issuekey"
+	"github.com/appleboy/go-jira/pkg/jiraops"
 
 	jira "github.com/andygrunwald/go-jira"
-	"github.com/appleboy/com/convert"
 )
 
-// processTransitions processes issue transitions concurrently
+// processTransitions moves the issues through toTransition concurrently,
+// within the limits of ops (see newProcessor).
 func processTransitions(
 	ctx context.Context,
-	jiraClient *jira.Client,
+	ops *jiraops.Processor,
 	toTransition string,
 	resolution string,
 	issues []*jira.Issue,
 ) error {
-	return forEachIssueConcurrent(issues, "processing transitions", func(iss *jira.Issue) error {
-		// Use the nil-safe accessors: a partial issue response can leave Fields
-		// or Status nil, and a deref here would panic inside the worker
-		// goroutine and take down the whole process.
-		summary := issueSummary(iss)
-		slog.Info("issue info",
-			"key", iss.Key,
-			"summary", summary,
-			"current status", issueStatusName(iss),
+	for _, iss := range issues {
+		// Use the nil-safe accessors: a partial issue response can leave
+		// Fields or Status nil.
+		issueLogger(iss.Key, actionTransition).Info("issue info",
+			"summary", issueSummary(iss),
+			statusKey, issueStatusName(iss),
 		)
+	}
+	outcomes, err := ops.Transition(ctx, issues, toTransition, resolution)
+	report := reportFrom(ctx)
+	for i, out := range outcomes {
+		iss := issues[i]
+		log := issueLogger(iss.Key, actionTransition)
+		if errors.Is(out.Err, jiraops.ErrTransitionNotFound) {
+			log.Warn("transition not found for issue", "transition", toTransition,
+				"hint", `run "go-jira transitions `+iss.Key+`" to list the available ones`)
+			report.record(iss.Key, actionTransition, outcomeSkipped, out.Reason())
+			continue
+		}
+		if recordSetback(ctx, out, "error moving issue") {
+			continue
+		}
+		log.Info("issue moved to transition",
+			"summary", issueSummary(iss),
+			"transition", toTransition,
+			logKeyDuration, out.Duration,
+		)
+		report.record(iss.Key, actionTransition, outcomeOK, "")
+		report.setNewStatus(iss.Key, out.NewStatus)
+	}
+	return err
+}
 
-		transitionFound := false
-		for _, transition := range iss.Transitions {
-			if !strings.EqualFold(transition.Name, toTransition) {
-				continue
-			}
-			transitionFound = true
+// transitionGroup is a set of issues moved through the same transition.
+type transitionGroup struct {
+	transition string
+	issues     []*jira.Issue
+}
 
-			input := &jira.TransitionPayloadInput{
-				TicketID:     iss.Key,
-				TransitionID: transition.ID,
-			}
-			if resolution != "" {
-				input.ResolutionID = convert.ToPtr(resolution)
-			}
-			resp, err := jiraClient.Issue.DoTransitionPayloadWithContext(
-				ctx,
-				input,
-			)
-			if resp != nil && resp.Body != nil {
-				defer resp.Body.Close()
-			}
-			if err != nil {
-				slog.Error("error moving issue", "issue", iss.Key, "error", err)
-				return err
-			}
-			if resp.StatusCode != http.StatusNoContent {
-				slog.Error("error moving issue", "issue", iss.Key, statusKey, resp.Status)
-				return fmt.Errorf("unexpected status: %s", resp.Status)
+// planTransitions groups issues by the transition each should take:
+// --closing-transition for issues referenced with a closing keyword ("Closes
+// ABC-1"), then the one configured for the type of the commit referencing
+// it, falling back to --to-transition. Issues with none are left out, so a
+// plainly mentioned issue can just get the comment. Groups are ordered by
+// their first issue.
+func planTransitions(issues []*jira.Issue, config Config) ([]transitionGroup, error) {
+	byType, err := parseTypeTransitions(config.commitTypeTransitions)
+	if err != nil {
+		return nil, err
+	}
+	var keyTypes map[string]string
+	if len(byType) > 0 {
+		keyTypes, err = issueCommitTypes(config, parseCommitTypes(config.commitTypes))
+		if err != nil {
+			return nil, err
+		}
+	}
+	closing := map[string]bool{}
+	if config.closingTransition != "" {
+		re, err := issuekey.Compile(config.issuePattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, text := range refTexts(config) {
+			for _, k := range issuekey.Keys(issuekey.FindClosing(text, re)) {
+				closing[k] = true
 			}
-			slog.Info("issue moved to transition",
-				"key", iss.Key,
-				"summary", summary,
-				"transition", transition.Name,
-			)
-			// The issue has moved; stop scanning so a second transition with the
-			// same name isn't attempted against the already-transitioned issue.
-			break
 		}
-
-		if !transitionFound {
-			slog.Warn("transition not found for issue",
-				"issue", iss.Key,
-				"transition", toTransition,
-			)
+	}
+	var groups []transitionGroup
+	index := map[string]int{}
+	for _, iss := range issues {
+		transition := config.toTransition
+		if t, ok := byType[keyTypes[iss.Key]]; ok {
+			transition = t
+		}
+		if closing[iss.Key] {
+			transition = config.closingTransition
+		}
+		if transition == "" {
+			continue
+		}
+		i, ok := index[transition]
+		if !ok {
+			i = len(groups)
+			index[transition] = i
+			groups = append(groups, transitionGroup{transition: transition})
 		}
-		return nil
-	})
+		groups[i].issues = append(groups[i].issues, iss)
+	}
+	return groups, nil
 }
@@ -198,6 +198,35 @@ func TestProcessTransitions(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:         "archived issue is skipped, not failed",
+			toTransition: "Done",
+			resolution:   "",
+			issues: []*jira.Issue{
+				{
+					Key: "OLD-1",
+					Fields: &jira.IssueFields{
+						Summary: "Ancient issue",
+						Status: &jira.Status{
+							Name: "Closed",
+						},
+					},
+					Transitions: []jira.Transition{
+						{ID: "1", Name: "Done"},
+					},
+				},
+			},
+			setupServer: func(t *testing.T) *httptest.Server {
+				return httptest.NewServer(
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.Header().Set("Content-Type", "application/json")
+						w.WriteHeader(http.StatusForbidden)
+						_, _ = w.Write([]byte(`{"errorMessages":["This issue is archived. You can't edit it."]}`))
+					}),
+				)
+			},
+			wantErr: false,
+		},
 		{
 			name:         "partial failure with multiple issues",
 			toTransition: "Done",
@@ -280,7 +309,8 @@ func TestProcessTransitions(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			err = processTransitions(ctx, jiraClient, tt.toTransition, tt.resolution, tt.issues)
+			ops := newProcessor(ctx, jiraClient, Config{})
+			err = processTransitions(ctx, ops, tt.toTransition, tt.resolution, tt.issues)
 
 			if tt.wantErr {
 				if err == nil {
@@ -315,3 +345,67 @@ func createManyIssues(count int) []*jira.Issue {
 	}
 	return issues
 }
+
+func TestPlanTransitions(t *testing.T) {
+	issues := []*jira.Issue{{Key: "GAIA-1"}, {Key: "GAIA-3"}, {Key: "GAIA-4"}, {Key: "GAIA-5"}}
+	config := Config{
+		ref:                   conventionalLog,
+		toTransition:          "Done",
+		commitTypeTransitions: "feat=In Review; fix=Done\nchore=Backlog",
+	}
+	groups, err := planTransitions(issues, config)
+	if err != nil {
+		t.Fatalf("planTransitions: %v", err)
+	}
+	var got []string
+	for _, g := range groups {
+		var keys []string
+		for _, iss := range g.issues {
+			keys = append(keys, iss.Key)
+		}
+		got = append(got, g.transition+"="+strings.Join(keys, "+"))
+	}
+	// GAIA-1 takes its first commit (feat), GAIA-5 is in the chore section.
+	want := "In Review=GAIA-1,Done=GAIA-3,Backlog=GAIA-4+GAIA-5"
+	if strings.Join(got, ",") != want {
+		t.Errorf("groups = %v, want %s", got, want)
+	}
+
+	// Without type rules every issue takes --to-transition, and none is
+	// planned when that is empty too.
+	groups, _ = planTransitions(issues, Config{ref: conventionalLog, toTransition: "Done"})
+	if len(groups) != 1 || len(groups[0].issues) != 4 {
+		t.Errorf("default plan = %+v", groups)
+	}
+	if groups, _ = planTransitions(issues, Config{ref: conventionalLog}); len(groups) != 0 {
+		t.Errorf("expected no groups, got %+v", groups)
+	}
+
+	if _, err := planTransitions(issues, Config{commitTypeTransitions: "feat"}); err == nil {
+		t.Error("expected an error for a malformed rule")
+	}
+}
+
+func TestPlanTransitionsClosingKeywords(t *testing.T) {
+	issues := []*jira.Issue{{Key: "GAIA-1"}, {Key: "GAIA-2"}, {Key: "GAIA-3"}}
+	ref := "Fixes GAIA-1, relates to GAIA-2\n\nfeat: resolves GAIA-3"
+	groups, err := planTransitions(issues, Config{
+		ref:                   ref,
+		closingTransition:     "Done",
+		commitTypeTransitions: "feat=In Review",
+	})
+	if err != nil {
+		t.Fatalf("planTransitions: %v", err)
+	}
+	// GAIA-2 is only mentioned and there is no --to-transition, so it is left
+	// for the comment; the closing keyword wins over the feat rule for GAIA-3.
+	if len(groups) != 1 || groups[0].transition != "Done" || len(groups[0].issues) != 2 ||
+		groups[0].issues[0].Key != "GAIA-1" || groups[0].issues[1].Key != "GAIA-3" {
+		t.Errorf("groups = %+v", groups)
+	}
+
+	groups, _ = planTransitions(issues, Config{ref: ref, closingTransition: "Done", toTransition: "In Progress"})
+	if len(groups) != 2 || groups[1].transition != "In Progress" || groups[1].issues[0].Key != "GAIA-2" {
+		t.Errorf("groups with --to-transition = %+v", groups)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// transitionInfo is one transition listed by `transitions`: its ID, name, the
+// status it leads to, and the fields the transition screen requires.
+type transitionInfo struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	To             string   `json:"to"`
+	ToCategory     string   `json:"to_category,omitempty"`
+	RequiredFields []string `json:"required_fields,omitempty"`
+}
+
+// newTransitionsCmd builds the `transitions` subcommand: list the transitions
+// the current user can take on an issue from its current status, which is what
+// run matches --to-transition against.
+func newTransitionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "transitions [KEY]",
+		Short:   "List the transitions available on an issue",
+		GroupID: groupIssues,
+		Long: `List the transitions the current user can take on an issue from its current
+status: ID, name, target status, and any fields the transition requires.
+
+run matches --to-transition against these names (case-insensitively), so use
+this when run reports "transition not found".`,
+		Example: `  # Which transitions can the pipeline's account take on GAIA-123?
+  go-jira transitions GAIA-123 --output text
+
+  # Same, as JSON
+  go-jira transitions --key GAIA-123`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTransitions(cmd, args)
+		},
+	}
+	addCommonFlags(cmd)
+	addOAuthFlags(cmd)
+	addAuthFlags(cmd)
+	addOutputFlag(cmd)
+	cmd.Flags().String(flagKey, "", "Issue key, e.g. GAIA-123 (or pass it as the argument)")
+	return cmd
+}
+
+func runTransitions(cmd *cobra.Command, args []string) error {
+	config, err := loadDataConfig(cmd)
+	if err != nil {
+		return err
+	}
+	key, _ := cmd.Flags().GetString(flagKey)
+	if len(args) == 1 {
+		key = args[0]
+	}
+	if key == "" {
+		return &cliError{
+			code:    exitUsage,
+			kind:    kindUsage,
+			message: "an issue key is required, e.g. go-jira transitions GAIA-123",
+			err:     errors.New("missing issue key"),
+		}
+	}
+
+	ctx, cancel := cmdContextWithTimeout(cmd, time.Minute)
+	defer cancel()
+
+	jiraClient, err := resolveJiraClient(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	transitions, resp, err := jiraClient.Issue.GetTransitionsWithContext(ctx, key)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting transitions for %s: %w", key, err)
+	}
+
+	out := make([]transitionInfo, 0, len(transitions))
+	for _, t := range transitions {
+		info := transitionInfo{
+			ID:         t.ID,
+			Name:       t.Name,
+			To:         t.To.Name,
+			ToCategory: t.To.StatusCategory.Name,
+		}
+		for field, f := range t.Fields {
+			if f.Required {
+				info.RequiredFields = append(info.RequiredFields, field)
+			}
+		}
+		sort.Strings(info.RequiredFields)
+		out = append(out, info)
+	}
+
+	return emitResult(config, out, func() {
+		if len(out) == 0 {
+			fmt.Fprintf(os.Stdout, "no transitions available on %s for this user\n", key)
+			return
+		}
+		for _, t := range out {
+			fmt.Fprintf(os.Stdout, "%s\t%s\t→ %s", t.ID, t.Name, t.To)
+			if len(t.RequiredFields) > 0 {
+				fmt.Fprintf(os.Stdout, "\t(requires %s)", strings.Join(t.RequiredFields, ", "))
+			}
+			fmt.Fprintln(os.Stdout)
+		}
+	})
+}
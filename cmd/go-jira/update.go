@@ -31,6 +31,7 @@ func newUpdateCmd() *cobra.Command {
 	addCommonFlags(cmd)
 	addOAuthFlags(cmd)
 	addAuthFlags(cmd)
+	addAuditLogFlag(cmd)
 	addOutputFlag(cmd)
 	addCustomFieldFlags(cmd)
 	addEditableIssueFlags(cmd)
@@ -64,10 +65,11 @@ func runUpdate(cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
+	adaptFieldsForProject(ctx, jiraClient, config, projectKeyFromIssueKey(key), fields)
 
 	resp, err := jiraClient.Issue.UpdateIssueWithContext(ctx, key, map[string]any{"fields": fields})
 	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+		defer drainBody(resp.Body)
 	}
 	if err != nil {
 		return fmt.Errorf("error updating issue %s: %w", key, err)
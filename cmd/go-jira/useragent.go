@@ -0,0 +1,31 @@
+package main
+
+import "net/http"
+
+// userAgentProduct is the product token Jira admins see in access logs.
+const userAgentProduct = "go-jira-action"
+
+// userAgent returns the identifying User-Agent, e.g.
+// "go-jira-action/v1.4.0 (3f2c1ab)"; the commit is omitted for unstamped
+// builds.
+func userAgent() string {
+	ua := userAgentProduct + "/" + versionString()
+	if Commit != "" {
+		ua += " (" + Commit + ")"
+	}
+	return ua
+}
+
+// userAgentTransport is a RoundTripper that identifies this tool on every
+// request, so Jira admins can attribute, rate-limit, or allow-list its traffic
+// instead of seeing Go's generic "Go-http-client/1.1".
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
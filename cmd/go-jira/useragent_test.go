@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	origVersion, origCommit := Version, Commit
+	t.Cleanup(func() { Version, Commit = origVersion, origCommit })
+
+	Version, Commit = "", ""
+	if got := userAgent(); got != "go-jira-action/dev" {
+		t.Errorf("unstamped userAgent() = %q", got)
+	}
+	Version, Commit = "v1.4.0", "3f2c1ab"
+	if got := userAgent(); got != "go-jira-action/v1.4.0 (3f2c1ab)" {
+		t.Errorf("stamped userAgent() = %q", got)
+	}
+}
+
+func TestCreateHTTPClientUserAgent(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	get := func(config Config) string {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := mustHTTPClient(t, config, nil).Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		return got
+	}
+
+	if ua := get(Config{baseURL: srv.URL}); ua != userAgent() {
+		t.Errorf("User-Agent = %q, want %q", ua, userAgent())
+	}
+	// An explicit User-Agent in --headers takes precedence.
+	if ua := get(Config{baseURL: srv.URL, headers: "User-Agent: custom/1.0"}); ua != "custom/1.0" {
+		t.Errorf("User-Agent = %q, want custom/1.0", ua)
+	}
+}
@@ -1,27 +1,44 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/appleboy/go-jira/pkg/auth"
+	"github.com/appleboy/go-jira/pkg/util"
 
 	jira "github.com/andygrunwald/go-jira"
 	"github.com/spf13/cobra"
 )
 
+// whoamiResult is what `whoami` reports: the authenticated user, the groups
+// it belongs to, and how it authenticated.
+type whoamiResult struct {
+	DisplayName string   `json:"display_name"`
+	Email       string   `json:"email,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	AccountID   string   `json:"account_id,omitempty"`
+	Groups      []string `json:"groups"`
+	BaseURL     string   `json:"base_url"`
+	AuthMode    string   `json:"auth_mode"`
+}
+
 // newWhoamiCmd builds the `whoami` subcommand: it resolves the active
 // authenticator (by the same priority as `run`) and reports the authenticated
-// user plus the auth mode in use.
+// user, its groups, and the auth mode in use, as text or JSON.
 func newWhoamiCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "whoami",
 		Short:   "Show the authenticated Jira user and the active auth mode",
 		GroupID: groupAuth,
-		Long: `Resolve the active authenticator and report the authenticated user, base URL,
-and auth mode. Run this first to confirm you are logged in before issuing
-other commands.
+		Long: `Resolve the active authenticator and report the authenticated user (display
+name, email, username or account ID, and groups), base URL, and auth mode. Run
+this first to confirm you are logged in before issuing other commands, or in a
+pipeline to see which service account it actually uses.
 
 Recovering from an authentication failure (exit code 3):
   1. The stored OAuth access token is most likely expired — run
@@ -37,6 +54,9 @@ Recovering from an authentication failure (exit code 3):
   # Verify a bearer token
   go-jira whoami --base-url https://jira.example.com --token "$JIRA_TOKEN"
 
+  # Machine-readable account details, e.g. for a pipeline log
+  go-jira whoami --output json
+
   # Recommended recovery when whoami fails with an auth error (exit code 3)
   go-jira token refresh --base-url https://jira.example.com
   go-jira whoami --base-url https://jira.example.com`,
@@ -50,6 +70,11 @@ Recovering from an authentication failure (exit code 3):
 	cmd.Flags().String(flagUsername, "", "Jira username (env: USERNAME / INPUT_USERNAME)")
 	cmd.Flags().String(flagPassword, "", "Jira password (prefer env: PASSWORD / INPUT_PASSWORD)")
 	cmd.Flags().String(flagToken, "", "Jira API token (prefer env: TOKEN / INPUT_TOKEN)")
+	cmd.Flags().Bool(flagSessionAuth, false,
+		"Log in with username/password through the legacy session cookie endpoint instead of "+
+			"Basic Auth (env: SESSION_AUTH / INPUT_SESSION_AUTH)")
+	addKerberosFlags(cmd)
+	cmd.Flags().String(flagOutput, outputText, "Output format: text|json (env: OUTPUT / INPUT_OUTPUT)")
 	return cmd
 }
 
@@ -61,6 +86,15 @@ func runWhoami(cmd *cobra.Command) error {
 	if err := requireBaseURL(config); err != nil {
 		return err
 	}
+	// Unlike the data commands whoami defaults to text, its output before
+	// --output existed.
+	if !flagChanged(cmd, flagOutput) && util.GetGlobalValue("output") == "" {
+		config.output = outputText
+	}
+	if config.output != outputJSON && config.output != outputText {
+		return fmt.Errorf("invalid output format %q: must be %q or %q",
+			config.output, outputJSON, outputText)
+	}
 
 	ctx, cancel := cmdContextWithTimeout(cmd, time.Minute)
 	defer cancel()
@@ -73,19 +107,65 @@ func runWhoami(cmd *cobra.Command) error {
 		return fmt.Errorf("auth validation: %w", err)
 	}
 
-	httpClient := createHTTPClient(config, authenticator)
+	httpClient, err := createHTTPClient(config, authenticator)
+	if err != nil {
+		return err
+	}
 	jiraClient, err := jira.NewClient(httpClient, config.baseURL)
 	if err != nil {
 		return fmt.Errorf("error creating jira client: %w", err)
 	}
-	user, err := getSelf(ctx, jiraClient)
+	user, groups, err := getSelfWithGroups(ctx, jiraClient)
 	if err != nil {
 		return fmt.Errorf("error getting self: %w", err)
 	}
 
-	fmt.Fprintf(os.Stderr, "Authenticated as %s <%s> (%s)\n",
-		user.DisplayName, user.EmailAddress, user.Name)
-	fmt.Fprintf(os.Stderr, "Base URL:  %s\n", config.baseURL)
-	fmt.Fprintf(os.Stderr, "Auth mode: %s\n", authenticator.Mode())
-	return nil
+	result := whoamiResult{
+		DisplayName: user.DisplayName,
+		Email:       user.EmailAddress,
+		Name:        user.Name,
+		AccountID:   user.AccountID,
+		Groups:      groups,
+		BaseURL:     config.baseURL,
+		AuthMode:    authenticator.Mode(),
+	}
+	return emitResult(config, result, func() {
+		fmt.Fprintf(os.Stdout, "Authenticated as %s <%s> (%s)\n",
+			user.DisplayName, user.EmailAddress, userID(user))
+		if user.AccountID != "" && user.Name != "" {
+			fmt.Fprintf(os.Stdout, "Account ID: %s\n", user.AccountID)
+		}
+		fmt.Fprintf(os.Stdout, "Groups:    %s\n", strings.Join(groups, ", "))
+		fmt.Fprintf(os.Stdout, "Base URL:  %s\n", config.baseURL)
+		fmt.Fprintf(os.Stdout, "Auth mode: %s\n", authenticator.Mode())
+	})
+}
+
+// getSelfWithGroups retrieves the current user together with the names of
+// its groups, which the plain myself call leaves out.
+func getSelfWithGroups(ctx context.Context, jiraClient *jira.Client) (*jira.User, []string, error) {
+	req, err := jiraClient.NewRequestWithContext(
+		ctx, http.MethodGet, "rest/api/2/myself?expand=groups", nil,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	var self struct {
+		jira.User
+		Groups struct {
+			Items []jira.UserGroup `json:"items"`
+		} `json:"groups"`
+	}
+	resp, err := jiraClient.Do(req, &self)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	groups := make([]string, 0, len(self.Groups.Items))
+	for _, g := range self.Groups.Items {
+		groups = append(groups, g.Name)
+	}
+	return &self.User, groups, nil
 }
@@ -14,6 +14,8 @@ import "net/http"
 const (
 	ModeBasic        = "basic"
 	ModeBearer       = "bearer"
+	ModeSession      = "session"
+	ModeSPNEGO       = "spnego"
 	ModeOAuthStorage = "oauth-storage"
 	ModeOAuthEnv     = "oauth-env"
 )
@@ -20,10 +20,11 @@ func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, erro
 	}, nil
 }
 
-// Compile-time assertions that both implementations satisfy Authenticator.
+// Compile-time assertions that the implementations satisfy Authenticator.
 var (
 	_ Authenticator = (*BasicAuth)(nil)
 	_ Authenticator = (*BearerAuth)(nil)
+	_ Authenticator = (*SessionAuth)(nil)
 )
 
 func TestModeIdentifiers(t *testing.T) {
@@ -33,6 +34,7 @@ func TestModeIdentifiers(t *testing.T) {
 	}{
 		{&BasicAuth{Username: "u", Password: "p"}, "basic"},
 		{&BearerAuth{Token: "t"}, "bearer"},
+		{&SessionAuth{BaseURL: "https://jira", Username: "u", Password: "p"}, "session"},
 	}
 	for _, tt := range tests {
 		if got := tt.auth.Mode(); got != tt.want {
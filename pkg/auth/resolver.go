@@ -17,6 +17,19 @@ type Config struct {
 	Password string
 	Token    string
 
+	// Session switches username + password from Basic Auth to the legacy
+	// cookie login (rest/auth/1/session) against BaseURL.
+	Session bool
+	BaseURL string
+
+	// Kerberos / SPNEGO: a keytab (with principal) or a ticket cache selects
+	// SPNEGO; krb5.conf and the service principal are optional overrides.
+	Krb5Keytab    string
+	Krb5Principal string
+	Krb5CCache    string
+	Krb5Config    string
+	Krb5SPN       string
+
 	// OAuth env-injection mode (CI/CD)
 	OAuthRefreshToken string
 
@@ -51,7 +64,8 @@ type Config struct {
 //  1. oauth-env     (JIRA_OAUTH_REFRESH_TOKEN present)
 //  2. oauth-storage (a token for this base URL/client exists in storage)
 //  3. bearer        (token / --token)
-//  4. basic         (username + password)
+//  4. spnego        (Kerberos keytab or ticket cache)
+//  5. session/basic (username + password; session when cfg.Session is set)
 func Resolve(ctx context.Context, cfg Config) (Authenticator, error) {
 	if cfg.OAuthRefreshToken != "" {
 		return resolveOAuthEnv(ctx, cfg)
@@ -68,6 +82,16 @@ func Resolve(ctx context.Context, cfg Config) (Authenticator, error) {
 	if cfg.Token != "" {
 		return &BearerAuth{Token: cfg.Token}, nil
 	}
+	if cfg.Krb5Keytab != "" || cfg.Krb5CCache != "" {
+		return newSPNEGOAuth(cfg)
+	}
+	if cfg.Username != "" && cfg.Password != "" && cfg.Session {
+		return &SessionAuth{
+			BaseURL:  cfg.BaseURL,
+			Username: cfg.Username,
+			Password: cfg.Password,
+		}, nil
+	}
 	if cfg.Username != "" && cfg.Password != "" {
 		return &BasicAuth{Username: cfg.Username, Password: cfg.Password}, nil
 	}
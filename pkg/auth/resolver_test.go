@@ -29,6 +29,16 @@ func TestResolve(t *testing.T) {
 			cfg:      Config{Username: "u", Password: "p"},
 			wantMode: "basic",
 		},
+		{
+			name:     "session when requested",
+			cfg:      Config{Username: "u", Password: "p", Session: true, BaseURL: "https://jira"},
+			wantMode: "session",
+		},
+		{
+			name:     "token wins over session",
+			cfg:      Config{Token: "t", Username: "u", Password: "p", Session: true},
+			wantMode: "bearer",
+		},
 		{
 			name:        "username without password errors",
 			cfg:         Config{Username: "u"},
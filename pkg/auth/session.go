@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// sessionLoginPath is the legacy Jira Server cookie-auth endpoint.
+const sessionLoginPath = "rest/auth/1/session"
+
+// SessionAuth authenticates with a JSESSIONID cookie obtained from the legacy
+// rest/auth/1/session endpoint, for older Jira Server instances where Basic
+// Auth on the REST API is disabled. It logs in once, on the first request,
+// reuses the cookie for every later request, and logs in again when Jira
+// answers 401 because the session expired.
+type SessionAuth struct {
+	BaseURL  string
+	Username string
+	Password string
+
+	mu     sync.Mutex
+	cookie *http.Cookie
+}
+
+// Transport returns a RoundTripper that attaches the session cookie on top of
+// base. The login request itself also goes through base, so it honours the
+// same TLS and proxy settings as API calls. base is never mutated.
+func (a *SessionAuth) Transport(base http.RoundTripper) http.RoundTripper {
+	return &sessionTransport{auth: a, base: base}
+}
+
+// Validate ensures the base URL and both credentials are present.
+func (a *SessionAuth) Validate() error {
+	if a.BaseURL == "" {
+		return errors.New("session auth: base URL is required")
+	}
+	if a.Username == "" {
+		return errors.New("session auth: username is required")
+	}
+	if a.Password == "" {
+		return errors.New("session auth: password is required")
+	}
+	return nil
+}
+
+// Mode reports the stable identifier "session".
+func (a *SessionAuth) Mode() string { return ModeSession }
+
+// session returns the current cookie, logging in when there is none or when
+// the caller saw stale rejected. Comparing against stale means concurrent
+// requests that all hit 401 with the same expired cookie trigger one login,
+// not one each.
+func (a *SessionAuth) session(
+	ctx context.Context,
+	base http.RoundTripper,
+	header http.Header,
+	stale *http.Cookie,
+) (*http.Cookie, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cookie != nil && a.cookie != stale {
+		return a.cookie, nil
+	}
+	cookie, err := a.login(ctx, base, header)
+	if err != nil {
+		return nil, err
+	}
+	a.cookie = cookie
+	return cookie, nil
+}
+
+// login posts the credentials to rest/auth/1/session and returns the session
+// cookie named in the response. header carries the triggering request's
+// headers (e.g. User-Agent or gateway headers) so the login is sent the same
+// way.
+func (a *SessionAuth) login(
+	ctx context.Context,
+	base http.RoundTripper,
+	header http.Header,
+) (*http.Cookie, error) {
+	body, err := json.Marshal(map[string]string{
+		"username": a.Username,
+		"password": a.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	loginURL := strings.TrimRight(a.BaseURL, "/") + "/" + sessionLoginPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("session auth: %w", err)
+	}
+	req.Header = header.Clone()
+	req.Header.Del("Cookie")
+	req.Header.Del("Authorization")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, fmt.Errorf("session auth: login request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("session auth: login failed: %s", resp.Status)
+	}
+	var out struct {
+		Session struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"session"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("session auth: decode login response: %w", err)
+	}
+	if out.Session.Name == "" || out.Session.Value == "" {
+		return nil, errors.New("session auth: login response carried no session")
+	}
+	return &http.Cookie{Name: out.Session.Name, Value: out.Session.Value}, nil
+}
+
+// sessionTransport is the RoundTripper returned by SessionAuth.Transport.
+type sessionTransport struct {
+	auth *SessionAuth
+	base http.RoundTripper
+}
+
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cookie, err := t.auth.session(req.Context(), t.base, req.Header, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(withCookie(req, cookie))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// The session expired. Retrying needs a fresh copy of the body; without
+	// GetBody the request cannot be replayed, so return the 401 as-is.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	cookie, err = t.auth.session(req.Context(), t.base, req.Header, cookie)
+	if err != nil {
+		return nil, err
+	}
+	retry := withCookie(req, cookie)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(retry)
+}
+
+// withCookie returns a clone of req carrying the session cookie, leaving the
+// caller's request untouched as RoundTrippers must.
+func withCookie(req *http.Request, cookie *http.Cookie) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Del("Cookie")
+	r.AddCookie(cookie)
+	return r
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newSessionServer fakes the legacy session endpoint plus one API resource
+// that requires the current JSESSIONID. expire invalidates the live session.
+func newSessionServer(t *testing.T) (srv *httptest.Server, logins *atomic.Int32, expire func()) {
+	t.Helper()
+	logins = &atomic.Int32{}
+	var current atomic.Value
+	current.Store("")
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/auth/1/session":
+			var creds map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&creds)
+			if creds["username"] != "alice" || creds["password"] != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			n := logins.Add(1)
+			id := "sess-" + string(rune('0'+n))
+			current.Store(id)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"session":{"name":"JSESSIONID","value":"` + id + `"}}`))
+		default:
+			c, err := r.Cookie("JSESSIONID")
+			if err != nil || c.Value != current.Load().(string) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, logins, func() { current.Store("expired") }
+}
+
+func TestSessionAuthLoginOnceAndRelogin(t *testing.T) {
+	srv, logins, expire := newSessionServer(t)
+	a := &SessionAuth{BaseURL: srv.URL, Username: "alice", Password: "s3cret"}
+	client := &http.Client{Transport: a.Transport(http.DefaultTransport)}
+
+	post := func() int {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost,
+			srv.URL+"/rest/api/2/issue", strings.NewReader(`{"fields":{}}`))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for range 3 {
+		if got := post(); got != http.StatusOK {
+			t.Fatalf("status = %d, want 200", got)
+		}
+	}
+	if got := logins.Load(); got != 1 {
+		t.Errorf("logins = %d, want 1 (cookie reused)", got)
+	}
+
+	expire()
+	if got := post(); got != http.StatusOK {
+		t.Fatalf("status after expiry = %d, want 200 after re-login", got)
+	}
+	if got := logins.Load(); got != 2 {
+		t.Errorf("logins = %d, want 2 after expiry", got)
+	}
+}
+
+func TestSessionAuthLoginFailure(t *testing.T) {
+	srv, _, _ := newSessionServer(t)
+	a := &SessionAuth{BaseURL: srv.URL, Username: "alice", Password: "wrong"}
+	client := &http.Client{Transport: a.Transport(http.DefaultTransport)}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		srv.URL+"/rest/api/2/myself", nil)
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected login error")
+	}
+	if !strings.Contains(err.Error(), "login failed") {
+		t.Errorf("error = %v, want login failed", err)
+	}
+}
+
+func TestSessionAuthValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    *SessionAuth
+		wantErr bool
+	}{
+		{"complete", &SessionAuth{BaseURL: "https://jira", Username: "u", Password: "p"}, false},
+		{"missing base URL", &SessionAuth{Username: "u", Password: "p"}, true},
+		{"missing password", &SessionAuth{BaseURL: "https://jira", Username: "u"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.auth.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+)
+
+const testKrb5Conf = `[libdefaults]
+  default_realm = EXAMPLE.COM
+
+[realms]
+  EXAMPLE.COM = {
+    kdc = 127.0.0.1:88
+  }
+`
+
+// writeKerberosFixtures writes a krb5.conf and a keytab holding one entry for
+// ci@EXAMPLE.COM, returning their paths.
+func writeKerberosFixtures(t *testing.T) (confPath, ktPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	confPath = filepath.Join(dir, "krb5.conf")
+	if err := os.WriteFile(confPath, []byte(testKrb5Conf), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	kt := keytab.New()
+	if err := kt.AddEntry("ci", "EXAMPLE.COM", "s3cret", time.Now(), 1,
+		etypeID.AES256_CTS_HMAC_SHA1_96); err != nil {
+		t.Fatalf("add keytab entry: %v", err)
+	}
+	b, err := kt.Marshal()
+	if err != nil {
+		t.Fatalf("marshal keytab: %v", err)
+	}
+	ktPath = filepath.Join(dir, "ci.keytab")
+	if err := os.WriteFile(ktPath, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return confPath, ktPath
+}
+
+func TestResolveSPNEGO(t *testing.T) {
+	confPath, ktPath := writeKerberosFixtures(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		cfg         Config
+		wantMode    string
+		errContains string
+	}{
+		{
+			name:     "keytab wins over basic",
+			cfg:      Config{Krb5Keytab: ktPath, Krb5Principal: "ci", Krb5Config: confPath, Username: "u", Password: "p"},
+			wantMode: ModeSPNEGO,
+		},
+		{
+			name:     "token wins over keytab",
+			cfg:      Config{Token: "t", Krb5Keytab: ktPath, Krb5Principal: "ci", Krb5Config: confPath},
+			wantMode: ModeBearer,
+		},
+		{
+			name:        "keytab without principal",
+			cfg:         Config{Krb5Keytab: ktPath, Krb5Config: confPath},
+			errContains: "principal is required",
+		},
+		{
+			name:        "missing ticket cache",
+			cfg:         Config{Krb5CCache: "FILE:" + filepath.Join(dir, "krb5cc_missing"), Krb5Config: confPath},
+			errContains: "ticket cache",
+		},
+		{
+			name:        "missing krb5.conf",
+			cfg:         Config{Krb5Keytab: ktPath, Krb5Principal: "ci", Krb5Config: filepath.Join(dir, "nope.conf")},
+			errContains: "spnego: load",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Resolve(context.Background(), tt.cfg)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("error = %v, want containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := a.Mode(); got != tt.wantMode {
+				t.Errorf("Mode() = %q, want %q", got, tt.wantMode)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Package issuekey extracts Jira issue keys (e.g. GAIA-123) from free text
+// such as commit messages, PR bodies, and release refs. Every match carries
+// its source position so integrations — PR annotations, commit-msg hooks — can
+// point at exactly where a key was found.
+package issuekey
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultPattern matches an alphanumeric issue key, e.g. ABC-1234.
+var DefaultPattern = regexp.MustCompile(`([A-Z]{1,10}-[1-9][0-9]*)`)
+
+// Match is a single issue key occurrence in the source text.
+type Match struct {
+	// Key is the matched issue key.
+	Key string `json:"key"`
+	// Start and End are the byte offsets of the match, End exclusive.
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// Line and Column are the 1-based position of Start. Column counts bytes,
+	// matching the offsets.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// KeyGroup is the name of the capture group that, when a pattern has one,
+// holds the key. The rest of the match is context that must be present but
+// is not part of the key, e.g. `(?:^|\s)#(?P<key>[A-Z]+-\d+)` keeps only the
+// key of "#GAIA-1".
+const KeyGroup = "key"
+
+// Compile returns the pattern to extract keys with: DefaultPattern when
+// pattern is empty, otherwise the compiled custom expression.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return DefaultPattern, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issue pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// FindAll returns every occurrence of re in text, in source order, including
+// repeated keys. When re has a KeyGroup capture group, each Match covers
+// just that group, and matches where it did not participate are skipped.
+func FindAll(text string, re *regexp.Regexp) []Match {
+	group := re.SubexpIndex(KeyGroup)
+	var locs [][]int
+	if group < 0 {
+		locs = re.FindAllStringIndex(text, -1)
+	} else {
+		for _, sub := range re.FindAllStringSubmatchIndex(text, -1) {
+			if sub[2*group] >= 0 {
+				locs = append(locs, sub[2*group:2*group+2])
+			}
+		}
+	}
+	matches := make([]Match, 0, len(locs))
+	line, lineStart, scanned := 1, 0, 0
+	for _, loc := range locs {
+		// Advance the line counter incrementally; matches are in order, so the
+		// text is scanned once overall.
+		for {
+			i := strings.IndexByte(text[scanned:loc[0]], '\n')
+			if i < 0 {
+				break
+			}
+			line++
+			lineStart = scanned + i + 1
+			scanned = lineStart
+		}
+		scanned = loc[0]
+		matches = append(matches, Match{
+			Key:    text[loc[0]:loc[1]],
+			Start:  loc[0],
+			End:    loc[1],
+			Line:   line,
+			Column: loc[0] - lineStart + 1,
+		})
+	}
+	return matches
+}
+
+// Extract compiles pattern (see Compile) and returns every key occurrence in
+// text with its position.
+func Extract(text, pattern string) ([]Match, error) {
+	re, err := Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return FindAll(text, re), nil
+}
+
+// Keys returns the distinct keys of matches in first-seen order.
+func Keys(matches []Match) []string {
+	keys := []string{}
+	seen := make(map[string]struct{}, len(matches))
+	for _, m := range matches {
+		if _, ok := seen[m.Key]; ok {
+			continue
+		}
+		seen[m.Key] = struct{}{}
+		keys = append(keys, m.Key)
+	}
+	return keys
+}
+
+// DefaultTrailers are the Git trailer tokens read by FindTrailers when none
+// are configured, e.g. "Jira: ABC-123" or "Refs: ABC-456".
+var DefaultTrailers = []string{"Jira", "Refs", "Issue"}
+
+// FindTrailers returns the occurrences of re inside the values of trailer
+// lines ("Token: value") whose token is one of tokens, compared
+// case-insensitively; keys mentioned anywhere else, such as in prose, are
+// ignored. Trailer lines are recognised anywhere in text so the combined
+// messages of many commits can be scanned at once. Positions refer to text.
+func FindTrailers(text string, re *regexp.Regexp, tokens []string) []Match {
+	type span struct{ start, end int }
+	var values []span
+	offset := 0
+	for line := range strings.Lines(text) {
+		token, _, ok := strings.Cut(line, ":")
+		if ok && isTrailerToken(strings.TrimSpace(token), tokens) {
+			values = append(values, span{offset + len(token) + 1, offset + len(line)})
+		}
+		offset += len(line)
+	}
+	matches := []Match{}
+	for _, m := range FindAll(text, re) {
+		for _, v := range values {
+			if m.Start >= v.start && m.End <= v.end {
+				matches = append(matches, m)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func isTrailerToken(token string, tokens []string) bool {
+	if token == "" || strings.ContainsAny(token, " \t") {
+		return false
+	}
+	for _, t := range tokens {
+		if strings.EqualFold(token, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// closingKeywords are the GitHub-style verbs that mark an issue as resolved
+// by a change, e.g. "Closes ABC-1" or "fixed: ABC-2".
+var closingKeywords = map[string]bool{
+	"close": true, "closes": true, "closed": true,
+	"fix": true, "fixes": true, "fixed": true,
+	"resolve": true, "resolves": true, "resolved": true,
+}
+
+// FindClosing returns the occurrences of re directly preceded by a closing
+// keyword (close, fix, or resolve in any tense, case-insensitive, optionally
+// followed by a colon), as GitHub does for its own issues. Plain mentions are
+// left out.
+func FindClosing(text string, re *regexp.Regexp) []Match {
+	matches := []Match{}
+	for _, m := range FindAll(text, re) {
+		before := strings.TrimRightFunc(text[:m.Start], unicode.IsSpace)
+		before = strings.TrimSuffix(before, ":")
+		// The keyword starts after the last non-letter, which may be a
+		// multi-byte rune such as an em dash or a full-width space.
+		start := 0
+		if i := strings.LastIndexFunc(before, func(r rune) bool {
+			return !unicode.IsLetter(r)
+		}); i >= 0 {
+			_, size := utf8.DecodeRuneInString(before[i:])
+			start = i + size
+		}
+		if closingKeywords[strings.ToLower(before[start:])] {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// Denylist holds strings that match DefaultPattern but name standards,
+// encodings, and algorithms rather than issues. ExcludeNoise drops them.
+var Denylist = map[string]bool{
+	"UTF-8": true, "UTF-16": true, "UTF-32": true,
+	"ISO-639": true, "ISO-3166": true, "ISO-8601": true, "ISO-8859": true,
+	"ISO-9001": true, "ISO-27001": true,
+	"SHA-1": true, "SHA-224": true, "SHA-256": true, "SHA-384": true, "SHA-512": true,
+	"AES-128": true, "AES-192": true, "AES-256": true,
+	"RSA-2048": true, "RSA-3072": true, "RSA-4096": true,
+	"HTTP-2": true, "HTTP-3": true, "TLS-1": true, "SSL-3": true,
+	"X-509": true, "ECMA-262": true,
+}
+
+var (
+	urlPattern        = regexp.MustCompile(`(?i)\b[a-z][a-z0-9+.-]*://[^\s<>"'` + "`" + `)\]]+`)
+	codeSpanPattern   = regexp.MustCompile("`[^`\n]+`")
+	codeFencePrefixes = []string{"```", "~~~"}
+)
+
+// ExcludeNoise drops matches that are unlikely to be issue references: keys
+// inside URLs (except Jira "/browse/KEY" links, which are references), inside
+// Markdown inline code or fenced code blocks, and keys in Denylist.
+func ExcludeNoise(text string, matches []Match) []Match {
+	type span struct {
+		start, end int
+		url        bool
+	}
+	var ignored []span
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		ignored = append(ignored, span{loc[0], loc[1], true})
+	}
+	for _, loc := range codeSpanPattern.FindAllStringIndex(text, -1) {
+		ignored = append(ignored, span{loc[0], loc[1], false})
+	}
+	offset, fenceStart := 0, -1
+	for line := range strings.Lines(text) {
+		trimmed := strings.TrimSpace(line)
+		for _, p := range codeFencePrefixes {
+			if !strings.HasPrefix(trimmed, p) {
+				continue
+			}
+			if fenceStart < 0 {
+				fenceStart = offset
+			} else {
+				ignored = append(ignored, span{fenceStart, offset + len(line), false})
+				fenceStart = -1
+			}
+			break
+		}
+		offset += len(line)
+	}
+	// An unclosed fence runs to the end of the text, as in Markdown.
+	if fenceStart >= 0 {
+		ignored = append(ignored, span{fenceStart, len(text), false})
+	}
+
+	kept := []Match{}
+	for _, m := range matches {
+		if Denylist[m.Key] {
+			continue
+		}
+		if !slices.ContainsFunc(ignored, func(s span) bool {
+			if m.Start < s.start || m.End > s.end {
+				return false
+			}
+			return !s.url || !strings.HasSuffix(text[s.start:m.Start], "/browse/")
+		}) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
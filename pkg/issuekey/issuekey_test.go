@@ -0,0 +1,155 @@
+package issuekey
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		pattern string
+		want    []Match
+		wantErr bool
+	}{
+		{
+			name: "single line",
+			text: "Fix GAIA-12 and GAIA-7",
+			want: []Match{
+				{Key: "GAIA-12", Start: 4, End: 11, Line: 1, Column: 5},
+				{Key: "GAIA-7", Start: 16, End: 22, Line: 1, Column: 17},
+			},
+		},
+		{
+			name: "multiple lines keep repeats",
+			text: "feat: retries\n\nRefs: ABC-1\nCloses ABC-1",
+			want: []Match{
+				{Key: "ABC-1", Start: 21, End: 26, Line: 3, Column: 7},
+				{Key: "ABC-1", Start: 34, End: 39, Line: 4, Column: 8},
+			},
+		},
+		{
+			name:    "custom pattern",
+			text:    "see #42 and\n#7",
+			pattern: `#[0-9]+`,
+			want: []Match{
+				{Key: "#42", Start: 4, End: 7, Line: 1, Column: 5},
+				{Key: "#7", Start: 12, End: 14, Line: 2, Column: 1},
+			},
+		},
+		{
+			name:    "named key group",
+			text:    "Fixes [GAIA-3] and GAIA-4\n[OPS-9]",
+			pattern: `\[(?P<key>[A-Z]+-\d+)\]`,
+			want: []Match{
+				{Key: "GAIA-3", Start: 7, End: 13, Line: 1, Column: 8},
+				{Key: "OPS-9", Start: 27, End: 32, Line: 2, Column: 2},
+			},
+		},
+		{
+			name:    "optional key group that did not match",
+			text:    "see #42 and GAIA-1",
+			pattern: `#[0-9]+|(?P<key>[A-Z]+-[0-9]+)`,
+			want: []Match{
+				{Key: "GAIA-1", Start: 12, End: 18, Line: 1, Column: 13},
+			},
+		},
+		{
+			name: "no keys",
+			text: "chore: bump deps",
+			want: []Match{},
+		},
+		{
+			name:    "invalid pattern",
+			text:    "ABC-1",
+			pattern: `[`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(tt.text, tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = %+v, want %+v", got, tt.want)
+			}
+			for _, m := range got {
+				if tt.text[m.Start:m.End] != m.Key {
+					t.Errorf("offsets [%d:%d] do not point at %q", m.Start, m.End, m.Key)
+				}
+			}
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	matches := []Match{{Key: "B-2"}, {Key: "A-1"}, {Key: "B-2"}}
+	want := []string{"B-2", "A-1"}
+	if got := Keys(matches); !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	if got := Keys(nil); got == nil || len(got) != 0 {
+		t.Errorf("Keys(nil) = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestFindTrailers(t *testing.T) {
+	text := "fix: ABC-1 mentioned in prose\n\nSee the ABC-2 discussion.\n\n" +
+		"Jira: ABC-3\nrefs: ABC-4, ABC-5\nSigned-off-by: Dev <dev@example.com>\n" +
+		"Related To: ABC-6\nIssue:ABC-7"
+	got := Keys(FindTrailers(text, DefaultPattern, DefaultTrailers))
+	want := []string{"ABC-3", "ABC-4", "ABC-5", "ABC-7"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindTrailers() keys = %v, want %v", got, want)
+	}
+
+	custom := Keys(FindTrailers(text, DefaultPattern, []string{"Signed-off-by", "Jira"}))
+	if !reflect.DeepEqual(custom, []string{"ABC-3"}) {
+		t.Errorf("custom tokens keys = %v", custom)
+	}
+
+	for _, m := range FindTrailers(text, DefaultPattern, DefaultTrailers) {
+		if text[m.Start:m.End] != m.Key {
+			t.Errorf("offsets [%d:%d] do not point at %q", m.Start, m.End, m.Key)
+		}
+	}
+}
+
+func TestFindClosing(t *testing.T) {
+	text := "Closes ABC-1, fixes: ABC-2 and relates to ABC-3.\n" +
+		"RESOLVED ABC-4; prefix ABC-5\nSee hotfix ABC-6 (not a keyword)\nfix(ABC-7)"
+	got := Keys(FindClosing(text, DefaultPattern))
+	want := []string{"ABC-1", "ABC-2", "ABC-4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindClosing() keys = %v, want %v", got, want)
+	}
+
+	// The keyword may follow, or be followed by, a multi-byte separator.
+	text = "Done—Fixes ABC-1\nResolves\u3000ABC-2\n修正　closes ABC-3\nünfixes ABC-4"
+	got = Keys(FindClosing(text, DefaultPattern))
+	want = []string{"ABC-1", "ABC-2", "ABC-3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindClosing() keys = %v, want %v", got, want)
+	}
+}
+
+func TestExcludeNoise(t *testing.T) {
+	text := "Fix ABC-1 (UTF-8, SHA-256 digests, ISO-8601 dates)\n" +
+		"See https://ci.example.com/job/ABC-2/log and https://jira.example.com/browse/ABC-3\n" +
+		"Run `make ABC-4` then\n" +
+		"```\nexport KEY=ABC-5\n```\n" +
+		"Closes ABC-6 `https://jira.example.com/browse/ABC-7`\n" +
+		"~~~\nABC-8 never closed"
+	got := Keys(ExcludeNoise(text, FindAll(text, DefaultPattern)))
+	want := []string{"ABC-1", "ABC-3", "ABC-6"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExcludeNoise() keys = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package jiraops
+
+import (
+	"context"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// JiraClient is the part of the Jira API the operations use, so embedders
+// and tests can supply a fake instead of a *jira.Client against an
+// httptest server. Wrap adapts a *jira.Client.
+//
+// Batch operations group issues by client, so implementations must be
+// comparable, which pointers are.
+type JiraClient interface {
+	// GetIssue fetches the issue key.
+	GetIssue(ctx context.Context, key string, opts *jira.GetQueryOptions) (
+		*jira.Issue, *jira.Response, error)
+	// DoTransition moves an issue through a transition.
+	DoTransition(ctx context.Context, input *jira.TransitionPayloadInput) (*jira.Response, error)
+	// AddComment adds comment to the issue key.
+	AddComment(ctx context.Context, key string, comment *jira.Comment) (
+		*jira.Comment, *jira.Response, error)
+	// UpdateAssignee assigns the issue key to user.
+	UpdateAssignee(ctx context.Context, key string, user *jira.User) (*jira.Response, error)
+	// GetSelf returns the authenticated user.
+	GetSelf(ctx context.Context) (*jira.User, *jira.Response, error)
+}
+
+// IssueSearcher is implemented by a JiraClient that can run JQL searches.
+// Fetch batches its lookups through it and falls back to one GetIssue per
+// key without it.
+type IssueSearcher interface {
+	SearchIssues(ctx context.Context, jql string, opts *jira.SearchOptions) (
+		[]jira.Issue, *jira.Response, error)
+}
+
+// ResolutionLister is implemented by a JiraClient that can list the
+// resolutions; ResolutionID needs it.
+type ResolutionLister interface {
+	Resolutions(ctx context.Context) ([]jira.Resolution, *jira.Response, error)
+}
+
+// Wrap adapts client to JiraClient, IssueSearcher, and ResolutionLister. It
+// returns nil for a nil client.
+func Wrap(client *jira.Client) JiraClient {
+	if client == nil {
+		return nil
+	}
+	return goJiraClient{client}
+}
+
+// goJiraClient is a value type so that wrapping the same *jira.Client twice
+// yields equal clients, which keeps the batch grouping intact.
+type goJiraClient struct {
+	c *jira.Client
+}
+
+func (g goJiraClient) GetIssue(
+	ctx context.Context,
+	key string,
+	opts *jira.GetQueryOptions,
+) (*jira.Issue, *jira.Response, error) {
+	return g.c.Issue.GetWithContext(ctx, key, opts)
+}
+
+func (g goJiraClient) DoTransition(
+	ctx context.Context,
+	input *jira.TransitionPayloadInput,
+) (*jira.Response, error) {
+	return g.c.Issue.DoTransitionPayloadWithContext(ctx, input)
+}
+
+func (g goJiraClient) AddComment(
+	ctx context.Context,
+	key string,
+	comment *jira.Comment,
+) (*jira.Comment, *jira.Response, error) {
+	return g.c.Issue.AddCommentWithContext(ctx, key, comment)
+}
+
+func (g goJiraClient) UpdateAssignee(
+	ctx context.Context,
+	key string,
+	user *jira.User,
+) (*jira.Response, error) {
+	return g.c.Issue.UpdateAssigneeWithContext(ctx, key, user)
+}
+
+func (g goJiraClient) GetSelf(ctx context.Context) (*jira.User, *jira.Response, error) {
+	return g.c.User.GetSelfWithContext(ctx)
+}
+
+func (g goJiraClient) SearchIssues(
+	ctx context.Context,
+	jql string,
+	opts *jira.SearchOptions,
+) ([]jira.Issue, *jira.Response, error) {
+	return g.c.Issue.SearchWithContext(ctx, jql, opts)
+}
+
+func (g goJiraClient) Resolutions(ctx context.Context) ([]jira.Resolution, *jira.Response, error) {
+	return g.c.Resolution.GetListWithContext(ctx)
+}
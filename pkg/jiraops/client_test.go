@@ -0,0 +1,162 @@
+package jiraops
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// fakeClient is an in-memory JiraClient holding issues by key. It implements
+// neither IssueSearcher nor ResolutionLister.
+type fakeClient struct {
+	mu          sync.Mutex
+	issues      map[string]*jira.Issue
+	transitions []string
+	comments    []string
+	assignees   []string
+}
+
+func response(code int) *jira.Response {
+	return &jira.Response{Response: &http.Response{StatusCode: code}}
+}
+
+func (f *fakeClient) GetIssue(
+	_ context.Context,
+	key string,
+	_ *jira.GetQueryOptions,
+) (*jira.Issue, *jira.Response, error) {
+	if iss, ok := f.issues[key]; ok {
+		return iss, response(http.StatusOK), nil
+	}
+	return nil, response(http.StatusNotFound), errors.New("Issue Does Not Exist")
+}
+
+func (f *fakeClient) DoTransition(
+	_ context.Context,
+	input *jira.TransitionPayloadInput,
+) (*jira.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.transitions = append(f.transitions, input.TicketID+":"+input.TransitionID)
+	return response(http.StatusNoContent), nil
+}
+
+func (f *fakeClient) AddComment(
+	_ context.Context,
+	key string,
+	comment *jira.Comment,
+) (*jira.Comment, *jira.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.comments = append(f.comments, key+":"+comment.Body)
+	return &jira.Comment{ID: "100", Body: comment.Body}, response(http.StatusCreated), nil
+}
+
+func (f *fakeClient) UpdateAssignee(
+	_ context.Context,
+	key string,
+	user *jira.User,
+) (*jira.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.assignees = append(f.assignees, key+":"+user.Name)
+	return response(http.StatusNoContent), nil
+}
+
+func (f *fakeClient) GetSelf(context.Context) (*jira.User, *jira.Response, error) {
+	return &jira.User{Name: "bot"}, response(http.StatusOK), nil
+}
+
+func TestProcessWithFakeClient(t *testing.T) {
+	fake := &fakeClient{issues: map[string]*jira.Issue{
+		"GAIA-1": {Key: "GAIA-1", Transitions: []jira.Transition{
+			{ID: "31", Name: "Done", To: jira.Status{Name: "Done"}},
+		}},
+	}}
+	p := New(fake, Options{})
+	res, err := p.Process(context.Background(), "GAIA-1 and GAIA-2", Actions{
+		Transition: "Done",
+		Assignee:   "alice",
+		Comment:    "deployed",
+	})
+	if !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("err = %v, want the GAIA-2 fetch to fail with ErrIssueNotFound", err)
+	}
+	if len(res.Issues) != 1 || res.Issues[0].Key != "GAIA-1" {
+		t.Fatalf("issues = %+v", res.Issues)
+	}
+	if len(fake.transitions) != 1 || fake.transitions[0] != "GAIA-1:31" {
+		t.Errorf("transitions = %v", fake.transitions)
+	}
+	if len(fake.assignees) != 1 || fake.assignees[0] != "GAIA-1:alice" {
+		t.Errorf("assignees = %v", fake.assignees)
+	}
+	if len(fake.comments) != 1 || fake.comments[0] != "GAIA-1:deployed" {
+		t.Errorf("comments = %v", fake.comments)
+	}
+}
+
+func TestOptionalInterfacesUnsupported(t *testing.T) {
+	p := New(&fakeClient{}, Options{})
+	if _, err := p.ResolutionID(context.Background(), "Fixed"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ResolutionID err = %v, want ErrUnsupported", err)
+	}
+	if _, err := p.SearchKeys(context.Background(), []string{"GAIA-1"}); !errors.Is(
+		err, ErrUnsupported) {
+		t.Errorf("SearchKeys err = %v, want ErrUnsupported", err)
+	}
+	user, err := p.Self(context.Background())
+	if err != nil || user.Name != "bot" {
+		t.Errorf("Self = %+v, %v", user, err)
+	}
+}
+
+// nilResponseClient answers every issue call with neither a response nor an
+// error, as an incomplete fake might.
+type nilResponseClient struct{ fakeClient }
+
+func (*nilResponseClient) GetIssue(
+	context.Context, string, *jira.GetQueryOptions,
+) (*jira.Issue, *jira.Response, error) {
+	return nil, nil, nil
+}
+
+func (*nilResponseClient) DoTransition(
+	context.Context, *jira.TransitionPayloadInput,
+) (*jira.Response, error) {
+	return nil, nil
+}
+
+func (*nilResponseClient) AddComment(
+	context.Context, string, *jira.Comment,
+) (*jira.Comment, *jira.Response, error) {
+	return nil, nil, nil
+}
+
+func (*nilResponseClient) UpdateAssignee(
+	context.Context, string, *jira.User,
+) (*jira.Response, error) {
+	return nil, nil
+}
+
+func TestNilResponseFails(t *testing.T) {
+	p := New(&nilResponseClient{}, Options{})
+	ctx := context.Background()
+	iss := &jira.Issue{Key: "GAIA-1", Transitions: []jira.Transition{{ID: "31", Name: "Done"}}}
+
+	_, fetch := p.FetchIssue(ctx, "GAIA-1")
+	for _, out := range []Outcome{
+		fetch,
+		p.TransitionIssue(ctx, iss, "Done", ""),
+		p.AssignIssue(ctx, iss, "alice"),
+		p.CommentIssue(ctx, iss, "deployed", ""),
+	} {
+		if out.Status != StatusFailed || !errors.Is(out.Err, ErrNoResponse) {
+			t.Errorf("%s: outcome = %+v, want a failure with ErrNoResponse", out.Action, out)
+		}
+	}
+}
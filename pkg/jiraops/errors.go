@@ -0,0 +1,135 @@
+package jiraops
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// Sentinel errors for callers that branch on an Outcome or a returned error
+// with errors.Is instead of matching messages.
+var (
+	// ErrIssueNotFound reports that Jira answered 404 for an issue.
+	ErrIssueNotFound = errors.New("issue not found")
+	// ErrTransitionNotFound reports that the requested transition is not
+	// available from the issue's current status.
+	ErrTransitionNotFound = errors.New("transition not found")
+	// ErrUnauthorized reports that Jira rejected the credentials (401) or the
+	// account's permission (403).
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrReadOnly reports that Jira refused a change because the issue or its
+	// project is archived or not editable in its status.
+	ErrReadOnly = errors.New("issue is read-only")
+	// ErrNotAttempted reports an issue skipped by FailFast after an earlier
+	// failure in the same batch.
+	ErrNotAttempted = errors.New("not attempted after an earlier failure")
+	// ErrUnsupported reports that the JiraClient does not implement the
+	// optional interface an operation needs, such as IssueSearcher.
+	ErrUnsupported = errors.New("not supported by the client")
+	// ErrSkip is returned by a Hooks.BeforeTransition hook to skip the
+	// transition rather than fail it.
+	ErrSkip = errors.New("skipped by hook")
+	// ErrNoResponse reports a JiraClient call that returned neither a
+	// response nor an error, e.g. from an incomplete fake.
+	ErrNoResponse = errors.New("no response from the client")
+)
+
+// sentinelError keeps an established message while matching a sentinel under
+// errors.Is; the cause, when set, stays reachable through Unwrap.
+type sentinelError struct {
+	sentinel error
+	msg      string
+	cause    error
+}
+
+func (e *sentinelError) Error() string        { return e.msg }
+func (e *sentinelError) Is(target error) bool { return target == e.sentinel } //nolint:errorlint // identity check
+func (e *sentinelError) Unwrap() error        { return e.cause }
+
+// APIError wraps the error of an issue-scoped API call with ErrIssueNotFound
+// on 404 and ErrUnauthorized on 401/403, keeping its message. Other errors
+// are returned unchanged.
+func APIError(resp *jira.Response, err error) error {
+	if err == nil || resp == nil {
+		return err
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return &sentinelError{sentinel: ErrIssueNotFound, msg: err.Error(), cause: err}
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &sentinelError{sentinel: ErrUnauthorized, msg: err.Error(), cause: err}
+	}
+	return err
+}
+
+// TransitionNotFoundError is the ErrTransitionNotFound error for transition,
+// naming the transitions that are available from the issue's current status
+// so the fix is visible without a second lookup.
+func TransitionNotFoundError(transition string, available []jira.Transition) error {
+	names := make([]string, 0, len(available))
+	for _, t := range available {
+		names = append(names, t.Name)
+	}
+	msg := fmt.Sprintf("transition %q not found; no transitions available", transition)
+	if len(names) > 0 {
+		msg = fmt.Sprintf("transition %q not found; available: %s", transition, strings.Join(names, ", "))
+	}
+	return &sentinelError{sentinel: ErrTransitionNotFound, msg: msg}
+}
+
+// readOnlyMarkers are the lowercase fragments Jira puts in errorMessages /
+// errors when a write is refused because the issue (or its project) can no
+// longer be edited: archived issues and projects on Data Center and Cloud, and
+// workflow statuses carrying jira.issue.editable=false. Release refs often
+// mention ancient tickets in exactly that state, so these are matched to skip
+// rather than fail.
+var readOnlyMarkers = []string{
+	"archived",
+	"read-only",
+	"read only",
+	"not editable",
+	"cannot be edited",
+	"can't be edited",
+}
+
+// IsReadOnly reports whether err is a Jira refusal caused by the issue being
+// archived or read-only. Only client-error statuses are considered (400, 403,
+// 404, 409), so an outage or rate limit is never mistaken for one.
+func IsReadOnly(resp *jira.Response, err error) bool {
+	if err == nil {
+		return false
+	}
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusBadRequest, http.StatusForbidden,
+			http.StatusNotFound, http.StatusConflict:
+		default:
+			return false
+		}
+	}
+	var jerr *jira.Error
+	if !errors.As(err, &jerr) {
+		return false
+	}
+	msgs := append([]string{}, jerr.ErrorMessages...)
+	for _, v := range jerr.Errors {
+		msgs = append(msgs, v)
+	}
+	for _, m := range msgs {
+		m = strings.ToLower(m)
+		for _, marker := range readOnlyMarkers {
+			if strings.Contains(m, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readOnlyError is the ErrReadOnly error for a refused write.
+func readOnlyError(err error) error {
+	return &sentinelError{sentinel: ErrReadOnly, msg: "read-only: " + err.Error(), cause: err}
+}
@@ -0,0 +1,67 @@
+package jiraops
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestIsReadOnly(t *testing.T) {
+	resp := func(code int) *jira.Response {
+		return &jira.Response{Response: &http.Response{StatusCode: code}}
+	}
+	tests := []struct {
+		name string
+		resp *jira.Response
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error",
+			resp: resp(http.StatusForbidden),
+			want: false,
+		},
+		{
+			name: "archived issue message",
+			resp: resp(http.StatusForbidden),
+			err:  &jira.Error{ErrorMessages: []string{"The issue is Archived."}},
+			want: true,
+		},
+		{
+			name: "non-editable status in field errors",
+			resp: resp(http.StatusBadRequest),
+			err: &jira.Error{
+				Errors: map[string]string{"summary": "Issue is not editable in this status"},
+			},
+			want: true,
+		},
+		{
+			name: "unrelated validation error",
+			resp: resp(http.StatusBadRequest),
+			err:  &jira.Error{ErrorMessages: []string{"Field 'foo' is required"}},
+			want: false,
+		},
+		{
+			name: "server error mentioning archived is not read-only",
+			resp: resp(http.StatusInternalServerError),
+			err:  &jira.Error{ErrorMessages: []string{"archived storage unavailable"}},
+			want: false,
+		},
+		{
+			name: "plain error",
+			resp: resp(http.StatusForbidden),
+			err:  errors.New("archived"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsReadOnly(tt.resp, tt.err); got != tt.want {
+				t.Errorf("IsReadOnly() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
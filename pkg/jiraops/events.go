@@ -0,0 +1,58 @@
+package jiraops
+
+import (
+	"context"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// EventType says what an Event reports.
+type EventType string
+
+// Event types, one per settled single-issue operation.
+const (
+	EventIssueFetched      EventType = "issue_fetched"
+	EventTransitionApplied EventType = "transition_applied"
+	EventAssigneeUpdated   EventType = "assignee_updated"
+	EventCommentAdded      EventType = "comment_added"
+	EventOperationSkipped  EventType = "operation_skipped"
+	EventOperationFailed   EventType = "operation_failed"
+)
+
+// Event is live progress of a Processor, for UIs and bots that stream status
+// instead of waiting for the outcomes.
+type Event struct {
+	Type EventType
+	// Issue is the issue fetched or acted on; nil when its fetch failed.
+	Issue *jira.Issue
+	// Outcome is the settled outcome, with its key, action, and error.
+	Outcome Outcome
+}
+
+// eventTypes maps the action of a successful outcome to its event.
+var eventTypes = map[string]EventType{
+	ActionFetch:      EventIssueFetched,
+	ActionTransition: EventTransitionApplied,
+	ActionAssign:     EventAssigneeUpdated,
+	ActionComment:    EventCommentAdded,
+}
+
+// settled reports a settled outcome: the OnEvent callback gets its event and,
+// when it failed, the OnError hook runs. The single-issue operations defer it
+// so every return path reaches it.
+func (p *Processor) settled(ctx context.Context, iss *jira.Issue, out *Outcome) {
+	if out.Status == StatusFailed && p.opts.Hooks.OnError != nil {
+		p.opts.Hooks.OnError(ctx, *out)
+	}
+	if p.opts.OnEvent == nil {
+		return
+	}
+	ev := Event{Type: eventTypes[out.Action], Issue: iss, Outcome: *out}
+	switch out.Status {
+	case StatusSkipped:
+		ev.Type = EventOperationSkipped
+	case StatusFailed:
+		ev.Type = EventOperationFailed
+	}
+	p.opts.OnEvent(ev)
+}
@@ -0,0 +1,49 @@
+package jiraops
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestOnEvent(t *testing.T) {
+	fake := &fakeClient{issues: map[string]*jira.Issue{
+		"GAIA-1": {Key: "GAIA-1", Transitions: []jira.Transition{
+			{ID: "31", Name: "Done", To: jira.Status{Name: "Done"}},
+		}},
+		"GAIA-3": {Key: "GAIA-3"},
+	}}
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	p := New(fake, Options{OnEvent: func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ev.Issue != nil && ev.Issue.Key != ev.Outcome.Key {
+			t.Errorf("event %s: issue %s, outcome %s", ev.Type, ev.Issue.Key, ev.Outcome.Key)
+		}
+		events = append(events, ev.Outcome.Key+" "+string(ev.Type))
+	}})
+	_, _ = p.Process(context.Background(), "GAIA-1 GAIA-2 GAIA-3", Actions{
+		Transition: "Done",
+		Comment:    "deployed",
+	})
+
+	slices.Sort(events)
+	want := []string{
+		"GAIA-1 comment_added",
+		"GAIA-1 issue_fetched",
+		"GAIA-1 transition_applied",
+		"GAIA-2 operation_failed",
+		"GAIA-3 comment_added",
+		"GAIA-3 issue_fetched",
+		"GAIA-3 operation_skipped",
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
@@ -0,0 +1,49 @@
+package jiraops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// Hooks are optional callbacks around the single-issue operations, for the
+// validation, metrics, or notifications an embedder needs without wrapping
+// every call. Nil hooks are skipped. The batch operations and Process reach
+// them through the single-issue operations, so a hook may run on several
+// goroutines at once.
+type Hooks struct {
+	// BeforeTransition runs before iss is moved through transition, once the
+	// transition is known to be available. Returning ErrSkip, or an error
+	// wrapping it, skips the transition; any other error fails it.
+	BeforeTransition func(ctx context.Context, iss *jira.Issue, transition string) error
+	// AfterComment runs after a comment was added to iss; out carries the
+	// comment's ID.
+	AfterComment func(ctx context.Context, iss *jira.Issue, out Outcome)
+	// OnError runs for every failed outcome, whatever its action.
+	OnError func(ctx context.Context, out Outcome)
+}
+
+// beforeTransition runs the BeforeTransition hook and reports whether the
+// transition may go ahead, settling out when it may not.
+func (p *Processor) beforeTransition(
+	ctx context.Context,
+	iss *jira.Issue,
+	transition string,
+	out *Outcome,
+) bool {
+	if p.opts.Hooks.BeforeTransition == nil {
+		return true
+	}
+	err := p.opts.Hooks.BeforeTransition(ctx, iss, transition)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrSkip):
+		out.Status, out.Err = StatusSkipped, err
+	default:
+		out.Status, out.Err = StatusFailed, fmt.Errorf("before transition hook: %w", err)
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package jiraops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestHooks(t *testing.T) {
+	done := []jira.Transition{{ID: "31", Name: "Done", To: jira.Status{Name: "Done"}}}
+	fake := &fakeClient{issues: map[string]*jira.Issue{
+		"GAIA-1": {Key: "GAIA-1", Transitions: done},
+		"GAIA-2": {Key: "GAIA-2", Transitions: done},
+		"GAIA-3": {Key: "GAIA-3", Transitions: done},
+	}}
+	var (
+		mu        sync.Mutex
+		commented []string
+		failed    []string
+	)
+	p := New(fake, Options{Hooks: Hooks{
+		BeforeTransition: func(_ context.Context, iss *jira.Issue, transition string) error {
+			switch iss.Key {
+			case "GAIA-2":
+				return fmt.Errorf("frozen: %w", ErrSkip)
+			case "GAIA-3":
+				return errors.New("release not approved")
+			}
+			return nil
+		},
+		AfterComment: func(_ context.Context, iss *jira.Issue, out Outcome) {
+			mu.Lock()
+			defer mu.Unlock()
+			commented = append(commented, iss.Key+":"+out.CommentID)
+		},
+		OnError: func(_ context.Context, out Outcome) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, out.Key+" "+out.Action)
+		},
+	}})
+
+	res, err := p.Process(context.Background(), "GAIA-1 GAIA-2 GAIA-3 GAIA-4", Actions{
+		Transition: "Done",
+		Comment:    "deployed",
+	})
+	if err == nil {
+		t.Fatal("expected the GAIA-3 transition and GAIA-4 fetch to fail")
+	}
+	if len(fake.transitions) != 1 || fake.transitions[0] != "GAIA-1:31" {
+		t.Errorf("transitions = %v, want only GAIA-1", fake.transitions)
+	}
+	for _, o := range res.Outcomes {
+		if o.Action != ActionTransition {
+			continue
+		}
+		switch o.Key {
+		case "GAIA-2":
+			if o.Status != StatusSkipped || !errors.Is(o.Err, ErrSkip) {
+				t.Errorf("GAIA-2 transition = %+v, want skipped", o)
+			}
+		case "GAIA-3":
+			want := "before transition hook: release not approved"
+			if o.Status != StatusFailed || o.Reason() != want {
+				t.Errorf("GAIA-3 transition = %+v, want failed by the hook", o)
+			}
+		}
+	}
+	if len(commented) != 3 {
+		t.Errorf("AfterComment ran for %v, want all three fetched issues", commented)
+	}
+	if len(failed) != 2 {
+		t.Errorf("OnError ran for %v, want GAIA-4 fetch and GAIA-3 transition", failed)
+	}
+}
@@ -0,0 +1,160 @@
+// Package jiraops is the issue workflow behind `go-jira run` as an importable
+// library: extract issue keys from free text, fetch the issues, and
+// transition, assign, and comment on them concurrently. Every action on every
+// issue yields an Outcome, so embedders get structured results rather than
+// log lines.
+//
+//	p := jiraops.New(jiraops.Wrap(client), jiraops.Options{Concurrency: 4})
+//	res, err := p.Process(ctx, commitMessage, jiraops.Actions{Transition: "Done"})
+package jiraops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// DefaultIssueTimeout bounds each issue's work when Options.IssueTimeout is
+// unset.
+const DefaultIssueTimeout = time.Minute
+
+// maxDrainBytes bounds how much of an unread response body is discarded to
+// keep the connection reusable.
+const maxDrainBytes = 1 << 20
+
+// Options configures a Processor. The zero value is usable: one client,
+// unlimited concurrency, DefaultIssueTimeout, and no fail-fast.
+type Options struct {
+	// ClientFor picks the client for an issue key, e.g. a per-project service
+	// account. A nil func, or a nil result, uses the Processor's client.
+	ClientFor func(key string) JiraClient
+	// Concurrency caps the Jira requests in flight across a batch operation;
+	// 0 means unlimited.
+	Concurrency int
+	// IssueTimeout is the deadline for each issue's request in a batch
+	// operation, so one hung request fails on its own.
+	IssueTimeout time.Duration
+	// FailFast stops a batch operation from starting further issues after its
+	// first failure; those are reported skipped with ErrNotAttempted.
+	FailFast bool
+	// Hooks are called around the single-issue operations.
+	Hooks Hooks
+	// OnEvent, when set, receives an Event as each single-issue operation
+	// settles, including issues a batch fetch found by search. It is called
+	// on the goroutine that did the work, so it must be safe for concurrent
+	// use and should return quickly.
+	OnEvent func(Event)
+}
+
+// Processor runs the issue workflow against Jira. It is safe for concurrent
+// use.
+type Processor struct {
+	client JiraClient
+	opts   Options
+	sem    chan struct{}
+}
+
+// New returns a Processor that talks to Jira through client; see Wrap for a
+// *jira.Client.
+func New(client JiraClient, opts Options) *Processor {
+	p := &Processor{client: client, opts: opts}
+	if p.opts.IssueTimeout <= 0 {
+		p.opts.IssueTimeout = DefaultIssueTimeout
+	}
+	if opts.Concurrency > 0 {
+		p.sem = make(chan struct{}, opts.Concurrency)
+	}
+	return p
+}
+
+// clientOf returns the client to use for the issue key.
+func (p *Processor) clientOf(key string) JiraClient {
+	if p.opts.ClientFor != nil {
+		if c := p.opts.ClientFor(key); c != nil {
+			return c
+		}
+	}
+	return p.client
+}
+
+// acquire blocks until the concurrency cap has room and returns the matching
+// release func.
+func (p *Processor) acquire() func() {
+	if p.sem == nil {
+		return func() {}
+	}
+	p.sem <- struct{}{}
+	return func() { <-p.sem }
+}
+
+// forEach runs fn for every issue in parallel within the concurrency cap,
+// each on a context bounded by the issue timeout, and returns the outcomes in
+// issue order with the failures joined (see failures). Under FailFast the
+// first failure cancels the work still running and skips the issues not yet
+// started.
+func (p *Processor) forEach(
+	ctx context.Context,
+	issues []*jira.Issue,
+	action string,
+	fn func(context.Context, *jira.Issue) Outcome,
+) ([]Outcome, error) {
+	failCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workCtx := ctx
+	if p.opts.FailFast {
+		workCtx = failCtx
+	}
+
+	// Each worker owns one slot, so the outcomes need no lock.
+	outcomes := make([]Outcome, len(issues))
+	var wg sync.WaitGroup
+	for i, iss := range issues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := p.acquire()
+			defer release()
+			if p.opts.FailFast && failCtx.Err() != nil {
+				outcomes[i] = Outcome{
+					Key: iss.Key, Action: action, Status: StatusSkipped, Err: ErrNotAttempted,
+				}
+				return
+			}
+			issueCtx, cancelIssue := context.WithTimeout(workCtx, p.opts.IssueTimeout)
+			defer cancelIssue()
+			outcomes[i] = fn(issueCtx, iss)
+			if outcomes[i].Status == StatusFailed {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return outcomes, failures(outcomes)
+}
+
+// failures joins an *IssueError per failed outcome, in order, into one error
+// that counts them, or returns nil when none failed.
+func failures(outcomes []Outcome) error {
+	var errs []error
+	for _, o := range outcomes {
+		if o.Status == StatusFailed {
+			errs = append(errs, &IssueError{Key: o.Key, Action: o.Action, Err: o.Err})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d issues failed: %w", len(errs), len(outcomes), errors.Join(errs...))
+}
+
+// drainBody discards what is left of a response body, up to maxDrainBytes,
+// before closing it, so the connection goes back to the idle pool.
+func drainBody(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, maxDrainBytes))
+	_ = body.Close()
+}
@@ -0,0 +1,126 @@
+package jiraops
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// newServer serves GAIA-1 (with a Done transition) through search, answers
+// 404 for every other issue, and accepts transitions and comments on GAIA-1.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/2/search":
+			_, _ = w.Write([]byte(`{"issues":[{"key":"GAIA-1","fields":{"summary":"First"},
+				"transitions":[{"id":"31","name":"Done","to":{"name":"Done"}}]}]}`))
+		case "GET /rest/api/2/resolution":
+			_, _ = w.Write([]byte(`[{"id":"10000","name":"Fixed"}]`))
+		case "POST /rest/api/2/issue/GAIA-1/transitions":
+			w.WriteHeader(http.StatusNoContent)
+		case "POST /rest/api/2/issue/GAIA-1/comment":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"100","body":"deployed"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages":["Issue Does Not Exist"]}`))
+		}
+	}))
+}
+
+func TestProcess(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(Wrap(client), Options{Concurrency: 2})
+	res, err := p.Process(context.Background(), "GAIA-1 and GAIA-2", Actions{
+		Transition: "done",
+		Resolution: "Fixed",
+		Comment:    "deployed",
+	})
+	if !errors.Is(err, ErrIssueNotFound) {
+		t.Fatalf("err = %v, want the GAIA-2 fetch to fail with ErrIssueNotFound", err)
+	}
+	if len(res.Keys) != 2 || len(res.Issues) != 1 || res.Issues[0].Key != "GAIA-1" {
+		t.Fatalf("result = %+v", res)
+	}
+
+	want := []Outcome{
+		{Key: "GAIA-1", Action: ActionFetch, Status: StatusOK},
+		{Key: "GAIA-2", Action: ActionFetch, Status: StatusFailed},
+		{Key: "GAIA-1", Action: ActionTransition, Status: StatusOK, NewStatus: "Done"},
+		{Key: "GAIA-1", Action: ActionComment, Status: StatusOK, CommentID: "100"},
+	}
+	if len(res.Outcomes) != len(want) {
+		t.Fatalf("outcomes = %+v", res.Outcomes)
+	}
+	for i, w := range want {
+		got := res.Outcomes[i]
+		if got.Key != w.Key || got.Action != w.Action || got.Status != w.Status ||
+			got.NewStatus != w.NewStatus || got.CommentID != w.CommentID {
+			t.Errorf("outcome %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestTransitionIssueNotFound(t *testing.T) {
+	p := New(nil, Options{})
+	out := p.TransitionIssue(context.Background(), &jira.Issue{
+		Key:         "GAIA-1",
+		Transitions: []jira.Transition{{Name: "Start Progress"}},
+	}, "Done", "")
+	if out.Status != StatusSkipped || !errors.Is(out.Err, ErrTransitionNotFound) ||
+		out.Reason() != `transition "Done" not found; available: Start Progress` {
+		t.Errorf("outcome = %+v", out)
+	}
+}
+
+func TestFailFastSkipsRemainingIssues(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Neither issue has a comment endpoint. With one slot the issues run in
+	// turn, so whichever goes first fails and the other is never attempted.
+	issues := []*jira.Issue{{Key: "GAIA-2"}, {Key: "GAIA-3"}}
+	p := New(Wrap(client), Options{Concurrency: 1, FailFast: true})
+	outcomes, err := p.Comment(context.Background(), issues, "deployed", "")
+	var ie *IssueError
+	if !errors.As(err, &ie) || ie.Action != ActionComment {
+		t.Fatalf("err = %v", err)
+	}
+	failed, skipped := 0, 0
+	for _, o := range outcomes {
+		switch {
+		case o.Status == StatusFailed:
+			failed++
+		case errors.Is(o.Err, ErrNotAttempted):
+			skipped++
+		}
+	}
+	if failed != 1 || skipped != 1 {
+		t.Errorf("outcomes = %+v", outcomes)
+	}
+}
+
+func TestExtractTrailerKeys(t *testing.T) {
+	keys, err := ExtractTrailerKeys("Fix GAIA-1 in passing\n\nJira: GAIA-2\n", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "GAIA-2" {
+		t.Errorf("keys = %v, want [GAIA-2]", keys)
+	}
+}
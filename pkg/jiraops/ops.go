@@ -0,0 +1,336 @@
+package jiraops
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/appleboy/com/convert"
+)
+
+// SearchBatchSize is how many keys one batch search asks for; it matches the
+// default search page size so each batch is answered in a single response.
+const SearchBatchSize = 50
+
+// FetchFields are the only fields fetched for an issue: the summary and
+// status for logs and reports, and the type for filtering. Asking for just
+// these keeps issues with megabytes of description, comments, or attachments
+// from bloating memory and latency.
+var FetchFields = []string{"summary", "status", "issuetype"}
+
+// settle fills out from the result of its Jira call and reports whether it
+// succeeded: skipped for a read-only issue, failed for an error, a missing
+// response, or a status other than want.
+func settle(out *Outcome, start time.Time, resp *jira.Response, err error, want int) bool {
+	out.Duration = time.Since(start)
+	switch {
+	case IsReadOnly(resp, err):
+		out.Status, out.Err = StatusSkipped, readOnlyError(err)
+	case err != nil:
+		out.Status, out.Err = StatusFailed, APIError(resp, err)
+	case resp == nil:
+		out.Status, out.Err = StatusFailed, ErrNoResponse
+	case resp.StatusCode != want:
+		out.Status, out.Err = StatusFailed, fmt.Errorf("unexpected status: %s", resp.Status)
+	default:
+		out.Status = StatusOK
+	}
+	return out.Status == StatusOK
+}
+
+// FetchIssue fetches one issue with FetchFields and its transitions. The
+// issue is nil unless the outcome is StatusOK.
+func (p *Processor) FetchIssue(ctx context.Context, key string) (issue *jira.Issue, out Outcome) {
+	defer func() { p.settled(ctx, issue, &out) }()
+	out = Outcome{Key: key, Action: ActionFetch}
+	start := time.Now()
+	fetched, resp, err := p.clientOf(key).GetIssue(ctx, key, &jira.GetQueryOptions{
+		Fields: strings.Join(FetchFields, ","),
+		Expand: "transitions",
+	})
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if !settle(&out, start, resp, err, http.StatusOK) {
+		return nil, out
+	}
+	return fetched, out
+}
+
+// SearchKeys fetches keys with a single `key in (...)` search on the
+// Processor's client, asking for FetchFields and the transitions like
+// FetchIssue. Unknown keys are left out of the result rather than failing
+// the search. It fails with ErrUnsupported when the client is not an
+// IssueSearcher.
+func (p *Processor) SearchKeys(ctx context.Context, keys []string) ([]jira.Issue, error) {
+	return searchKeys(ctx, p.client, keys)
+}
+
+func searchKeys(ctx context.Context, client JiraClient, keys []string) ([]jira.Issue, error) {
+	searcher, ok := client.(IssueSearcher)
+	if !ok {
+		return nil, fmt.Errorf("search: %w", ErrUnsupported)
+	}
+	jql := fmt.Sprintf("key in (%s)", strings.Join(keys, ", "))
+	issues, resp, err := searcher.SearchIssues(ctx, jql, &jira.SearchOptions{
+		MaxResults:    len(keys),
+		Expand:        "transitions",
+		Fields:        FetchFields,
+		ValidateQuery: "warn",
+	})
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	return issues, err
+}
+
+// Fetch fetches the issues for keys: in batch searches of SearchBatchSize
+// keys per client, then one by one for the keys a search did not return, so
+// a missing or archived issue gets its own outcome. Clients that are not an
+// IssueSearcher fetch every key one by one. Issues and outcomes are
+// in key order.
+func (p *Processor) Fetch(ctx context.Context, keys []string) ([]*jira.Issue, []Outcome, error) {
+	found := p.batchSearch(ctx, keys)
+	fetched := make([]*jira.Issue, len(keys))
+	outcomes := make([]Outcome, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		if iss, ok := found[key]; ok {
+			fetched[i] = iss
+			outcomes[i] = Outcome{Key: key, Action: ActionFetch, Status: StatusOK}
+			p.settled(ctx, iss, &outcomes[i])
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := p.acquire()
+			defer release()
+			ctx, cancel := context.WithTimeout(ctx, p.opts.IssueTimeout)
+			defer cancel()
+			fetched[i], outcomes[i] = p.FetchIssue(ctx, key)
+		}()
+	}
+	wg.Wait()
+
+	issues := make([]*jira.Issue, 0, len(keys))
+	for _, iss := range fetched {
+		if iss != nil {
+			issues = append(issues, iss)
+		}
+	}
+	return issues, outcomes, failures(outcomes)
+}
+
+// batchSearch runs SearchKeys per SearchBatchSize keys, grouped by the client
+// of each key. A failed search leaves its keys out of the result.
+func (p *Processor) batchSearch(ctx context.Context, keys []string) map[string]*jira.Issue {
+	groups := map[JiraClient][]string{}
+	for _, k := range keys {
+		c := p.clientOf(k)
+		if _, ok := c.(IssueSearcher); ok {
+			groups[c] = append(groups[c], k)
+		}
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found = make(map[string]*jira.Issue, len(keys))
+	)
+	for client, group := range groups {
+		for chunk := range slices.Chunk(group, SearchBatchSize) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release := p.acquire()
+				defer release()
+				ctx, cancel := context.WithTimeout(ctx, p.opts.IssueTimeout)
+				defer cancel()
+				issues, err := searchKeys(ctx, client, chunk)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for i := range issues {
+					found[issues[i].Key] = &issues[i]
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return found
+}
+
+// TransitionIssue moves iss through the transition named transition,
+// matched case-insensitively against the transitions fetched with the issue,
+// and sets the resolution when resolutionID is set. A transition that is not
+// available is skipped with ErrTransitionNotFound; one that is runs past the
+// BeforeTransition hook first.
+func (p *Processor) TransitionIssue(
+	ctx context.Context,
+	iss *jira.Issue,
+	transition, resolutionID string,
+) (out Outcome) {
+	defer p.settled(ctx, iss, &out)
+	out = Outcome{Key: iss.Key, Action: ActionTransition}
+	for _, t := range iss.Transitions {
+		if !strings.EqualFold(t.Name, transition) {
+			continue
+		}
+		if !p.beforeTransition(ctx, iss, t.Name, &out) {
+			return out
+		}
+		input := &jira.TransitionPayloadInput{
+			TicketID:     iss.Key,
+			TransitionID: t.ID,
+		}
+		if resolutionID != "" {
+			input.ResolutionID = convert.ToPtr(resolutionID)
+		}
+		start := time.Now()
+		resp, err := p.clientOf(iss.Key).DoTransition(ctx, input)
+		if resp != nil && resp.Body != nil {
+			defer drainBody(resp.Body)
+		}
+		if settle(&out, start, resp, err, http.StatusNoContent) {
+			out.NewStatus = t.To.Name
+		}
+		// Only the first match is tried, so a second transition with the same
+		// name is never attempted against the already-moved issue.
+		return out
+	}
+	out.Status = StatusSkipped
+	out.Err = TransitionNotFoundError(transition, iss.Transitions)
+	return out
+}
+
+// AssignIssue assigns iss to the user with the login name assignee.
+func (p *Processor) AssignIssue(
+	ctx context.Context,
+	iss *jira.Issue,
+	assignee string,
+) (out Outcome) {
+	defer p.settled(ctx, iss, &out)
+	out = Outcome{Key: iss.Key, Action: ActionAssign}
+	start := time.Now()
+	resp, err := p.clientOf(iss.Key).UpdateAssignee(ctx, iss.Key, &jira.User{Name: assignee})
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	settle(&out, start, resp, err, http.StatusNoContent)
+	return out
+}
+
+// CommentIssue adds a comment with body, in Jira wiki markup, to iss. author
+// fills the comment's legacy name field and may be empty.
+func (p *Processor) CommentIssue(
+	ctx context.Context,
+	iss *jira.Issue,
+	body, author string,
+) (out Outcome) {
+	defer p.settled(ctx, iss, &out)
+	out = Outcome{Key: iss.Key, Action: ActionComment}
+	start := time.Now()
+	item, resp, err := p.clientOf(iss.Key).AddComment(ctx, iss.Key, &jira.Comment{
+		Name: author,
+		Body: body,
+	})
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err == nil && resp != nil && resp.StatusCode != http.StatusCreated {
+		// The body says why, e.g. a comment visibility restriction.
+		data, _ := io.ReadAll(resp.Body)
+		out.Duration = time.Since(start)
+		out.Status = StatusFailed
+		out.Err = fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(data))
+		return out
+	}
+	if settle(&out, start, resp, err, http.StatusCreated) {
+		out.CommentID = item.ID
+		if p.opts.Hooks.AfterComment != nil {
+			p.opts.Hooks.AfterComment(ctx, iss, out)
+		}
+	}
+	return out
+}
+
+// Transition runs TransitionIssue for every issue concurrently.
+func (p *Processor) Transition(
+	ctx context.Context,
+	issues []*jira.Issue,
+	transition, resolutionID string,
+) ([]Outcome, error) {
+	fn := func(ctx context.Context, iss *jira.Issue) Outcome {
+		return p.TransitionIssue(ctx, iss, transition, resolutionID)
+	}
+	return p.forEach(ctx, issues, ActionTransition, fn)
+}
+
+// Assign runs AssignIssue for every issue concurrently.
+func (p *Processor) Assign(
+	ctx context.Context,
+	issues []*jira.Issue,
+	assignee string,
+) ([]Outcome, error) {
+	fn := func(ctx context.Context, iss *jira.Issue) Outcome {
+		return p.AssignIssue(ctx, iss, assignee)
+	}
+	return p.forEach(ctx, issues, ActionAssign, fn)
+}
+
+// Comment runs CommentIssue for every issue concurrently.
+func (p *Processor) Comment(
+	ctx context.Context,
+	issues []*jira.Issue,
+	body, author string,
+) ([]Outcome, error) {
+	fn := func(ctx context.Context, iss *jira.Issue) Outcome {
+		return p.CommentIssue(ctx, iss, body, author)
+	}
+	return p.forEach(ctx, issues, ActionComment, fn)
+}
+
+// Self returns the user the Processor's client is authenticated as, e.g. to
+// pass as the author of CommentIssue.
+func (p *Processor) Self(ctx context.Context) (*jira.User, error) {
+	user, resp, err := p.client.GetSelf(ctx)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting current user: %w", err)
+	}
+	return user, nil
+}
+
+// ResolutionID returns the ID of the resolution named name, matched
+// case-insensitively. It fails with ErrUnsupported when the Processor's
+// client is not a ResolutionLister.
+func (p *Processor) ResolutionID(ctx context.Context, name string) (string, error) {
+	lister, ok := p.client.(ResolutionLister)
+	if !ok {
+		return "", fmt.Errorf("resolutions: %w", ErrUnsupported)
+	}
+	resolutions, resp, err := lister.Resolutions(ctx)
+	if resp != nil && resp.Body != nil {
+		defer drainBody(resp.Body)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting resolutions: %w", err)
+	}
+	for _, r := range resolutions {
+		if strings.EqualFold(r.Name, name) {
+			return r.ID, nil
+		}
+	}
+	return "", fmt.Errorf("resolution %q not found", name)
+}
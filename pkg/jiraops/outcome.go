@@ -0,0 +1,58 @@
+package jiraops
+
+import (
+	"fmt"
+	"time"
+)
+
+// Actions performed on an issue, as reported in Outcome.Action.
+const (
+	ActionFetch      = "fetch"
+	ActionTransition = "transition"
+	ActionAssign     = "assign"
+	ActionComment    = "comment"
+)
+
+// Outcome statuses.
+const (
+	StatusOK      = "ok"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+// Outcome is the result of one action on one issue.
+type Outcome struct {
+	Key    string
+	Action string
+	Status string
+	// Err explains a skipped or failed outcome. It matches ErrReadOnly,
+	// ErrTransitionNotFound, ErrNotAttempted, ErrSkip, ErrIssueNotFound, or
+	// ErrUnauthorized under errors.Is when one of them applies.
+	Err error
+	// NewStatus is the status a successful transition moved the issue to.
+	NewStatus string
+	// CommentID is the ID of the comment a successful comment created.
+	CommentID string
+	// Duration is how long the action's Jira call took.
+	Duration time.Duration
+}
+
+// Reason is the one-line explanation of a skipped or failed outcome, "" for
+// a successful one.
+func (o Outcome) Reason() string {
+	if o.Err == nil {
+		return ""
+	}
+	return o.Err.Error()
+}
+
+// IssueError is the failure of one action on one issue, as joined into the
+// error a batch operation returns.
+type IssueError struct {
+	Key    string
+	Action string
+	Err    error
+}
+
+func (e *IssueError) Error() string { return fmt.Sprintf("%s %s: %v", e.Key, e.Action, e.Err) }
+func (e *IssueError) Unwrap() error { return e.Err }
@@ -0,0 +1,103 @@
+package jiraops
+
+import (
+	"context"
+	"errors"
+
+	"github.com/appleboy/go-jira/pkg/issuekey"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// ExtractKeys returns the distinct issue keys in text matched by pattern
+// (issuekey.DefaultPattern when empty). Keys inside URLs, code spans, and
+// fenced code, and known false positives such as SHA-256, are skipped (see
+// issuekey.ExcludeNoise).
+func ExtractKeys(text, pattern string) ([]string, error) {
+	matches, err := issuekey.Extract(text, pattern)
+	if err != nil {
+		return nil, err
+	}
+	return issuekey.Keys(issuekey.ExcludeNoise(text, matches)), nil
+}
+
+// ExtractTrailerKeys is ExtractKeys restricted to Git trailers such as
+// "Jira: ABC-123" whose token is one of tokens (issuekey.DefaultTrailers when
+// empty), so keys mentioned in prose are ignored.
+func ExtractTrailerKeys(text, pattern string, tokens []string) ([]string, error) {
+	re, err := issuekey.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		tokens = issuekey.DefaultTrailers
+	}
+	matches := issuekey.FindTrailers(text, re, tokens)
+	return issuekey.Keys(issuekey.ExcludeNoise(text, matches)), nil
+}
+
+// Actions are what Process does to every issue referenced in the text. Empty
+// fields are skipped.
+type Actions struct {
+	// IssuePattern is the regular expression keys are extracted with;
+	// issuekey.DefaultPattern when empty.
+	IssuePattern string
+	// Transition is the name of the transition to move each issue through.
+	Transition string
+	// Resolution is the name of the resolution the transition sets.
+	Resolution string
+	// Assignee is the login name to assign each issue to.
+	Assignee string
+	// Comment is a comment body in Jira wiki markup.
+	Comment string
+}
+
+// Result is what Process did: the keys found, the issues fetched, and every
+// outcome, fetches first and then per action in the order they ran.
+type Result struct {
+	Keys     []string
+	Issues   []*jira.Issue
+	Outcomes []Outcome
+}
+
+// Process runs the whole workflow on text: extract the issue keys, fetch the
+// issues, then transition, assign, and comment on them as the Actions ask.
+// Every action runs on the issues that were fetched even when an earlier one
+// failed for some of them; the returned error joins the failures.
+func (p *Processor) Process(ctx context.Context, text string, a Actions) (*Result, error) {
+	keys, err := ExtractKeys(text, a.IssuePattern)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Keys: keys}
+	if len(keys) == 0 {
+		return res, nil
+	}
+	var resolutionID string
+	if a.Resolution != "" {
+		if resolutionID, err = p.ResolutionID(ctx, a.Resolution); err != nil {
+			return res, err
+		}
+	}
+
+	var errs []error
+	add := func(outcomes []Outcome, err error) {
+		res.Outcomes = append(res.Outcomes, outcomes...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	issues, outcomes, err := p.Fetch(ctx, keys)
+	res.Issues = issues
+	add(outcomes, err)
+	if a.Transition != "" {
+		add(p.Transition(ctx, issues, a.Transition, resolutionID))
+	}
+	if a.Assignee != "" {
+		add(p.Assign(ctx, issues, a.Assignee))
+	}
+	if a.Comment != "" {
+		add(p.Comment(ctx, issues, a.Comment, ""))
+	}
+	return res, errors.Join(errs...)
+}
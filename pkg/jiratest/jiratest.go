@@ -0,0 +1,427 @@
+// Package jiratest is an in-memory fake of the Jira REST API v2 endpoints
+// the go-jira workflow uses, for integration tests of the CLI and of programs
+// embedding pkg/jiraops. It serves configurable issues and transitions,
+// applies transitions, assignments, and comments to them, fails chosen
+// endpoints on demand, and records every request.
+//
+//	srv := jiratest.NewServer(jiratest.Config{})
+//	defer srv.Close()
+//	client, _ := jira.NewClient(nil, srv.URL)
+package jiratest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+const apiPrefix = "/rest/api/2/"
+
+// Endpoint names a group of API calls a Failure applies to.
+type Endpoint string
+
+// Endpoints the server answers.
+const (
+	EndpointSelf        Endpoint = "myself"      // GET myself
+	EndpointUser        Endpoint = "user"        // GET user?username=
+	EndpointResolutions Endpoint = "resolution"  // GET resolution
+	EndpointSearch      Endpoint = "search"      // GET/POST search
+	EndpointIssue       Endpoint = "issue"       // GET issue/{key}
+	EndpointTransition  Endpoint = "transitions" // GET/POST issue/{key}/transitions
+	EndpointAssignee    Endpoint = "assignee"    // PUT issue/{key}/assignee
+	EndpointComment     Endpoint = "comment"     // POST issue/{key}/comment
+)
+
+// Failure makes the server answer an endpoint with an error status.
+type Failure struct {
+	Endpoint Endpoint
+	// Key limits the failure to one issue; empty fails every call.
+	Key string
+	// Status is the HTTP status answered, http.StatusInternalServerError
+	// when zero.
+	Status int
+	// Message is returned in the body's errorMessages.
+	Message string
+}
+
+// Request is one request the server received.
+type Request struct {
+	Method   string
+	Path     string
+	RawQuery string
+	Body     []byte
+}
+
+// Config is the initial state of a Server. The zero value serves any issue
+// key as an open issue with a Done transition, the user "testuser", and the
+// resolutions Fixed and Done.
+type Config struct {
+	// Issues are the issues served, by key. When empty, every key is served
+	// as a default issue instead and nothing is ever not found.
+	Issues []*jira.Issue
+	// Transitions are offered by issues that have none of their own;
+	// {ID: "1", Name: "Done"} when empty.
+	Transitions []jira.Transition
+	// Self is the authenticated user; "testuser" when nil.
+	Self *jira.User
+	// Users are found by username; any username matches an "assignee" user
+	// when empty.
+	Users []jira.User
+	// Resolutions are listed by the resolution endpoint; Fixed and Done when
+	// empty.
+	Resolutions []jira.Resolution
+	// Failures are in effect from the start; see Server.Fail.
+	Failures []Failure
+}
+
+// Server is a running fake Jira. Its embedded httptest.Server provides URL
+// and Close. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	cfg      Config
+	issues   map[string]*jira.Issue
+	failures []Failure
+	requests []Request
+	lastID   int
+}
+
+// NewServer starts a Server with cfg. The caller closes it.
+func NewServer(cfg Config) *Server {
+	s := &Server{cfg: cfg, failures: slices.Clone(cfg.Failures), lastID: 12344}
+	if len(cfg.Issues) > 0 {
+		s.issues = make(map[string]*jira.Issue, len(cfg.Issues))
+		for _, iss := range cfg.Issues {
+			s.issues[iss.Key] = copyIssue(iss)
+		}
+	}
+	if len(s.cfg.Transitions) == 0 {
+		s.cfg.Transitions = []jira.Transition{{ID: "1", Name: "Done"}}
+	}
+	if s.cfg.Self == nil {
+		s.cfg.Self = &jira.User{
+			Name:         "testuser",
+			DisplayName:  "Test User",
+			EmailAddress: "test@example.com",
+		}
+	}
+	if len(s.cfg.Resolutions) == 0 {
+		s.cfg.Resolutions = []jira.Resolution{{ID: "1", Name: "Fixed"}, {ID: "2", Name: "Done"}}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Fail adds f to the failures in effect.
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, f)
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Issue returns a copy of the issue key as it stands, with the transitions,
+// assignee, and comments applied, or nil when it is not served.
+func (s *Server) Issue(key string) *jira.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	iss := s.issue(key)
+	if iss == nil {
+		return nil
+	}
+	return copyIssue(iss)
+}
+
+// copyIssue copies iss and its fields, so neither the caller's issues nor
+// the ones returned by Issue share the fields the server updates.
+func copyIssue(iss *jira.Issue) *jira.Issue {
+	c := *iss
+	if iss.Fields != nil {
+		f := *iss.Fields
+		c.Fields = &f
+	}
+	return &c
+}
+
+// issue returns the issue key, creating the default issue when the server
+// serves any key. The caller holds mu.
+func (s *Server) issue(key string) *jira.Issue {
+	if s.issues == nil {
+		s.issues = map[string]*jira.Issue{}
+	}
+	if iss, ok := s.issues[key]; ok {
+		return iss
+	}
+	if len(s.cfg.Issues) > 0 {
+		return nil
+	}
+	iss := &jira.Issue{
+		Key: key,
+		Fields: &jira.IssueFields{
+			Summary: "Test issue " + key,
+			Status:  &jira.Status{Name: "Open"},
+		},
+	}
+	s.issues[key] = iss
+	return iss
+}
+
+// failure returns the failure in effect for endpoint and key, if any. The
+// caller holds mu.
+func (s *Server) failure(endpoint Endpoint, key string) (Failure, bool) {
+	for _, f := range s.failures {
+		if f.Endpoint == endpoint && (f.Key == "" || f.Key == key) {
+			if f.Status == 0 {
+				f.Status = http.StatusInternalServerError
+			}
+			return f, true
+		}
+	}
+	return Failure{}, false
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, Request{
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		RawQuery: r.URL.RawQuery,
+		Body:     body,
+	})
+
+	path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+	if path == r.URL.Path {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	endpoint, key := route(r.Method, path)
+	if endpoint == "" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if f, ok := s.failure(endpoint, key); ok {
+		writeError(w, f.Status, f.Message)
+		return
+	}
+
+	switch endpoint {
+	case EndpointSelf:
+		writeJSON(w, http.StatusOK, s.cfg.Self)
+	case EndpointUser:
+		s.serveUser(w, r.URL.Query().Get("username"))
+	case EndpointResolutions:
+		writeJSON(w, http.StatusOK, s.cfg.Resolutions)
+	case EndpointSearch:
+		s.serveSearch(w, r, body)
+	default:
+		s.serveIssue(w, r, endpoint, key, body)
+	}
+}
+
+// route maps a method and a path below the API prefix to its endpoint and
+// issue key; the endpoint is empty for a call the server does not answer.
+func route(method, path string) (Endpoint, string) {
+	switch {
+	case path == "myself" && method == http.MethodGet:
+		return EndpointSelf, ""
+	case path == "user" && method == http.MethodGet:
+		return EndpointUser, ""
+	case path == "resolution" && method == http.MethodGet:
+		return EndpointResolutions, ""
+	case path == "search" && (method == http.MethodGet || method == http.MethodPost):
+		return EndpointSearch, ""
+	}
+	rest, ok := strings.CutPrefix(path, "issue/")
+	if !ok || rest == "" {
+		return "", ""
+	}
+	key, sub, _ := strings.Cut(rest, "/")
+	switch {
+	case sub == "" && method == http.MethodGet:
+		return EndpointIssue, key
+	case sub == "transitions" && (method == http.MethodGet || method == http.MethodPost):
+		return EndpointTransition, key
+	case sub == "assignee" && method == http.MethodPut:
+		return EndpointAssignee, key
+	case sub == "comment" && method == http.MethodPost:
+		return EndpointComment, key
+	}
+	return "", ""
+}
+
+func (s *Server) serveUser(w http.ResponseWriter, username string) {
+	if len(s.cfg.Users) == 0 {
+		writeJSON(w, http.StatusOK, jira.User{
+			Name:         "assignee",
+			DisplayName:  "Assignee User",
+			EmailAddress: "assignee@example.com",
+		})
+		return
+	}
+	for _, u := range s.cfg.Users {
+		if u.Name == username {
+			writeJSON(w, http.StatusOK, u)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "User not found")
+}
+
+// keyList matches the `key in (...)` clause the batch fetch searches with.
+var keyList = regexp.MustCompile(`(?i)\bkey\s+in\s*\(([^)]*)\)`)
+
+// serveSearch answers a `key in (...)` search with the listed issues that
+// exist and any other JQL with every issue served.
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request, body []byte) {
+	jql := r.URL.Query().Get("jql")
+	if r.Method == http.MethodPost {
+		var req struct {
+			JQL string `json:"jql"`
+		}
+		_ = json.Unmarshal(body, &req)
+		jql = req.JQL
+	}
+	var keys []string
+	if m := keyList.FindStringSubmatch(jql); m != nil {
+		for k := range strings.SplitSeq(m[1], ",") {
+			keys = append(keys, strings.Trim(strings.TrimSpace(k), `"'`))
+		}
+	} else {
+		for k := range s.issues {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+	}
+	issues := []jira.Issue{}
+	for _, k := range keys {
+		if iss := s.issue(k); iss != nil {
+			issues = append(issues, s.withTransitions(iss))
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"startAt":    0,
+		"maxResults": len(issues),
+		"total":      len(issues),
+		"issues":     issues,
+	})
+}
+
+// withTransitions returns a copy of iss with the configured transitions when
+// it has none of its own.
+func (s *Server) withTransitions(iss *jira.Issue) jira.Issue {
+	c := *iss
+	if len(c.Transitions) == 0 {
+		c.Transitions = s.cfg.Transitions
+	}
+	return c
+}
+
+func (s *Server) serveIssue(
+	w http.ResponseWriter,
+	r *http.Request,
+	endpoint Endpoint,
+	key string,
+	body []byte,
+) {
+	iss := s.issue(key)
+	if iss == nil {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+	switch endpoint {
+	case EndpointIssue:
+		writeJSON(w, http.StatusOK, s.withTransitions(iss))
+	case EndpointTransition:
+		s.serveTransition(w, r, iss, body)
+	case EndpointAssignee:
+		var user jira.User
+		if err := json.Unmarshal(body, &user); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.fields(iss).Assignee = &user
+		w.WriteHeader(http.StatusNoContent)
+	case EndpointComment:
+		var comment jira.Comment
+		if err := json.Unmarshal(body, &comment); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		f := s.fields(iss)
+		if f.Comments == nil {
+			f.Comments = &jira.Comments{}
+		}
+		s.lastID++
+		comment.ID = strconv.Itoa(s.lastID)
+		f.Comments.Comments = append(f.Comments.Comments, &comment)
+		writeJSON(w, http.StatusCreated, comment)
+	}
+}
+
+func (s *Server) serveTransition(
+	w http.ResponseWriter,
+	r *http.Request,
+	iss *jira.Issue,
+	body []byte,
+) {
+	transitions := s.withTransitions(iss).Transitions
+	if r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, map[string]any{"transitions": transitions})
+		return
+	}
+	var req struct {
+		Transition struct {
+			ID string `json:"id"`
+		} `json:"transition"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, t := range transitions {
+		if t.ID != req.Transition.ID {
+			continue
+		}
+		if t.To.Name != "" {
+			s.fields(iss).Status = &jira.Status{ID: t.To.ID, Name: t.To.Name}
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeError(w, http.StatusBadRequest, "Invalid transition")
+}
+
+// fields returns the fields of iss, creating them when nil.
+func (s *Server) fields(iss *jira.Issue) *jira.IssueFields {
+	if iss.Fields == nil {
+		iss.Fields = &jira.IssueFields{}
+	}
+	return iss.Fields
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string][]string{"errorMessages": {message}})
+}
@@ -0,0 +1,96 @@
+package jiratest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/appleboy/go-jira/pkg/jiraops"
+	"github.com/appleboy/go-jira/pkg/jiratest"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+func TestServerWithJiraops(t *testing.T) {
+	srv := jiratest.NewServer(jiratest.Config{
+		Issues: []*jira.Issue{
+			{Key: "GAIA-1", Fields: &jira.IssueFields{Status: &jira.Status{Name: "Open"}}},
+			{Key: "GAIA-2"},
+		},
+		Transitions: []jira.Transition{{ID: "31", Name: "Done", To: jira.Status{Name: "Done"}}},
+		Failures: []jiratest.Failure{{
+			Endpoint: jiratest.EndpointComment,
+			Key:      "GAIA-2",
+			Status:   http.StatusForbidden,
+			Message:  "You do not have permission",
+		}},
+	})
+	defer srv.Close()
+	client, err := jira.NewClient(nil, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := jiraops.New(jiraops.Wrap(client), jiraops.Options{})
+	res, err := p.Process(context.Background(), "GAIA-1 GAIA-2 GAIA-3", jiraops.Actions{
+		Transition: "Done",
+		Assignee:   "alice",
+		Comment:    "deployed",
+	})
+	if !errors.Is(err, jiraops.ErrIssueNotFound) || !errors.Is(err, jiraops.ErrUnauthorized) {
+		t.Errorf("err = %v, want GAIA-3 not found and the GAIA-2 comment refused", err)
+	}
+	if len(res.Issues) != 2 {
+		t.Fatalf("issues = %d, want 2", len(res.Issues))
+	}
+
+	iss := srv.Issue("GAIA-1")
+	if iss.Fields.Status.Name != "Done" {
+		t.Errorf("GAIA-1 status = %q, want Done", iss.Fields.Status.Name)
+	}
+	if iss.Fields.Assignee == nil || iss.Fields.Assignee.Name != "alice" {
+		t.Errorf("GAIA-1 assignee = %+v, want alice", iss.Fields.Assignee)
+	}
+	if iss.Fields.Comments == nil || len(iss.Fields.Comments.Comments) != 1 ||
+		iss.Fields.Comments.Comments[0].Body != "deployed" {
+		t.Errorf("GAIA-1 comments = %+v", iss.Fields.Comments)
+	}
+	if got := srv.Issue("GAIA-2").Fields.Comments; got != nil {
+		t.Errorf("GAIA-2 comments = %+v, want none", got)
+	}
+	if srv.Issue("GAIA-3") != nil {
+		t.Error("GAIA-3 should not be served")
+	}
+
+	posts := 0
+	for _, r := range srv.Requests() {
+		if r.Method == http.MethodPost && r.Path == "/rest/api/2/issue/GAIA-1/transitions" {
+			posts++
+		}
+	}
+	if posts != 1 {
+		t.Errorf("GAIA-1 transition requests = %d, want 1", posts)
+	}
+}
+
+func TestServerDefaultIssues(t *testing.T) {
+	srv := jiratest.NewServer(jiratest.Config{})
+	defer srv.Close()
+	client, err := jira.NewClient(nil, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iss, _, err := client.Issue.Get("ANY-42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iss.Fields.Summary != "Test issue ANY-42" || len(iss.Transitions) != 1 {
+		t.Errorf("issue = %+v", iss)
+	}
+	self, _, err := client.User.GetSelf()
+	if err != nil || self.Name != "testuser" {
+		t.Errorf("self = %+v, %v", self, err)
+	}
+}
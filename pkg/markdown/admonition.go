@@ -0,0 +1,317 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	bf "github.com/russross/blackfriday/v2"
+)
+
+// admonitionMacros maps the kind of a `:::kind` admonition to the Jira macro
+// it renders as. Other kinds render as a {panel}.
+var admonitionMacros = map[string]string{
+	"info":      "info",
+	"note":      "note",
+	"important": "note",
+	"tip":       "tip",
+	"hint":      "tip",
+	"success":   "tip",
+	"warning":   "warning",
+	"caution":   "warning",
+	"danger":    "warning",
+	"error":     "warning",
+}
+
+// githubAlertMacros maps the kind of a GitHub alert, a blockquote starting
+// with "[!NOTE]", to the Jira macro it renders as.
+var githubAlertMacros = map[string]string{
+	"NOTE":      "info",
+	"TIP":       "tip",
+	"IMPORTANT": "note",
+	"WARNING":   "warning",
+	"CAUTION":   "warning",
+}
+
+// admonition is a `:::kind Title` ... `:::` block, or a <details> block
+// when colons is 0, found by splitAdmonitions.
+type admonition struct {
+	colons int
+	kind   string
+	title  string
+	body   string
+}
+
+// admonitionSegment is a stretch of plain Markdown, or an admonition when
+// admonition is non-nil.
+type admonitionSegment struct {
+	markdown   string
+	admonition *admonition
+}
+
+// splitAdmonitions splits markdown into plain Markdown and the admonitions
+// between it. An admonition opens with a line of three or more colons
+// followed by its kind and an optional title, and closes with a line of at
+// least as many colons alone. A <details> line through its </details> line,
+// titled by its <summary>, is an admonition too. Admonitions nest, and
+// fences inside code blocks or without a closing line are left as text.
+func splitAdmonitions(markdown string) []admonitionSegment {
+	if !strings.Contains(markdown, ":::") && !strings.Contains(markdown, "<details") {
+		return []admonitionSegment{{markdown: markdown}}
+	}
+	lines := strings.SplitAfter(markdown, "\n")
+	var segments []admonitionSegment
+	start := 0
+	var fence codeFence
+	for i := 0; i < len(lines); i++ {
+		if fence.update(lines[i]) {
+			continue
+		}
+		a := admonitionOpening(lines[i])
+		if a == nil {
+			continue
+		}
+		end := admonitionEnd(lines, i+1, a)
+		if end < 0 {
+			continue
+		}
+		if text := strings.Join(lines[start:i], ""); text != "" {
+			segments = append(segments, admonitionSegment{markdown: text})
+		}
+		body := lines[i+1 : end]
+		if a.colons == 0 && a.title == "" {
+			body = detailsSummary(a, body)
+		}
+		a.body = strings.Join(body, "")
+		segments = append(segments, admonitionSegment{admonition: a})
+		i = end
+		start = end + 1
+	}
+	if text := strings.Join(lines[start:], ""); text != "" || len(segments) == 0 {
+		segments = append(segments, admonitionSegment{markdown: text})
+	}
+	return segments
+}
+
+// admonitionEnd returns the index of the line closing open, whose body
+// starts at lines[from], skipping nested admonitions and code blocks, or -1
+// when it is never closed.
+func admonitionEnd(lines []string, from int, open *admonition) int {
+	var fence codeFence
+	nested := []*admonition{open}
+	for i := from; i < len(lines); i++ {
+		if fence.update(lines[i]) {
+			continue
+		}
+		if a := admonitionOpening(lines[i]); a != nil {
+			nested = append(nested, a)
+			continue
+		}
+		if !nested[len(nested)-1].closedBy(lines[i]) {
+			continue
+		}
+		if nested = nested[:len(nested)-1]; len(nested) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// admonitionOpening parses a `:::kind Title` or <details> line, returning nil
+// when line is not one.
+func admonitionOpening(line string) *admonition {
+	line = strings.TrimSpace(line)
+	if rest, ok := strings.CutPrefix(line, "<details"); ok {
+		if rest == "" || (rest[0] != '>' && rest[0] != ' ') ||
+			strings.Contains(rest, "</details>") {
+			return nil
+		}
+		_, rest, _ = strings.Cut(rest, ">")
+		a := &admonition{kind: "details"}
+		if m := summaryPattern.FindStringSubmatch(rest); m != nil {
+			a.title = htmlText(m[1])
+		}
+		return a
+	}
+	colons := leadingColons(line)
+	if colons < 3 {
+		return nil
+	}
+	kind, title, _ := strings.Cut(strings.TrimSpace(line[colons:]), " ")
+	if kind == "" {
+		return nil
+	}
+	return &admonition{
+		colons: colons,
+		kind:   strings.ToLower(kind),
+		title:  strings.TrimSpace(title),
+	}
+}
+
+// closedBy reports whether line closes a: a line of at least as many colons
+// alone, or </details>.
+func (a *admonition) closedBy(line string) bool {
+	line = strings.TrimSpace(line)
+	if a.colons == 0 {
+		return line == "</details>"
+	}
+	n := leadingColons(line)
+	return n >= a.colons && n == len(line)
+}
+
+// summaryPattern matches a <summary> element, capturing its content.
+var summaryPattern = regexp.MustCompile(`(?is)<summary[^>]*>(.*?)</summary>`)
+
+// detailsSummary sets the title of the details block a from a <summary> on
+// the first non-blank line of body, returning body without it.
+func detailsSummary(a *admonition, body []string) []string {
+	for i, line := range body {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if m := summaryPattern.FindStringSubmatch(line); m != nil {
+			a.title = htmlText(m[1])
+			return body[i+1:]
+		}
+		break
+	}
+	return body
+}
+
+// htmlText returns the text of html with its tags removed.
+func htmlText(html string) string {
+	return strings.TrimSpace(htmlTagPattern.ReplaceAllString(html, ""))
+}
+
+func leadingColons(s string) int {
+	n := 0
+	for n < len(s) && s[n] == ':' {
+		n++
+	}
+	return n
+}
+
+// codeFence tracks whether a line-by-line scan is inside a fenced code
+// block.
+type codeFence struct {
+	marker string
+}
+
+// update advances the scan by line and reports whether line belongs to a
+// code block, fences included.
+func (f *codeFence) update(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return f.marker != ""
+	}
+	trimmed = strings.TrimRight(trimmed, " \t\r\n")
+	if f.marker != "" {
+		if strings.HasPrefix(trimmed, f.marker) &&
+			strings.Trim(trimmed, f.marker[:1]) == "" {
+			f.marker = ""
+		}
+		return true
+	}
+	for _, c := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == c {
+			n++
+		}
+		if n >= 3 {
+			f.marker = trimmed[:n]
+			return true
+		}
+	}
+	return false
+}
+
+// renderAdmonition renders a as its Jira macro around its body, converted
+// with opts.
+func renderAdmonition(a *admonition, opts Options) string {
+	macro, ok := admonitionMacros[a.kind]
+	switch {
+	case a.colons == 0:
+		macro, ok = "expand", true
+	case !ok:
+		macro = "panel"
+	}
+	title := a.title
+	if title == "" && !ok {
+		title = strings.ToUpper(a.kind[:1]) + a.kind[1:]
+	}
+	var b strings.Builder
+	b.WriteString("{")
+	b.WriteString(macro)
+	if title != "" {
+		b.WriteString(":title=")
+		b.WriteString(macroParamStripper.Replace(title))
+	}
+	b.WriteString("}\n")
+	if body := renderMarkdown(a.body, opts, nil); body != "" {
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+	b.WriteString("{")
+	b.WriteString(macro)
+	b.WriteString("}")
+	return b.String()
+}
+
+// githubAlert reports the Jira macro of quote when it is a GitHub alert, and
+// removes the "[!NOTE]" line from its first paragraph, which goes too if
+// nothing else is left. It returns "" for other blockquotes.
+func githubAlert(quote *bf.Node) string {
+	para := quote.FirstChild
+	if para == nil || para.Type != bf.Paragraph || para.FirstChild == nil ||
+		para.FirstChild.Type != bf.Text {
+		return ""
+	}
+	text := para.FirstChild
+	lit := text.Literal
+	end := bytes.IndexByte(lit, ']')
+	if len(lit) < 3 || lit[0] != '[' || lit[1] != '!' || end < 0 {
+		return ""
+	}
+	macro, ok := githubAlertMacros[strings.ToUpper(string(lit[2:end]))]
+	if !ok {
+		return ""
+	}
+	rest := bytes.TrimLeft(lit[end+1:], " \t")
+	if len(rest) > 0 && rest[0] != '\n' {
+		// GitHub only reads the marker alone on its line.
+		return ""
+	}
+	text.Literal = bytes.TrimPrefix(rest, []byte("\n"))
+	if len(text.Literal) == 0 && text.Next == nil {
+		para.Unlink()
+	}
+	return macro
+}
+
+func (r *JiraRenderer) renderBlockQuote(w *bytes.Buffer, node *bf.Node, entering bool) {
+	if entering {
+		macro := githubAlert(node)
+		r.quoteMacros = append(r.quoteMacros, macro)
+		if macro == "" {
+			return
+		}
+		if w.Len() > 0 {
+			w.WriteString("\n")
+		}
+		w.WriteString("{" + macro + "}")
+		return
+	}
+	n := len(r.quoteMacros)
+	if n == 0 {
+		return
+	}
+	macro := r.quoteMacros[n-1]
+	r.quoteMacros = r.quoteMacros[:n-1]
+	if macro == "" {
+		return
+	}
+	if b := w.Bytes(); len(b) > 0 && b[len(b)-1] != '\n' {
+		w.WriteString("\n")
+	}
+	w.WriteString("{" + macro + "}")
+}
@@ -0,0 +1,168 @@
+package markdown
+
+import "testing"
+
+func TestToJiraAdmonitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "info",
+			markdown: ":::info\nDeploys run **nightly**.\n:::",
+			want:     "{info}\nDeploys run *nightly*.\n{info}",
+		},
+		{
+			name:     "warning with a title",
+			markdown: ":::warning Breaking change\nThe `v1` API is gone.\n:::",
+			want:     "{warning:title=Breaking change}\nThe {{v1}} API is gone.\n{warning}",
+		},
+		{
+			name:     "alias kind",
+			markdown: ":::caution\nCareful.\n:::",
+			want:     "{warning}\nCareful.\n{warning}",
+		},
+		{
+			name:     "unknown kind as a panel",
+			markdown: ":::summary\nAll good.\n:::",
+			want:     "{panel:title=Summary}\nAll good.\n{panel}",
+		},
+		{
+			name:     "title markup is stripped",
+			markdown: ":::note a|b}c\nx\n:::",
+			want:     "{note:title=abc}\nx\n{note}",
+		},
+		{
+			name:     "surrounding text",
+			markdown: "# Release\n\n:::tip\nUpgrade now.\n:::\n\nThanks.",
+			want:     "h1. Release\n\n{tip}\nUpgrade now.\n{tip}\n\nThanks.",
+		},
+		{
+			name:     "nested",
+			markdown: "::::note\nOuter\n\n:::warning\nInner\n:::\n::::",
+			want:     "{note}\nOuter\n\n{warning}\nInner\n{warning}\n{note}",
+		},
+		{
+			name:     "list body",
+			markdown: ":::info\n- one\n- two\n:::",
+			want:     "{info}\n* one\n* two\n{info}",
+		},
+		{
+			name:     "inside a code block",
+			markdown: "```\n:::info\nx\n:::\n```",
+			want:     "{noformat}\n:::info\nx\n:::\n{noformat}",
+		},
+		{
+			name:     "unclosed",
+			markdown: ":::info\nx",
+			want:     ":::info\nx",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJira(tt.markdown); got != tt.want {
+				t.Errorf("ToJira() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJiraGitHubAlerts(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "note",
+			markdown: "> [!NOTE]\n> Useful **info**.",
+			want:     "{info}\nUseful *info*.\n{info}",
+		},
+		{
+			name:     "warning with paragraphs",
+			markdown: "> [!WARNING]\n> First.\n>\n> Second.",
+			want:     "{warning}\nFirst.\n\nSecond.\n{warning}",
+		},
+		{
+			name:     "important on its own paragraph",
+			markdown: "> [!important]\n>\n> Read this.",
+			want:     "{note}\nRead this.\n{note}",
+		},
+		{
+			name:     "surrounding text",
+			markdown: "Before.\n\n> [!TIP]\n> Try it.\n\nAfter.",
+			want:     "Before.\n\n{tip}\nTry it.\n{tip}\nAfter.",
+		},
+		{
+			name:     "unknown kind",
+			markdown: "> [!HINT]\n> x",
+			want:     "[!HINT]\nx",
+		},
+		{
+			name:     "marker followed by text",
+			markdown: "> [!NOTE] x",
+			want:     "[!NOTE] x",
+		},
+		{
+			name:     "plain blockquote",
+			markdown: "> quoted",
+			want:     "quoted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJira(tt.markdown); got != tt.want {
+				t.Errorf("ToJira() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJiraDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name: "summary on its own line",
+			markdown: "<details>\n<summary>Test results</summary>\n\n" +
+				"- 10 passed\n- 1 **failed**\n\n</details>",
+			want: "{expand:title=Test results}\n* 10 passed\n* 1 *failed*\n{expand}",
+		},
+		{
+			name:     "summary on the details line",
+			markdown: "<details open><summary><b>Logs</b></summary>\n\nAll good.\n</details>",
+			want:     "{expand:title=Logs}\nAll good.\n{expand}",
+		},
+		{
+			name:     "no summary",
+			markdown: "<details>\n\nHidden.\n\n</details>",
+			want:     "{expand}\nHidden.\n{expand}",
+		},
+		{
+			name: "admonition inside",
+			markdown: "<details>\n<summary>More</summary>\n\n" +
+				":::warning\nCareful.\n:::\n\n</details>",
+			want: "{expand:title=More}\n{warning}\nCareful.\n{warning}\n{expand}",
+		},
+		{
+			name:     "nested",
+			markdown: "<details>\n\nOuter\n\n<details>\n\nInner\n\n</details>\n\n</details>",
+			want:     "{expand}\nOuter\n\n{expand}\nInner\n{expand}\n{expand}",
+		},
+		{
+			name:     "unclosed",
+			markdown: "<details>\n\nHidden.",
+			want:     "Hidden.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJira(tt.markdown); got != tt.want {
+				t.Errorf("ToJira() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+
+	bf "github.com/russross/blackfriday/v2"
+)
+
+// codeMacroParams are the fence info attributes passed on as {code} macro
+// parameters, e.g. ```go title="main.go" collapse linenumbers. A bare
+// attribute means "true".
+var codeMacroParams = map[string]bool{
+	"title":       true,
+	"collapse":    true,
+	"linenumbers": true,
+	"firstline":   true,
+	"theme":       true,
+}
+
+// jiraLanguages are the languages the Jira {code} macro highlights.
+var jiraLanguages = map[string]bool{
+	"actionscript": true, "ada": true, "applescript": true, "bash": true, "c": true,
+	"c#": true, "c++": true, "cpp": true, "css": true, "erlang": true, "go": true,
+	"groovy": true, "haskell": true, "html": true, "java": true, "javascript": true,
+	"json": true, "lua": true, "nyan": true, "objc": true, "perl": true, "php": true,
+	"python": true, "r": true, "rainbow": true, "ruby": true, "scala": true, "sh": true,
+	"sql": true, "swift": true, "visualbasic": true, "xml": true, "yaml": true,
+	"none": true,
+}
+
+// languageAliases maps common fence languages Jira does not know by that
+// name to the closest highlighter it has.
+var languageAliases = map[string]string{
+	"golang":        "go",
+	"ts":            "javascript",
+	"typescript":    "javascript",
+	"js":            "javascript",
+	"jsx":           "javascript",
+	"tsx":           "javascript",
+	"mjs":           "javascript",
+	"cjs":           "javascript",
+	"shell":         "bash",
+	"zsh":           "bash",
+	"console":       "bash",
+	"shell-session": "bash",
+	"yml":           "yaml",
+	"py":            "python",
+	"python3":       "python",
+	"rb":            "ruby",
+	"cs":            "c#",
+	"csharp":        "c#",
+	"cxx":           "cpp",
+	"hpp":           "cpp",
+	"cc":            "cpp",
+	"h":             "c",
+	"objective-c":   "objc",
+	"objectivec":    "objc",
+	"kotlin":        "java",
+	"kt":            "java",
+	"kts":           "java",
+	"gradle":        "groovy",
+	"jsonc":         "json",
+	"json5":         "json",
+	"xhtml":         "html",
+	"htm":           "html",
+	"svg":           "xml",
+	"xsd":           "xml",
+	"xsl":           "xml",
+	"scss":          "css",
+	"sass":          "css",
+	"less":          "css",
+	"mysql":         "sql",
+	"postgresql":    "sql",
+	"postgres":      "sql",
+	"pgsql":         "sql",
+	"plsql":         "sql",
+	"erl":           "erlang",
+	"hs":            "haskell",
+	"pl":            "perl",
+	"vb":            "visualbasic",
+	"vbnet":         "visualbasic",
+}
+
+// jiraLanguage returns the {code} macro language for a fence language:
+// itself when Jira highlights it, its alias, or "none" for plain text, since
+// an unknown language breaks the highlighting.
+func jiraLanguage(language string) string {
+	language = strings.ToLower(language)
+	if jiraLanguages[language] {
+		return language
+	}
+	if alias, ok := languageAliases[language]; ok {
+		return alias
+	}
+	return "none"
+}
+
+func (r *JiraRenderer) renderCodeBlock(w *bytes.Buffer, node *bf.Node, entering bool) {
+	if !entering {
+		return
+	}
+	language, params := parseFenceInfo(string(node.Info))
+	if node.IsFenced && strings.EqualFold(language, "jira") {
+		// A ```jira fence is Jira markup for what the renderer cannot
+		// express, written out as is. Its trailing newline keeps it apart
+		// from the next block, so a table or list in it does not run on.
+		if w.Len() > 0 {
+			w.WriteString("\n")
+		}
+		w.Write(node.Literal)
+		return
+	}
+	// Indented code blocks cannot name a language, and are usually plain
+	// preformatted text such as command output, so the default only applies
+	// to fences.
+	if language == "" && node.IsFenced {
+		language = r.opts.DefaultCodeLanguage
+	}
+	if w.Len() > 0 {
+		w.WriteString("\n")
+	}
+	if strings.EqualFold(language, "mermaid") {
+		r.renderMermaid(w, node.Literal)
+		return
+	}
+	if language == "" && len(params) == 0 {
+		w.WriteString("{noformat}\n")
+		w.Write(node.Literal)
+		w.WriteString("{noformat}")
+		return
+	}
+	// Jira highlights a {code} macro without a language as Java.
+	w.WriteString("{code:language=")
+	w.WriteString(jiraLanguage(language))
+	for _, p := range params {
+		w.WriteString("|")
+		w.WriteString(p)
+	}
+	w.WriteString("}\n")
+	w.Write(node.Literal)
+	w.WriteString("{code}")
+}
+
+// mermaidTitle titles the {code} macro a Mermaid diagram falls back to.
+const mermaidTitle = "Mermaid diagram (not rendered by Jira)"
+
+// renderMermaid writes a ```mermaid fence, which Jira cannot draw, as an
+// image from the MermaidImageURL service or else as plain code titled as an
+// unrendered diagram.
+func (r *JiraRenderer) renderMermaid(w *bytes.Buffer, source []byte) {
+	if r.opts.MermaidImageURL != "" {
+		w.WriteString("!")
+		w.WriteString(r.opts.MermaidImageURL)
+		w.WriteString(base64.URLEncoding.EncodeToString(bytes.TrimSpace(source)))
+		w.WriteString("!")
+		return
+	}
+	w.WriteString("{code:language=none|title=")
+	w.WriteString(mermaidTitle)
+	w.WriteString("}\n")
+	w.Write(source)
+	w.WriteString("{code}")
+}
+
+// parseFenceInfo splits a fence info string such as `go title="main.go"
+// collapse` into the language, the first word unless it is an attribute, and
+// the {code} macro parameters ("title=main.go", "collapse=true") for the
+// attributes codeMacroParams knows. Values may be quoted with " or '.
+func parseFenceInfo(info string) (language string, params []string) {
+	for i, field := range splitFenceInfo(info) {
+		key, value, hasValue := strings.Cut(field, "=")
+		if i == 0 && !hasValue && !codeMacroParams[strings.ToLower(field)] {
+			language = field
+			continue
+		}
+		key = strings.ToLower(key)
+		if !codeMacroParams[key] {
+			continue
+		}
+		if !hasValue {
+			value = "true"
+		}
+		value = macroParamStripper.Replace(value)
+		if value != "" {
+			params = append(params, key+"="+value)
+		}
+	}
+	return language, params
+}
+
+// splitFenceInfo splits info on spaces outside quotes, dropping the quotes.
+func splitFenceInfo(info string) []string {
+	var fields []string
+	var field strings.Builder
+	var quote byte
+	inField := false
+	for i := 0; i < len(info); i++ {
+		c := info[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			field.WriteByte(c)
+		case c == '"' || c == '\'':
+			quote, inField = c, true
+		case c == ' ' || c == '\t':
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteByte(c)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
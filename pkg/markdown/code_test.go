@@ -0,0 +1,166 @@
+package markdown
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseFenceInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     string
+		language string
+		params   []string
+	}{
+		{name: "empty", info: ""},
+		{name: "language only", info: "go", language: "go"},
+		{
+			name:     "title and flags",
+			info:     `go title="main.go" collapse linenumbers`,
+			language: "go",
+			params:   []string{"title=main.go", "collapse=true", "linenumbers=true"},
+		},
+		{
+			name:   "attributes without a language",
+			info:   `title='CI log | run 7' collapse=false`,
+			params: []string{"title=CI log  run 7", "collapse=false"},
+		},
+		{
+			name:     "unknown attributes are dropped",
+			info:     `yaml highlight=3 firstline=10`,
+			language: "yaml",
+			params:   []string{"firstline=10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, params := parseFenceInfo(tt.info)
+			if language != tt.language || !slices.Equal(params, tt.params) {
+				t.Errorf("parseFenceInfo(%q) = %q, %q, want %q, %q",
+					tt.info, language, params, tt.language, tt.params)
+			}
+		})
+	}
+}
+
+func TestJiraLanguage(t *testing.T) {
+	tests := map[string]string{
+		"go":         "go",
+		"Go":         "go",
+		"golang":     "go",
+		"ts":         "javascript",
+		"sh":         "sh",
+		"shell":      "bash",
+		"yml":        "yaml",
+		"C#":         "c#",
+		"rust":       "none",
+		"text":       "none",
+		"":           "none",
+		"dockerfile": "none",
+	}
+	for language, want := range tests {
+		if got := jiraLanguage(language); got != want {
+			t.Errorf("jiraLanguage(%q) = %q, want %q", language, got, want)
+		}
+	}
+}
+
+func TestToJiraNoformat(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		opts     Options
+		want     string
+	}{
+		{
+			name:     "fence without a language",
+			markdown: "```\n$ make\nok\n```",
+			want:     "{noformat}\n$ make\nok\n{noformat}",
+		},
+		{
+			name:     "indented code block",
+			markdown: "Output:\n\n    $ make\n    ok",
+			want:     "Output:\n\n{noformat}\n$ make\nok\n{noformat}",
+		},
+		{
+			name:     "default language applies to fences",
+			markdown: "```\nfmt.Println()\n```",
+			opts:     Options{DefaultCodeLanguage: "go"},
+			want:     "{code:language=go}\nfmt.Println()\n{code}",
+		},
+		{
+			name:     "indented code block ignores the default language",
+			markdown: "    $ make",
+			opts:     Options{DefaultCodeLanguage: "go"},
+			want:     "{noformat}\n$ make\n{noformat}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJiraWithOptions(tt.markdown, tt.opts); got != tt.want {
+				t.Errorf("ToJiraWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJiraCodeMacroParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "title and collapse",
+			markdown: "```go title=\"main.go\" collapse\npackage main\n```",
+			want:     "{code:language=go|title=main.go|collapse=true}\npackage main\n{code}",
+		},
+		{
+			name:     "language alias",
+			markdown: "```yml\non: push\n```",
+			want:     "{code:language=yaml}\non: push\n{code}",
+		},
+		{
+			name:     "collapse without a language",
+			markdown: "``` collapse\nlong CI log\n```",
+			want:     "{code:language=none|collapse=true}\nlong CI log\n{code}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJira(tt.markdown); got != tt.want {
+				t.Errorf("ToJira() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJiraMermaid(t *testing.T) {
+	src := "```mermaid\ngraph TD\n  A-->B\n```"
+	want := "{code:language=none|title=" + mermaidTitle + "}\ngraph TD\n  A-->B\n{code}"
+	if got := ToJira(src); got != want {
+		t.Errorf("ToJira() = %q, want %q", got, want)
+	}
+	got := ToJiraWithOptions(src, Options{MermaidImageURL: "https://mermaid.ink/img/"})
+	want = "!https://mermaid.ink/img/Z3JhcGggVEQKICBBLS0-Qg==!"
+	if got != want {
+		t.Errorf("ToJiraWithOptions() = %q, want %q", got, want)
+	}
+}
+
+func TestToJiraRawFence(t *testing.T) {
+	src := "Status:\n\n```Jira\n{status:colour=Green|title=@bob *done*}\n||a||b||\n```\n\n" +
+		"after **b**"
+	want := "Status:\n\n{status:colour=Green|title=@bob *done*}\n||a||b||\n\nafter *b*"
+	if got := ToJira(src); got != want {
+		t.Errorf("ToJira() = %q, want %q", got, want)
+	}
+	// Only a fence naming jira is raw, not one defaulting to it.
+	got := ToJiraWithOptions("```\n*x*\n```", Options{DefaultCodeLanguage: "jira"})
+	if want := "{code:language=none}\n*x*\n{code}"; got != want {
+		t.Errorf("ToJiraWithOptions() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// colorSpanPattern matches a ==text=={color} span, capturing the text and
+// the color: a name such as red, or a #rgb or #rrggbb code.
+var colorSpanPattern = regexp.MustCompile(
+	`==([^=\s](?:[^=\n]*[^=\s])?)==\{(#[0-9a-fA-F]{6}|#[0-9a-fA-F]{3}|[a-zA-Z]+)\}`)
+
+// convertColorSpans rewrites the ==text=={color} spans of markdown outside
+// code as Jira {color} macros. Blackfriday passes those through as text, as
+// it does the ones written by hand, so the Markdown inside still converts.
+func convertColorSpans(markdown string) string {
+	if !strings.Contains(markdown, "=={") {
+		return markdown
+	}
+	lines := strings.SplitAfter(markdown, "\n")
+	var fence codeFence
+	for i, line := range lines {
+		if fence.update(line) || !strings.Contains(line, "=={") {
+			continue
+		}
+		lines[i] = outsideCodeSpans(line, func(s string) string {
+			return colorSpanPattern.ReplaceAllString(s, "{color:$2}$1{color}")
+		})
+	}
+	return strings.Join(lines, "")
+}
+
+// outsideCodeSpans returns line with convert applied to the text outside
+// its `code` spans.
+func outsideCodeSpans(line string, convert func(string) string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(line, '`')
+		if start < 0 {
+			break
+		}
+		n := backticks(line[start:])
+		end := codeSpanEnd(line, start+n, n)
+		if end < 0 {
+			break
+		}
+		b.WriteString(convert(line[:start]))
+		b.WriteString(line[start:end])
+		line = line[end:]
+	}
+	b.WriteString(convert(line))
+	return b.String()
+}
+
+// codeSpanEnd returns the end of the run of exactly n backticks closing a
+// code span whose content starts at line[from], or -1 when there is none.
+func codeSpanEnd(line string, from, n int) int {
+	for from < len(line) {
+		i := strings.IndexByte(line[from:], '`')
+		if i < 0 {
+			return -1
+		}
+		from += i
+		m := backticks(line[from:])
+		if m == n {
+			return from + n
+		}
+		from += m
+	}
+	return -1
+}
+
+func backticks(s string) int {
+	n := 0
+	for n < len(s) && s[n] == '`' {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,49 @@
+package markdown
+
+import "testing"
+
+func TestToJiraColorSpans(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "hex color",
+			markdown: "Status: ==delayed=={#ff8b00}",
+			want:     "Status: {color:#ff8b00}delayed{color}",
+		},
+		{
+			name:     "named color around emphasis",
+			markdown: "==**GAIA-1** is done=={green}",
+			want:     "{color:green}*GAIA-1* is done{color}",
+		},
+		{
+			name:     "hand-written macro",
+			markdown: "{color:#00875A}**refs/heads/snake_case_branch**{color}",
+			want:     "{color:#00875A}*refs/heads/snake_case_branch*{color}",
+		},
+		{
+			name:     "not in code",
+			markdown: "`==x=={red}` or ``a ` ==y=={red}`` but ==z=={red}",
+			want:     "{{==x=={red}}} or {{a ` ==y=={red}}} but {color:red}z{color}",
+		},
+		{
+			name:     "not in code blocks",
+			markdown: "```\n==x=={red}\n```",
+			want:     "{noformat}\n==x=={red}\n{noformat}",
+		},
+		{
+			name:     "no or invalid color",
+			markdown: "==kept== and ==kept=={not a color}",
+			want:     "==kept== and ==kept=={not a color}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJira(tt.markdown); got != tt.want {
+				t.Errorf("ToJira() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
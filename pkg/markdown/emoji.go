@@ -0,0 +1,114 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+)
+
+// emojiShortcodes maps common GitHub/Slack emoji shortcodes to Jira
+// emoticons where Jira has an equivalent, and to the Unicode emoji otherwise,
+// so CI comments keep their visual cues.
+var emojiShortcodes = map[string]string{
+	"warning":                     "(!)",
+	"white_check_mark":            "(/)",
+	"heavy_check_mark":            "(/)",
+	"ballot_box_with_check":       "(/)",
+	"x":                           "(x)",
+	"negative_squared_cross_mark": "(x)",
+	"information_source":          "(i)",
+	"question":                    "(?)",
+	"grey_question":               "(?)",
+	"heavy_plus_sign":             "(+)",
+	"heavy_minus_sign":            "(-)",
+	"bulb":                        "(on)",
+	"star":                        "(*)",
+	"+1":                          "(y)",
+	"thumbsup":                    "(y)",
+	"-1":                          "(n)",
+	"thumbsdown":                  "(n)",
+	"triangular_flag_on_post":     "(flag)",
+	"smile":                       ":D",
+	"smiley":                      ":D",
+	"slightly_smiling_face":       ":)",
+	"disappointed":                ":(",
+	"slightly_frowning_face":      ":(",
+	"wink":                        ";)",
+	"stuck_out_tongue":            ":P",
+	"rocket":                      "🚀",
+	"tada":                        "🎉",
+	"bug":                         "🐛",
+	"fire":                        "🔥",
+	"sparkles":                    "✨",
+	"memo":                        "📝",
+	"lock":                        "🔒",
+	"construction":                "🚧",
+	"package":                     "📦",
+	"zap":                         "⚡",
+	"art":                         "🎨",
+	"recycle":                     "♻️",
+	"hammer":                      "🔨",
+	"wrench":                      "🔧",
+	"boom":                        "💥",
+	"heart":                       "❤️",
+	"eyes":                        "👀",
+	"100":                         "💯",
+	"pencil2":                     "✏️",
+	"arrow_up":                    "⬆️",
+	"arrow_down":                  "⬇️",
+	"rotating_light":              "🚨",
+	"no_entry":                    "⛔",
+	"hourglass":                   "⌛",
+	"stopwatch":                   "⏱️",
+	"link":                        "🔗",
+	"mag":                         "🔍",
+	"chart_with_upwards_trend":    "📈",
+	"test_tube":                   "🧪",
+	"green_heart":                 "💚",
+	"red_circle":                  "🔴",
+	"large_green_circle":          "🟢",
+	"large_yellow_circle":         "🟡",
+}
+
+// convertEmoji replaces the :shortcode: emoji in text that emojiShortcodes
+// knows; unknown ones, such as the "30" of a "10:30:00" timestamp, are kept.
+func convertEmoji(text string) string {
+	start := strings.IndexByte(text, ':')
+	if start < 0 || strings.IndexByte(text[start+1:], ':') < 0 {
+		return text
+	}
+	var b bytes.Buffer
+	b.Grow(len(text))
+	writeEmoji(&b, text)
+	return b.String()
+}
+
+// writeEmoji writes text to w with its emoji converted as convertEmoji
+// does.
+func writeEmoji(w *bytes.Buffer, text string) {
+	for {
+		start := strings.IndexByte(text, ':')
+		if start < 0 {
+			break
+		}
+		end := start + 1
+		for end < len(text) && isShortcodeChar(text[end]) {
+			end++
+		}
+		if end < len(text) && text[end] == ':' {
+			if emoji, ok := emojiShortcodes[text[start+1:end]]; ok {
+				w.WriteString(text[:start])
+				w.WriteString(emoji)
+				text = text[end+1:]
+				continue
+			}
+		}
+		w.WriteString(text[:start+1])
+		text = text[start+1:]
+	}
+	w.WriteString(text)
+}
+
+// isShortcodeChar reports whether c may appear in an emoji shortcode name.
+func isShortcodeChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '+' || c == '-'
+}
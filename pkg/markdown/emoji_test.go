@@ -0,0 +1,29 @@
+package markdown
+
+import "testing"
+
+func TestConvertEmoji(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "no colon", text: "Deployed", want: "Deployed"},
+		{name: "jira emoticon", text: ":warning: flaky test", want: "(!) flaky test"},
+		{name: "check mark", text: "tests :white_check_mark:", want: "tests (/)"},
+		{name: "unicode fallback", text: ":rocket: released", want: "🚀 released"},
+		{name: "plus one", text: ":+1: :-1:", want: "(y) (n)"},
+		{name: "adjacent shortcodes", text: ":tada::tada:", want: "🎉🎉"},
+		{name: "unknown shortcode kept", text: ":not_an_emoji: ok", want: ":not_an_emoji: ok"},
+		{name: "timestamp kept", text: "at 10:30:00", want: "at 10:30:00"},
+		{name: "lone colon", text: "Note: :bug: fixed", want: "Note: 🐛 fixed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertEmoji(tt.text); got != tt.want {
+				t.Errorf("convertEmoji(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
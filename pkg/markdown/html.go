@@ -0,0 +1,142 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/appleboy/com/bytesconv"
+	bf "github.com/russross/blackfriday/v2"
+)
+
+var (
+	// htmlTagPattern matches an HTML comment or a start, end, or
+	// self-closing tag, capturing the slash of an end tag, the tag name, and
+	// the attributes.
+	htmlTagPattern = regexp.MustCompile(`(?s)<!--.*?-->|<(/?)([a-zA-Z][a-zA-Z0-9]*)(\s[^>]*)?>`)
+	// htmlAttrPattern matches a name="value", name='value', or name=value
+	// attribute.
+	htmlAttrPattern = regexp.MustCompile(`([a-zA-Z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	// jiraEscaper backslash-escapes the characters Jira reads as markup.
+	jiraEscaper = strings.NewReplacer(
+		`\`, `\\`, "*", `\*`, "_", `\_`, "-", `\-`, "+", `\+`, "^", `\^`, "~", `\~`,
+		"[", `\[`, "]", `\]`, "{", `\{`, "}", `\}`, "|", `\|`, "!", `\!`,
+	)
+)
+
+// htmlInlineMarkup maps the tags HTMLConvert turns into symmetric Jira
+// markup to that markup.
+var htmlInlineMarkup = map[string]string{
+	"b": "*", "strong": "*",
+	"i": "_", "em": "_",
+	"u":   "+",
+	"del": "-", "s": "-",
+	"sub": "~", "sup": "^",
+}
+
+func (r *JiraRenderer) renderHTMLBlock(w *bytes.Buffer, node *bf.Node, entering bool) {
+	if !entering || r.opts.HTML == HTMLDrop {
+		return
+	}
+	var block bytes.Buffer
+	r.renderHTML(&block, bytesconv.BytesToStr(node.Literal))
+	content := bytes.TrimSpace(block.Bytes())
+	if len(content) == 0 {
+		return
+	}
+	if w.Len() > 0 {
+		w.WriteString("\n")
+	}
+	w.Write(content)
+	w.WriteString("\n")
+}
+
+func (r *JiraRenderer) renderHTMLSpan(w *bytes.Buffer, node *bf.Node, _ bool) {
+	r.renderHTML(w, bytesconv.BytesToStr(node.Literal))
+}
+
+// renderHTML writes html as the HTML option asks.
+func (r *JiraRenderer) renderHTML(w *bytes.Buffer, html string) {
+	switch r.opts.HTML {
+	case HTMLEscape:
+		w.WriteString(jiraEscaper.Replace(html))
+	case HTMLConvert:
+		r.convertHTML(w, html)
+	case HTMLPassthrough:
+		w.WriteString(html)
+	}
+}
+
+// convertHTML writes html with its tags converted for HTMLConvert. The
+// hrefs of open <a> tags are kept on the renderer, since blackfriday splits
+// an inline link into separate spans for its start and end tags.
+func (r *JiraRenderer) convertHTML(w *bytes.Buffer, html string) {
+	last := 0
+	for _, m := range htmlTagPattern.FindAllStringSubmatchIndex(html, -1) {
+		w.WriteString(html[last:m[0]])
+		last = m[1]
+		if m[4] < 0 {
+			continue // a comment
+		}
+		closing := m[3] > m[2]
+		name := strings.ToLower(html[m[4]:m[5]])
+		var attrs string
+		if m[6] >= 0 {
+			attrs = html[m[6]:m[7]]
+		}
+		if markup, ok := htmlInlineMarkup[name]; ok {
+			w.WriteString(markup)
+			continue
+		}
+		switch name {
+		case "br":
+			w.WriteString("\n")
+		case "code":
+			if closing {
+				w.WriteString("}}")
+			} else {
+				w.WriteString("{{")
+			}
+		case "a":
+			r.convertHTMLLink(w, closing, htmlAttr(attrs, "href"))
+		case "img":
+			if src := htmlAttr(attrs, "src"); src != "" {
+				w.WriteString("!")
+				w.WriteString(r.resolveURL(src))
+				w.WriteString("!")
+			}
+		}
+	}
+	w.WriteString(html[last:])
+}
+
+func (r *JiraRenderer) convertHTMLLink(w *bytes.Buffer, closing bool, href string) {
+	if !closing {
+		r.htmlLinks = append(r.htmlLinks, r.resolveURL(href))
+		if href != "" {
+			w.WriteString("[")
+		}
+		return
+	}
+	n := len(r.htmlLinks)
+	if n == 0 {
+		return
+	}
+	href = r.htmlLinks[n-1]
+	r.htmlLinks = r.htmlLinks[:n-1]
+	if href != "" {
+		w.WriteString("|")
+		w.WriteString(href)
+		w.WriteString("]")
+	}
+}
+
+// htmlAttr returns the value of the attribute name in attrs, or "".
+func htmlAttr(attrs, name string) string {
+	for _, m := range htmlAttrPattern.FindAllStringSubmatch(attrs, -1) {
+		if strings.EqualFold(m[1], name) {
+			return m[2] + m[3] + m[4]
+		}
+	}
+	return ""
+}
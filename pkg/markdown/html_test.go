@@ -0,0 +1,65 @@
+package markdown
+
+import "testing"
+
+func TestToJiraHTML(t *testing.T) {
+	const inline = `Hi <b>bold</b>, <em>em</em> and <a href="http://x.io/a_b">link</a><br>next`
+	tests := []struct {
+		name     string
+		mode     HTMLMode
+		markdown string
+		want     string
+	}{
+		{
+			name:     "drop by default",
+			markdown: inline,
+			want:     "Hi bold, em and linknext",
+		},
+		{
+			name:     "escape",
+			mode:     HTMLEscape,
+			markdown: "a <span class=\"x_y\">b</span>",
+			want:     `a <span class="x\_y">b</span>`,
+		},
+		{
+			name:     "convert inline subset",
+			mode:     HTMLConvert,
+			markdown: inline,
+			want:     "Hi *bold*, _em_ and [link|http://x.io/a_b]\nnext",
+		},
+		{
+			name:     "convert subscript and superscript",
+			mode:     HTMLConvert,
+			markdown: "H<sub>2</sub>O and x<SUP>2</SUP>",
+			want:     "H~2~O and x^2^",
+		},
+		{
+			name:     "convert image and unknown tags",
+			mode:     HTMLConvert,
+			markdown: `<span>see</span> <img src="a.png" alt="A">`,
+			want:     "see !a.png!",
+		},
+		{
+			name: "convert block keeps its text",
+			mode: HTMLConvert,
+			markdown: "para\n\n<div>\n<strong>hi</strong> <code>x</code>\n</div>\n\n" +
+				"<!-- hidden -->\n\nend",
+			want: "para\n\n*hi* {{x}}\n\nend",
+		},
+		{
+			name:     "passthrough",
+			mode:     HTMLPassthrough,
+			markdown: "<div>\n<b>hi</b>\n</div>",
+			want:     "<div>\n<b>hi</b>\n</div>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToJiraWithOptions(tt.markdown, Options{HTML: tt.mode})
+			if got != tt.want {
+				t.Errorf("ToJiraWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,25 +2,60 @@ package markdown
 
 import (
 	"bytes"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/appleboy/com/bytesconv"
+	"github.com/appleboy/go-jira/pkg/issuekey"
 	bf "github.com/russross/blackfriday/v2"
 )
 
+var (
+	// macroParamStripper removes the "|" and "}" that would end a macro
+	// parameter, or the macro, early.
+	macroParamStripper = strings.NewReplacer("|", "", "}", "")
+	// linkTextStripper removes the "|" and "]" that would end the text,
+	// URL, or tooltip of a link early.
+	linkTextStripper = strings.NewReplacer("|", "", "]", "")
+	// imageAttrStripper removes the "|", "!", and "," that would end an
+	// image attribute, or the image, early.
+	imageAttrStripper = strings.NewReplacer("|", "", "!", "", ",", "")
+)
+
+// JiraRenderer converts Markdown to Jira markup. Render is safe for
+// concurrent use: each conversion walks the document with a JiraRenderer of
+// its own, which holds the state of that walk. RenderNode, which the walk
+// calls, updates that state, so it must not be shared between walks.
 type JiraRenderer struct {
-	builder strings.Builder
+	// out is the buffer a walk of writeBlocks writes to.
+	out bytes.Buffer
 	// listOrdered tracks the ordered-ness of each currently open list level so
 	// nested lists pick the right Jira marker ('#' ordered, '*' bullet). Its
 	// length is the current nesting depth, and len > 0 means "inside a list".
 	listOrdered []bool
+	// htmlLinks holds the hrefs of the HTML <a> tags open in HTMLConvert
+	// mode.
+	htmlLinks []string
+	// quoteMacros holds, for each open blockquote, the Jira macro it renders
+	// as, or "" for a plain blockquote.
+	quoteMacros []string
+	// anchors holds the heading IDs that "#id" links point to, which get a
+	// Jira {anchor} to land on.
+	anchors map[string]bool
+	opts    Options
 }
 
-func NewJiraRenderer() *JiraRenderer {
-	return &JiraRenderer{
-		builder: strings.Builder{},
-	}
+// NewJiraRenderer returns a renderer configured by opts.
+func NewJiraRenderer(opts ...Option) *JiraRenderer {
+	return &JiraRenderer{opts: NewOptions(opts...)}
+}
+
+// Render converts markdown to Jira markup with the options of r.
+func (r *JiraRenderer) Render(markdown string) string {
+	return ToJiraWithOptions(markdown, r.opts)
 }
 
 func (r *JiraRenderer) RenderNode(w *bytes.Buffer, node *bf.Node, entering bool) bf.WalkStatus {
@@ -31,13 +66,15 @@ func (r *JiraRenderer) RenderNode(w *bytes.Buffer, node *bf.Node, entering bool)
 		r.renderHorizontalRule(w, node, entering)
 	case bf.Image:
 		r.renderImage(w, node, entering)
-		// Skip the image's child nodes (the alt text): Jira image markup is
-		// `!url!` with no display-text slot, so rendering the alt corrupts it.
+		// Skip the image's child nodes (the alt text): Jira image markup has
+		// no display-text slot, so renderImage emits the alt as an attribute.
 		if entering {
 			return bf.SkipChildren
 		}
-	case bf.HTMLBlock, bf.HTMLSpan:
-		// Ignore HTML blocks and spans
+	case bf.HTMLBlock:
+		r.renderHTMLBlock(w, node, entering)
+	case bf.HTMLSpan:
+		r.renderHTMLSpan(w, node, entering)
 	case bf.Softbreak:
 		r.renderSoftbreak(w, node, entering)
 	case bf.Table, bf.TableCell, bf.TableHead, bf.TableBody, bf.TableRow:
@@ -55,6 +92,12 @@ func (r *JiraRenderer) RenderNode(w *bytes.Buffer, node *bf.Node, entering bool)
 	case bf.Emph:
 		r.renderEmph(w, node, entering)
 	case bf.Link:
+		// An autolink's text is its URL, which must stay verbatim: emphasis
+		// or mention handling would corrupt its query string.
+		if entering && isAutolink(node) {
+			r.renderAutolink(w, node)
+			return bf.SkipChildren
+		}
 		r.renderLink(w, node, entering)
 	case bf.List:
 		r.renderList(w, node, entering)
@@ -72,28 +115,81 @@ func (r *JiraRenderer) RenderNode(w *bytes.Buffer, node *bf.Node, entering bool)
 	return bf.GoToNext
 }
 
-func (r *JiraRenderer) renderBlockQuote(_ *bytes.Buffer, _ *bf.Node, _ bool) {
-	// Handle BlockQuote
-}
-
 func (r *JiraRenderer) renderHorizontalRule(w *bytes.Buffer, _ *bf.Node, _ bool) {
 	w.WriteString("----\n")
 }
 
+// imageSizePattern matches the " =300x200" size hint after an image URL,
+// either dimension optional, capturing the width and height.
+var imageSizePattern = regexp.MustCompile(`\s+=(\d*)(?:x(\d*))?$`)
+
 func (r *JiraRenderer) renderImage(w *bytes.Buffer, node *bf.Node, entering bool) {
-	// Jira embeds images as `!url!`; the part after a `|` is reserved for
-	// attributes (width, align, thumbnail), not alt text. The alt-text child is
+	// Jira embeds images as `!url|attr=value,...!`. The alt-text child is
 	// skipped by RenderNode, so the whole token is emitted on entering.
 	if !entering {
 		return
 	}
+	dest := string(node.Destination)
+	var attrs []string
+	if m := imageSizePattern.FindStringSubmatch(dest); m != nil && m[1]+m[2] != "" {
+		if m[1] != "" {
+			attrs = append(attrs, "width="+m[1])
+		}
+		if m[2] != "" {
+			attrs = append(attrs, "height="+m[2])
+		}
+		dest = strings.TrimSuffix(dest, m[0])
+	}
+	alt := imageAttrStripper.Replace(nodeText(node))
+	if alt = strings.TrimSpace(alt); alt != "" {
+		attrs = append([]string{"alt=" + alt}, attrs...)
+	}
 	w.WriteString("!")
-	w.Write(node.Destination)
+	if r.opts.ImagesAttached && isFilename(dest) {
+		w.WriteString(dest)
+	} else {
+		w.WriteString(r.resolveURL(dest))
+	}
+	if len(attrs) > 0 {
+		w.WriteString("|")
+		w.WriteString(strings.Join(attrs, ","))
+	}
 	w.WriteString("!")
 }
 
+// nodeText returns the text and code literals under node.
+func nodeText(node *bf.Node) string {
+	var b strings.Builder
+	node.Walk(func(n *bf.Node, entering bool) bf.WalkStatus {
+		if entering && (n.Type == bf.Text || n.Type == bf.Code) {
+			b.Write(n.Literal)
+		}
+		return bf.GoToNext
+	})
+	return b.String()
+}
+
+// isFilename reports whether dest is a bare file name, with no scheme,
+// directory, query, or fragment, which the ImagesAttached option reads as an
+// attachment of the issue.
+func isFilename(dest string) bool {
+	return dest != "" && !strings.ContainsAny(dest, "/\\:?#")
+}
+
 func (r *JiraRenderer) renderSoftbreak(w *bytes.Buffer, _ *bf.Node, _ bool) {
-	w.WriteString(" ")
+	w.WriteString(r.lineBreaks("\n"))
+}
+
+// lineBreaks renders the newlines in text, the line breaks of a paragraph,
+// in the LineBreaks style.
+func (r *JiraRenderer) lineBreaks(text string) string {
+	switch r.opts.LineBreaks {
+	case LineBreakSpace:
+		return strings.ReplaceAll(text, "\n", " ")
+	case LineBreakHard:
+		return strings.ReplaceAll(text, "\n", `\\`)
+	}
+	return text
 }
 
 func (r *JiraRenderer) renderParagraph(w *bytes.Buffer, _ *bf.Node, entering bool) {
@@ -112,16 +208,106 @@ func (r *JiraRenderer) renderHeading(w *bytes.Buffer, node *bf.Node, entering bo
 			w.WriteString("\n")
 		}
 		w.WriteString("h")
-		w.WriteString(strconv.Itoa(node.Level))
+		w.WriteString(strconv.Itoa(min(max(node.Level+r.opts.HeadingOffset, 1), 6)))
 		w.WriteString(". ")
+		if r.anchors[node.HeadingID] {
+			w.WriteString("{anchor:")
+			w.WriteString(node.HeadingID)
+			w.WriteString("}")
+		}
 		return
 	}
 	w.WriteString("\n")
 }
 
 func (r *JiraRenderer) renderText(w *bytes.Buffer, node *bf.Node, _ bool) {
-	text := r.convertMentions(bytesconv.BytesToStr(node.Literal))
-	w.WriteString(text)
+	literal := node.Literal
+	if symbol, n := taskMarker(node); n > 0 {
+		w.WriteString(symbol)
+		literal = literal[n:]
+	}
+	text := bytesconv.BytesToStr(literal)
+	last := 0
+	var prev byte
+	for _, m := range r.issueKeys(node, text) {
+		r.writeMentions(w, text[last:m.Start], prev, true)
+		w.WriteString("[")
+		w.WriteString(m.Key)
+		w.WriteString("|")
+		w.WriteString(r.opts.IssueURL)
+		w.WriteString(m.Key)
+		w.WriteString("]")
+		last = m.End
+		prev = text[last-1]
+	}
+	r.writeMentions(w, text[last:], prev, true)
+}
+
+// writePlain writes text, which holds no mentions, to w, with its emoji and
+// line breaks converted when convert is set.
+func (r *JiraRenderer) writePlain(w *bytes.Buffer, text string, convert bool) {
+	if !convert {
+		w.WriteString(text)
+		return
+	}
+	writeEmoji(w, r.lineBreaks(text))
+}
+
+// issueKeys returns the issue keys in text, from node, to link with the
+// IssueURL option. Keys already inside a link, glued to a preceding word or
+// "@", or in issuekey.Denylist are left alone.
+func (r *JiraRenderer) issueKeys(node *bf.Node, text string) []issuekey.Match {
+	if r.opts.IssueURL == "" {
+		return nil
+	}
+	for p := node.Parent; p != nil; p = p.Parent {
+		if p.Type == bf.Link {
+			return nil
+		}
+	}
+	re := r.opts.IssuePattern
+	if re == nil {
+		re = issuekey.DefaultPattern
+	}
+	matches := issuekey.FindAll(text, re)
+	keys := matches[:0]
+	for _, m := range matches {
+		if m.Start > 0 && (isValidMentionChar(text[m.Start-1]) || text[m.Start-1] == '@') ||
+			issuekey.Denylist[m.Key] {
+			continue
+		}
+		keys = append(keys, m)
+	}
+	return keys
+}
+
+// Jira symbols for the checkboxes of GitHub task list items.
+const (
+	taskDone = "(/)"
+	taskTodo = "( )"
+)
+
+// taskMarker reports whether node, a text node, starts a GitHub task list
+// item ("- [x] done", "- [ ] todo"), which blackfriday leaves as plain text.
+// It returns the Jira symbol for the checkbox and the length of the "[x]"
+// prefix to replace, or 0 when node is not a task list checkbox.
+func taskMarker(node *bf.Node) (string, int) {
+	para := node.Parent
+	if node.Prev != nil || para == nil || para.Type != bf.Paragraph || para.Prev != nil ||
+		para.Parent == nil || para.Parent.Type != bf.Item {
+		return "", 0
+	}
+	lit := node.Literal
+	if len(lit) < 3 || lit[0] != '[' || lit[2] != ']' || (len(lit) > 3 && lit[3] != ' ') {
+		return "", 0
+	}
+	switch lit[1] {
+	case 'x', 'X':
+		return taskDone, 3
+	case ' ':
+		return taskTodo, 3
+	}
+	return "", 0
 }
 
 func (r *JiraRenderer) renderStrong(w *bytes.Buffer, _ *bf.Node, _ bool) {
@@ -133,16 +319,89 @@ func (r *JiraRenderer) renderEmph(w *bytes.Buffer, _ *bf.Node, _ bool) {
 }
 
 func (r *JiraRenderer) renderLink(w *bytes.Buffer, node *bf.Node, entering bool) {
+	if node.NoteID > 0 {
+		// A footnote reference, numbered like its footnote at the end.
+		if entering {
+			w.WriteString("^")
+			w.WriteString(strconv.Itoa(node.NoteID))
+			w.WriteString("^")
+		}
+		return
+	}
 	if entering {
 		w.WriteString("[")
 		return
 	}
 	w.WriteString("|")
+	w.WriteString(r.resolveURL(bytesconv.BytesToStr(node.Destination)))
+	if len(node.Title) > 0 {
+		w.WriteString("|")
+		w.WriteString(linkTextStripper.Replace(bytesconv.BytesToStr(node.Title)))
+	}
+	w.WriteString("]")
+}
+
+// resolveURL returns dest resolved against the BaseURL option when dest is
+// relative. Absolute URLs, "#anchor" links, and unparsable URLs are returned
+// unchanged.
+func (r *JiraRenderer) resolveURL(dest string) string {
+	if r.opts.BaseURL == "" || dest == "" || dest[0] == '#' {
+		return dest
+	}
+	ref, err := url.Parse(dest)
+	if err != nil || ref.IsAbs() || ref.Host != "" {
+		return dest
+	}
+	base, err := url.Parse(strings.TrimSuffix(r.opts.BaseURL, "/") + "/")
+	if err != nil {
+		return dest
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// linkedAnchors returns the IDs that the "#id" links in the document point
+// to.
+func linkedAnchors(doc *bf.Node) map[string]bool {
+	anchors := map[string]bool{}
+	doc.Walk(func(node *bf.Node, entering bool) bf.WalkStatus {
+		if entering && node.Type == bf.Link && node.NoteID == 0 &&
+			len(node.Destination) > 1 && node.Destination[0] == '#' {
+			anchors[string(node.Destination[1:])] = true
+		}
+		return bf.GoToNext
+	})
+	return anchors
+}
+
+// isAutolink reports whether node, a link, is a bare URL or a <url> or
+// <email> autolink, whose only text is its destination.
+func isAutolink(node *bf.Node) bool {
+	text := node.FirstChild
+	if node.NoteID > 0 || text == nil || text.Next != nil || text.Type != bf.Text {
+		return false
+	}
+	dest := bytesconv.BytesToStr(node.Destination)
+	lit := bytesconv.BytesToStr(text.Literal)
+	return lit == dest || "mailto:"+lit == dest
+}
+
+func (r *JiraRenderer) renderAutolink(w *bytes.Buffer, node *bf.Node) {
+	w.WriteString("[")
+	w.Write(node.FirstChild.Literal)
+	w.WriteString("|")
 	w.Write(node.Destination)
 	w.WriteString("]")
 }
 
 func (r *JiraRenderer) renderList(w *bytes.Buffer, node *bf.Node, entering bool) {
+	if entering && node.IsFootnotesList {
+		// The footnotes follow the document below a rule, each item starting
+		// with its number.
+		if w.Len() > 0 {
+			w.WriteString("\n")
+		}
+		w.WriteString("----\n")
+	}
 	if entering {
 		r.listOrdered = append(r.listOrdered, node.ListFlags&bf.ListTypeOrdered != 0)
 		return
@@ -155,10 +414,23 @@ func (r *JiraRenderer) renderList(w *bytes.Buffer, node *bf.Node, entering bool)
 	}
 }
 
-func (r *JiraRenderer) renderItem(w *bytes.Buffer, _ *bf.Node, entering bool) {
+func (r *JiraRenderer) renderItem(w *bytes.Buffer, node *bf.Node, entering bool) {
 	if !entering {
 		return
 	}
+	if node.Parent != nil && node.Parent.IsFootnotesList {
+		n := 1
+		for prev := node.Prev; prev != nil; prev = prev.Prev {
+			n++
+		}
+		if b := w.Bytes(); len(b) > 0 && b[len(b)-1] != '\n' {
+			w.WriteString("\n")
+		}
+		w.WriteString("^")
+		w.WriteString(strconv.Itoa(n))
+		w.WriteString("^ ")
+		return
+	}
 	// One marker per open level, e.g. a bullet nested under an ordered list is
 	// "*#". Jira uses '#' for ordered items and '*' for bullets.
 	indent := make([]byte, len(r.listOrdered))
@@ -182,23 +454,6 @@ func (r *JiraRenderer) renderCode(w *bytes.Buffer, node *bf.Node, _ bool) {
 	w.WriteString("}}")
 }
 
-func (r *JiraRenderer) renderCodeBlock(w *bytes.Buffer, node *bf.Node, entering bool) {
-	if entering {
-		language := string(node.Info)
-		if language == "" {
-			language = "java"
-		}
-		if w.Len() > 0 {
-			w.WriteString("\n")
-		}
-		w.WriteString("{code:language=")
-		w.WriteString(language)
-		w.WriteString("}\n")
-		w.Write(node.Literal)
-		w.WriteString("{code}")
-	}
-}
-
 func (r *JiraRenderer) renderHardbreak(w *bytes.Buffer, _ *bf.Node, _ bool) {
 	w.WriteString("\n")
 }
@@ -207,37 +462,112 @@ func (r *JiraRenderer) renderDel(w *bytes.Buffer, _ *bf.Node, _ bool) {
 	w.WriteString("-")
 }
 
-func (r *JiraRenderer) convertMentions(text string) string {
+// convertMentions converts the @mentions in text, which follows the byte
+// prev, or 0 at the start of a text node, as the Mentions option asks.
+func (r *JiraRenderer) convertMentions(text string, prev byte) string {
 	// check the text include @ syntax
-	if !strings.Contains(text, "@") {
+	if r.opts.MentionMode == MentionOff || !strings.Contains(text, "@") {
 		return text
 	}
+	var b bytes.Buffer
+	b.Grow(len(text) + strings.Count(text, "@")*2) // Preallocate buffer with an initial capacity
+	r.writeMentions(&b, text, prev, false)
+	return bytesconv.BytesToStr(b.Bytes())
+}
 
-	count := strings.Count(text, "@")
+// writeMentions writes text to w with its @mentions converted, and with the
+// emoji and line breaks of the text between them too when plain is set.
+// prev is the byte before text, or 0.
+func (r *JiraRenderer) writeMentions(w *bytes.Buffer, text string, prev byte, plain bool) {
+	if r.opts.MentionMode == MentionOff || !strings.Contains(text, "@") {
+		r.writePlain(w, text, plain)
+		return
+	}
 	length := len(text)
-	r.builder.Reset()
-	r.builder.Grow(length + count*2) // Preallocate buffer with an initial capacity
+	last := 0
 	for i := 0; i < length; i++ {
-		// Require a left boundary so an '@' embedded in a word (e.g. an email
-		// address such as user@example.com) is not mistaken for a mention.
-		if text[i] == '@' && (i == 0 || !isValidMentionChar(text[i-1])) &&
-			i+1 < length && isValidMentionChar(text[i+1]) {
-			r.builder.WriteString("[~")
-			i++
-			for i < length && isValidMentionChar(text[i]) {
-				r.builder.WriteByte(text[i])
-				i++
-			}
-			r.builder.WriteString("]")
-			if i < length {
-				r.builder.WriteByte(text[i])
+		if i > 0 {
+			prev = text[i-1]
+		}
+		if text[i] != '@' || !r.mentionBoundary(prev) ||
+			i+1 >= length || !isValidMentionChar(text[i+1]) {
+			continue
+		}
+		if email := emailMentionPattern.FindString(text[i+1:]); email != "" {
+			// An address no user is found for stays as text.
+			if user, ok := r.emailUser(email); ok {
+				r.writePlain(w, text[last:i], plain)
+				w.WriteString("[~")
+				w.WriteString(user)
+				w.WriteString("]")
+				last = i + 1 + len(email)
 			}
+			i += len(email)
 			continue
 		}
-		// copy the character
-		r.builder.WriteByte(text[i])
+		start := i + 1
+		i++
+		for i < length && isValidMentionChar(text[i]) {
+			i++
+		}
+		r.writePlain(w, text[last:start-1], plain)
+		w.WriteString("[~")
+		w.WriteString(r.mentionUser(text[start:i]))
+		w.WriteString("]")
+		// The byte after the name is never a mention of its own; the loop
+		// skips it, leaving it to the next plain text.
+		last = i
+	}
+	r.writePlain(w, text[last:], plain)
+}
+
+// mentionBoundary reports whether an '@' after the byte prev, 0 at the start
+// of a text node, may start a mention. A left boundary is required so an '@'
+// embedded in a word (e.g. an email address such as user@example.com) is
+// not mistaken for a mention; MentionStrict requires whitespace.
+func (r *JiraRenderer) mentionBoundary(prev byte) bool {
+	if r.opts.MentionMode == MentionStrict {
+		return prev == 0 || prev == ' ' || prev == '\t' || prev == '\n'
+	}
+	return prev == 0 || !isValidMentionChar(prev)
+}
+
+// emailMentionPattern matches the e-mail address of an @user@example.com
+// mention, after its '@'.
+var emailMentionPattern = regexp.MustCompile(`^[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+`)
+
+// mentionUser returns the Jira user the @mention of name links to.
+func (r *JiraRenderer) mentionUser(name string) string {
+	if user, ok := r.mappedUser(name); ok {
+		return user
+	}
+	return name
+}
+
+// emailUser returns the Jira user the @mention of email links to, from the
+// Mentions option or else the ResolveEmail one. An unresolved address is
+// left as text, since Jira has no user named after it.
+func (r *JiraRenderer) emailUser(email string) (string, bool) {
+	if user, ok := r.mappedUser(email); ok {
+		return user, true
+	}
+	if r.opts.ResolveEmail == nil {
+		return "", false
+	}
+	return r.opts.ResolveEmail(email)
+}
+
+// mappedUser looks name up in the Mentions option, case-insensitively.
+func (r *JiraRenderer) mappedUser(name string) (string, bool) {
+	if user, ok := r.opts.Mentions[name]; ok {
+		return user, true
+	}
+	for git, user := range r.opts.Mentions {
+		if strings.EqualFold(git, name) {
+			return user, true
+		}
 	}
-	return r.builder.String()
+	return "", false
 }
 
 // MarkdownToJira converts a given Markdown string to Jira markup format.
@@ -246,23 +576,130 @@ func (r *JiraRenderer) convertMentions(text string) string {
 //
 // Parameters:
 //   - markdown: A string containing the Markdown content to be converted.
+//   - opts: Options configuring the renderer, such as WithHTML; without any
+//     it renders with the zero Options.
 //
 // Returns:
 //
 //	A string containing the converted content in Jira markup format.
-func ToJira(markdown string) string {
-	extensions := bf.CommonExtensions | bf.AutoHeadingIDs
-	md := bf.New(bf.WithExtensions(extensions))
+func ToJira(markdown string, opts ...Option) string {
+	return ToJiraWithOptions(markdown, NewOptions(opts...))
+}
+
+// ToJiraWithOptions is ToJira with the renderer configured by the opts
+// struct.
+func ToJiraWithOptions(markdown string, opts Options) string {
+	var b bytes.Buffer
+	b.Grow(len(markdown)) // Jira markup is about as long as its Markdown
+	// Writes to a bytes.Buffer do not fail.
+	_ = convert(&partWriter{w: &b}, markdown, opts)
+	return bytesconv.BytesToStr(b.Bytes())
+}
+
+// convert writes markdown converted with opts to p, returning the first
+// error writing it.
+func convert(p *partWriter, markdown string, opts Options) error {
+	markdown = convertColorSpans(markdown)
+	var anchors map[string]bool
+	if opts.TOC != TOCNone {
+		headings := documentHeadings(markdown)
+		if opts.TOC == TOCList {
+			// The listed headings need an {anchor} to link to.
+			anchors = make(map[string]bool, len(headings))
+			for _, h := range headings {
+				anchors[h.id] = true
+			}
+		}
+		// The headings render, so the table of contents never stands alone.
+		p.write(bytesconv.StrToBytes(tableOfContents(headings, opts.TOC)))
+	}
+	writeMarkdown(p, markdown, opts, anchors)
+	return p.err
+}
+
+// markdownExtensions are the blackfriday extensions the Markdown is parsed
+// with.
+const markdownExtensions = bf.CommonExtensions | bf.AutoHeadingIDs | bf.Footnotes
+
+// renderMarkdown converts markdown with opts, giving the headings with the
+// IDs in anchors an {anchor}.
+func renderMarkdown(markdown string, opts Options, anchors map[string]bool) string {
+	var b bytes.Buffer
+	writeMarkdown(&partWriter{w: &b}, markdown, opts, anchors)
+	return b.String()
+}
+
+// writeMarkdown writes markdown converted with opts to p, giving the
+// headings with the IDs in anchors an {anchor}. Blackfriday has no syntax
+// for admonitions, so they are split out first and each rendered around its
+// own converted body.
+func writeMarkdown(p *partWriter, markdown string, opts Options, anchors map[string]bool) {
+	for _, s := range splitAdmonitions(markdown) {
+		if p.err != nil {
+			return
+		}
+		if s.admonition != nil {
+			p.write(bytesconv.StrToBytes(renderAdmonition(s.admonition, opts)))
+		} else {
+			writeBlocks(p, s.markdown, opts, anchors)
+		}
+	}
+}
+
+// writeBlocks writes markdown, which holds no admonitions, converted to p,
+// giving the headings with the IDs in anchors, or linked to, an {anchor}.
+func writeBlocks(p *partWriter, markdown string, opts Options, anchors map[string]bool) {
+	md := bf.New(bf.WithExtensions(markdownExtensions))
 
 	ast := md.Parse(bytesconv.StrToBytes(markdown))
 
-	buf := bytes.NewBuffer(make([]byte, 0, 512)) // Preallocate buffer with an initial capacity
-	renderer := NewJiraRenderer()
+	// A renderer of its own keeps the walk state out of reach of concurrent
+	// conversions.
+	renderer := getRenderer(opts, linkedAnchors(ast))
+	defer putRenderer(renderer)
+	for id := range anchors {
+		renderer.anchors[id] = true
+	}
+	buf := &renderer.out
+	buf.Grow(len(markdown)) // Jira markup is about as long as its Markdown
 	ast.Walk(func(node *bf.Node, entering bool) bf.WalkStatus {
 		return renderer.RenderNode(buf, node, entering)
 	})
 
-	return strings.TrimSpace(bytesconv.BytesToStr(buf.Bytes()))
+	p.write(bytes.TrimSpace(buf.Bytes()))
+}
+
+// rendererPool holds the renderers of finished walks, so that converting
+// one document after another reuses their buffers.
+var rendererPool = sync.Pool{
+	New: func() any { return new(JiraRenderer) },
+}
+
+// maxPooledBuffer is the largest output buffer put back in rendererPool, so
+// one huge document does not keep its memory alive.
+const maxPooledBuffer = 1 << 20
+
+// getRenderer returns a renderer from rendererPool for a walk with opts,
+// giving the headings with the IDs in anchors an {anchor}.
+func getRenderer(opts Options, anchors map[string]bool) *JiraRenderer {
+	r := rendererPool.Get().(*JiraRenderer)
+	r.opts = opts
+	r.anchors = anchors
+	return r
+}
+
+// putRenderer returns r to rendererPool once its walk is done.
+func putRenderer(r *JiraRenderer) {
+	if r.out.Cap() > maxPooledBuffer {
+		return
+	}
+	r.out.Reset()
+	r.listOrdered = r.listOrdered[:0]
+	r.htmlLinks = r.htmlLinks[:0]
+	r.quoteMacros = r.quoteMacros[:0]
+	r.anchors = nil
+	r.opts = Options{}
+	rendererPool.Put(r)
 }
 
 var validMentionChars [256]bool
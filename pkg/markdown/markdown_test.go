@@ -1,6 +1,10 @@
 package markdown
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -45,6 +49,11 @@ func TestMarkdownToJira(t *testing.T) {
 			markdown: "```go\ncode block\n```",
 			want:     "{code:language=go}\ncode block\n{code}",
 		},
+		{
+			name:     "code block without language",
+			markdown: "```\nplain output\n```",
+			want:     "{noformat}\nplain output\n{noformat}",
+		},
 		{
 			name:     "inline code",
 			markdown: "`inline code`",
@@ -55,6 +64,11 @@ func TestMarkdownToJira(t *testing.T) {
 			markdown: "~~strikethrough~~",
 			want:     "-strikethrough-",
 		},
+		{
+			name:     "subscript and superscript",
+			markdown: "H~2~O, E=mc^2^ and ~~gone~~",
+			want:     "H~2~O, E=mc^2^ and -gone-",
+		},
 		{
 			name:     "custom comment",
 			markdown: `🧑‍💻 @appleboy push code to repository {color:#ff8b00}**davinci/rag-service**{color} {color:#00875A}**refs/heads/GAIS-4223**{color} branch.\n\nSee the detailed information from [commit link](http://exampl.com).\n\nimprove logging and error handling for PDF page count validation`,
@@ -90,10 +104,74 @@ func TestMarkdownToJira(t *testing.T) {
 			markdown: "* a\n  1. one\n  2. two",
 			want:     "* a\n*# one\n*# two",
 		},
+		{
+			name:     "bullet list nested under ordered",
+			markdown: "1. one\n   - a\n   - b\n2. two",
+			want:     "# one\n#* a\n#* b\n# two",
+		},
+		{
+			name:     "three mixed levels",
+			markdown: "- a\n    1. one\n        - x\n            1. deep\n    2. two\n- b",
+			want:     "* a\n*# one\n*#* x\n*#*# deep\n*# two\n* b",
+		},
+		{
+			name:     "link with a title",
+			markdown: `[example](http://example.com "Home]|page")`,
+			want:     "[example|http://example.com|Homepage]",
+		},
+		{
+			name:     "anchor link",
+			markdown: "## Getting Started\n\n## Usage\n\nSee [setup](#getting-started).",
+			want: "h2. {anchor:getting-started}Getting Started\n\nh2. Usage\n\n" +
+				"See [setup|#getting-started].",
+		},
+		{
+			name:     "bare URL",
+			markdown: "See https://example.com/a_b_c?x=1&user=@bob now",
+			want: "See [https://example.com/a_b_c?x=1&user=@bob|" +
+				"https://example.com/a_b_c?x=1&user=@bob] now",
+		},
+		{
+			name:     "autolinks",
+			markdown: "<https://x.io/q?a_b=1> or <dev@example.com>",
+			want: "[https://x.io/q?a_b=1|https://x.io/q?a_b=1] or " +
+				"[dev@example.com|mailto:dev@example.com]",
+		},
+		{
+			name:     "footnotes",
+			markdown: "Text[^1] and more[^note].\n\n[^1]: First *note*.\n[^note]: Second\n\nAfter.",
+			want:     "Text^1^ and more^2^.\n\nAfter.\n\n----\n^1^ First _note_.\n^2^ Second",
+		},
+		{
+			name:     "task list",
+			markdown: "- [x] done\n- [ ] todo\n- [X] shipped by @appleboy",
+			want:     "* (/) done\n* ( ) todo\n* (/) shipped by [~appleboy]",
+		},
+		{
+			name:     "nested task list",
+			markdown: "1. release\n   - [x] tag\n   - [ ] announce",
+			want:     "# release\n#* (/) tag\n#* ( ) announce",
+		},
+		{
+			name:     "brackets outside a list item are kept",
+			markdown: "[x] not a task",
+			want:     "[x] not a task",
+		},
+		{
+			name:     "emoji shortcodes",
+			markdown: "## :rocket: Release\n\n:white_check_mark: tests passed, `:warning:` kept",
+			want:     "h2. 🚀 Release\n\n(/) tests passed, {{:warning:}} kept",
+		},
 		{
 			name:     "image",
 			markdown: "![alt text](http://example.com/a.png)",
-			want:     "!http://example.com/a.png!",
+			want:     "!http://example.com/a.png|alt=alt text!",
+		},
+		{
+			name:     "image size",
+			markdown: "![](a.png =300x) ![x](b.png =x80) ![Fig, 1|2](c.png =300x200 \"t\")",
+			want: "!a.png|width=300! !b.png|alt=x,height=80! " +
+				"!c.png|alt=Fig 12,width=300,height=200!",
 		},
 	}
 
@@ -178,7 +256,7 @@ func TestConvertMentions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := r.convertMentions(tt.text)
+			got := r.convertMentions(tt.text, 0)
 			if got != tt.want {
 				t.Errorf("ConvertMentions() = %v, want %v", got, tt.want)
 			}
@@ -250,7 +328,7 @@ func BenchmarkConvertMentions(b *testing.B) {
 		b.ResetTimer()
 		text := "Hello @user! How are you?"
 		for i := 0; i < b.N; i++ {
-			_ = r.convertMentions(text)
+			_ = r.convertMentions(text, 0)
 		}
 	})
 	b.Run("complex", func(b *testing.B) {
@@ -258,7 +336,7 @@ func BenchmarkConvertMentions(b *testing.B) {
 		b.ResetTimer()
 		text := "Hello @user! How are you? How are you?How are you?How are you?How are you?How are you?How are you?"
 		for i := 0; i < b.N; i++ {
-			_ = r.convertMentions(text)
+			_ = r.convertMentions(text, 0)
 		}
 	})
 	b.Run("no mention", func(b *testing.B) {
@@ -266,7 +344,7 @@ func BenchmarkConvertMentions(b *testing.B) {
 		b.ResetTimer()
 		text := "Hello How are you? How are you?How are you?How are you?How are you?How are you?How are you?"
 		for i := 0; i < b.N; i++ {
-			_ = r.convertMentions(text)
+			_ = r.convertMentions(text, 0)
 		}
 	})
 }
@@ -298,3 +376,259 @@ func main() {
 		ToJira(markdown)
 	}
 }
+
+// releaseNotes returns Markdown release notes with n sections, a few
+// kilobytes each, like the generated changelogs converted per issue.
+func releaseNotes(n int) string {
+	const section = `## Release %d
+
+Thanks to @octocat and @hubot for this release, which fixes GAIA-%d and
+improves the **login** flow :tada:. See the [changelog](docs/CHANGELOG.md).
+
+* Fix _token_ refresh ([#12](https://github.com/appleboy/go-jira/pull/12))
+* Add ` + "`--markdown`" + ` support
+  1. headings
+  2. lists with [links](http://example.com)
+* Bump dependencies
+
+> [!NOTE]
+> Upgrade notes for @octocat live in the wiki.
+
+| Key | Status |
+| --- | ------ |
+| GAIA-1 | Done |
+
+` + "```go" + `
+func main() {
+	fmt.Println("Hello, World!")
+}
+` + "```" + `
+
+`
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, section, i, i)
+	}
+	return b.String()
+}
+
+func BenchmarkToJiraLarge(b *testing.B) {
+	for _, n := range []int{10, 100} {
+		markdown := releaseNotes(n)
+		b.Run(strconv.Itoa(len(markdown)/1024)+"KiB", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(markdown)))
+			for i := 0; i < b.N; i++ {
+				ToJira(markdown)
+			}
+		})
+	}
+}
+
+func BenchmarkToJiraParallel(b *testing.B) {
+	markdown := releaseNotes(10)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(markdown)))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ToJira(markdown)
+		}
+	})
+}
+
+func TestToJiraHeadingOffset(t *testing.T) {
+	src := "# Release\n\n## Fixes\n\n###### Details"
+	tests := []struct {
+		offset int
+		want   string
+	}{
+		{0, "h1. Release\n\nh2. Fixes\n\nh6. Details"},
+		{2, "h3. Release\n\nh4. Fixes\n\nh6. Details"},
+		{-1, "h1. Release\n\nh1. Fixes\n\nh5. Details"},
+	}
+	for _, tt := range tests {
+		if got := ToJiraWithOptions(src, Options{HeadingOffset: tt.offset}); got != tt.want {
+			t.Errorf("offset %d: ToJiraWithOptions() = %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestToJiraLineBreaks(t *testing.T) {
+	src := "Fix login\nand **logout**\n\n* first\n  line"
+	tests := []struct {
+		style LineBreakStyle
+		want  string
+	}{
+		{LineBreakPreserve, "Fix login\nand *logout*\n* first\nline"},
+		{LineBreakSpace, "Fix login and *logout*\n* first line"},
+		{LineBreakHard, "Fix login\\\\and *logout*\n* first\\\\line"},
+	}
+	for _, tt := range tests {
+		if got := ToJiraWithOptions(src, Options{LineBreaks: tt.style}); got != tt.want {
+			t.Errorf("style %d: ToJiraWithOptions() = %q, want %q", tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestToJiraBaseURL(t *testing.T) {
+	opts := Options{BaseURL: "https://github.com/appleboy/go-jira/blob/main"}
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "relative link",
+			markdown: "[setup](./docs/setup.md)",
+			want:     "[setup|https://github.com/appleboy/go-jira/blob/main/docs/setup.md]",
+		},
+		{
+			name:     "relative image",
+			markdown: "![logo](images/logo.png)",
+			want:     "!https://github.com/appleboy/go-jira/blob/main/images/logo.png|alt=logo!",
+		},
+		{
+			name:     "image with a size",
+			markdown: "![logo](images/logo.png =120x)",
+			want: "!https://github.com/appleboy/go-jira/blob/main/images/logo.png" +
+				"|alt=logo,width=120!",
+		},
+		{
+			name:     "parent directory",
+			markdown: "[license](../main/LICENSE)",
+			want:     "[license|https://github.com/appleboy/go-jira/blob/main/LICENSE]",
+		},
+		{
+			name:     "absolute link",
+			markdown: "[example](http://example.com/x)",
+			want:     "[example|http://example.com/x]",
+		},
+		{
+			name:     "anchor",
+			markdown: "[usage](#usage)",
+			want:     "[usage|#usage]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJiraWithOptions(tt.markdown, opts); got != tt.want {
+				t.Errorf("ToJiraWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJiraIssueLinks(t *testing.T) {
+	opts := Options{IssueURL: "https://jira.example.com/browse/"}
+	tests := []struct {
+		name     string
+		markdown string
+		opts     Options
+		want     string
+	}{
+		{
+			name:     "bare by default",
+			markdown: "Fixes GAIA-1",
+			want:     "Fixes GAIA-1",
+		},
+		{
+			name:     "linked",
+			markdown: "Fixes GAIA-1 and **GAIA-22** for @bob",
+			opts:     opts,
+			want: "Fixes [GAIA-1|https://jira.example.com/browse/GAIA-1] and " +
+				"*[GAIA-22|https://jira.example.com/browse/GAIA-22]* for [~bob]",
+		},
+		{
+			name:     "noise is left alone",
+			markdown: "UTF-8 in xGAIA-1 by @GAIA-2, see [GAIA-3](http://x.io) and `GAIA-4`",
+			opts:     opts,
+			want:     "UTF-8 in xGAIA-1 by [~GAIA-2], see [GAIA-3|http://x.io] and {{GAIA-4}}",
+		},
+		{
+			name:     "custom pattern",
+			markdown: "See ops-7 and GAIA-1",
+			opts: Options{
+				IssueURL:     opts.IssueURL,
+				IssuePattern: regexp.MustCompile(`ops-\d+`),
+			},
+			want: "See [ops-7|https://jira.example.com/browse/ops-7] and GAIA-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJiraWithOptions(tt.markdown, tt.opts); got != tt.want {
+				t.Errorf("ToJiraWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJiraMentionMapping(t *testing.T) {
+	opts := Options{Mentions: map[string]string{
+		"appleboy": "bo-yi.wu",
+		"octocat":  "accountid:5b10ac8d82e05b22cc7d4ef5",
+	}}
+	got := ToJiraWithOptions("Thanks @appleboy, @OctoCat and @someone", opts)
+	want := "Thanks [~bo-yi.wu], [~accountid:5b10ac8d82e05b22cc7d4ef5] and [~someone]"
+	if got != want {
+		t.Errorf("ToJiraWithOptions() = %q, want %q", got, want)
+	}
+}
+
+func TestToJiraMentionMode(t *testing.T) {
+	text := "@bob: see (@Override) and x@y.io, cc\t@alice"
+	tests := []struct {
+		mode MentionMode
+		want string
+	}{
+		{MentionLoose, "[~bob]: see ([~Override]) and x@y.io, cc\t[~alice]"},
+		{MentionStrict, "[~bob]: see (@Override) and x@y.io, cc\t[~alice]"},
+		{MentionOff, text},
+	}
+	for _, tt := range tests {
+		if got := ToJiraWithOptions(text, Options{MentionMode: tt.mode}); got != tt.want {
+			t.Errorf("mode %d: ToJiraWithOptions() = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+
+	opts := Options{IssueURL: "https://jira.example.com/browse/"}
+	got := ToJiraWithOptions("GAIA-1@bob", opts)
+	if want := "[GAIA-1|https://jira.example.com/browse/GAIA-1]@bob"; got != want {
+		t.Errorf("after a linked key: ToJiraWithOptions() = %q, want %q", got, want)
+	}
+}
+
+func TestToJiraEmailMentions(t *testing.T) {
+	var looked []string
+	opts := Options{
+		Mentions: map[string]string{"mona@example.com": "mona.lisa"},
+		ResolveEmail: func(email string) (string, bool) {
+			looked = append(looked, email)
+			if email == "bo-yi.wu+ci@example.com" {
+				return "appleboy", true
+			}
+			return "", false
+		},
+	}
+	text := "Thanks @bo-yi.wu+ci@example.com, @Mona@example.com and @ghost@example.org."
+	want := "Thanks [~appleboy], [~mona.lisa] and @ghost@example.org."
+	if got := ToJiraWithOptions(text, opts); got != want {
+		t.Errorf("ToJiraWithOptions() = %q, want %q", got, want)
+	}
+	if len(looked) != 2 {
+		t.Errorf("ResolveEmail calls = %v, want the two unmapped addresses", looked)
+	}
+	if got, want := ToJira("cc @bob@example.com"), "cc @bob@example.com"; got != want {
+		t.Errorf("without a resolver: ToJira() = %q, want %q", got, want)
+	}
+}
+
+func TestToJiraImagesAttached(t *testing.T) {
+	opts := Options{BaseURL: "https://github.com/appleboy/go-jira/blob/main", ImagesAttached: true}
+	got := ToJiraWithOptions("![chart](chart.png =400x) ![logo](docs/logo.png)", opts)
+	want := "!chart.png|alt=chart,width=400! " +
+		"!https://github.com/appleboy/go-jira/blob/main/docs/logo.png|alt=logo!"
+	if got != want {
+		t.Errorf("ToJiraWithOptions() = %q, want %q", got, want)
+	}
+}
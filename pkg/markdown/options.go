@@ -0,0 +1,191 @@
+package markdown
+
+import "regexp"
+
+// HTMLMode selects how raw HTML blocks and spans in the Markdown are
+// rendered.
+type HTMLMode int
+
+const (
+	// HTMLDrop drops raw HTML, the default.
+	HTMLDrop HTMLMode = iota
+	// HTMLEscape renders raw HTML as literal text, escaping the characters
+	// Jira would otherwise read as markup.
+	HTMLEscape
+	// HTMLConvert converts a safe subset of tags (<br>, <b>, <strong>, <i>,
+	// <em>, <u>, <del>, <s>, <sub>, <sup>, <code>, <a href>, <img src>) to
+	// Jira markup, keeps the text inside any other tag, and drops the other
+	// tags and comments.
+	HTMLConvert
+	// HTMLPassthrough emits raw HTML untouched.
+	HTMLPassthrough
+)
+
+// MentionMode selects which @name text is converted to a Jira [~user]
+// mention.
+type MentionMode int
+
+const (
+	// MentionLoose converts an @name not preceded by a letter, digit, '-',
+	// or '_', so e-mail addresses are left alone; the default.
+	MentionLoose MentionMode = iota
+	// MentionStrict only converts an @name at the start of a line or after
+	// whitespace, leaving code-like text such as "(@Override" alone.
+	MentionStrict
+	// MentionOff converts no mentions.
+	MentionOff
+)
+
+// LineBreakStyle selects how the line breaks inside a paragraph of the
+// Markdown are rendered.
+type LineBreakStyle int
+
+const (
+	// LineBreakPreserve keeps them as newlines, which Jira shows as line
+	// breaks in a paragraph; the default.
+	LineBreakPreserve LineBreakStyle = iota
+	// LineBreakSpace joins the lines with a space, as Markdown renderers
+	// show a paragraph.
+	LineBreakSpace
+	// LineBreakHard renders them as Jira's \\ forced line break, keeping a
+	// list item or table cell on one source line.
+	LineBreakHard
+)
+
+// Options configure the Jira renderer. The zero value renders like ToJira
+// without options, and NewOptions builds one from Option funcs.
+type Options struct {
+	// HTML selects how raw HTML is rendered.
+	HTML HTMLMode
+	// LineBreaks selects how the line breaks inside a paragraph render.
+	LineBreaks LineBreakStyle
+	// HeadingOffset is added to every heading level, so with 2 a "# Title"
+	// renders as h3. and does not dominate the issue it is embedded in.
+	// Levels stay within Jira's h1 to h6.
+	HeadingOffset int
+	// TOC selects the table of contents put before a document with
+	// headings.
+	TOC TOCMode
+	// DefaultCodeLanguage is the language of fenced code blocks that name
+	// none. When empty they render as {noformat} rather than as highlighted
+	// code, as indented code blocks always do.
+	DefaultCodeLanguage string
+	// MermaidImageURL, when set, renders ```mermaid fences as an image whose
+	// URL is it followed by the base64url-encoded diagram, as the
+	// https://mermaid.ink/img/ service takes it; the diagram is sent there.
+	// When empty they render as plain code, since Jira cannot draw them.
+	MermaidImageURL string
+	// BaseURL, when set, is what relative link and image URLs such as
+	// ./docs/setup.md or images/a.png are resolved against, e.g. the blob URL
+	// of the repository at the commit. A missing trailing slash is implied.
+	BaseURL string
+	// ImagesAttached renders images whose URL is a bare file name, such as
+	// ![build](chart.png), as references to that attachment of the issue
+	// instead of resolving them against BaseURL.
+	ImagesAttached bool
+	// IssueURL, when set, links the issue keys in text to it followed by
+	// the key, e.g. https://jira.example.com/browse/. When empty keys are
+	// left bare for Jira to link.
+	IssueURL string
+	// IssuePattern matches the issue keys IssueURL links; nil uses
+	// issuekey.DefaultPattern.
+	IssuePattern *regexp.Regexp
+	// MentionMode selects which @name text becomes a Jira user mention.
+	MentionMode MentionMode
+	// Mentions maps Git usernames, matched case-insensitively, to the Jira
+	// users their @mentions link to, e.g. "accountid:5b10ac8d82e05b22cc7d4ef5"
+	// for Jira Cloud. Unmapped names link to the Jira user of the same name.
+	Mentions map[string]string
+	// ResolveEmail, when set, returns the Jira user an @user@example.com
+	// mention that Mentions does not map links to, e.g. by searching the
+	// Jira users. Addresses it cannot resolve are left as text. It is
+	// called once per mention, so it should cache its lookups, and from
+	// every goroutine converting with these Options, so it must be safe for
+	// concurrent use.
+	ResolveEmail func(email string) (user string, ok bool)
+}
+
+// Option configures the renderer, as an alternative to filling in Options.
+type Option func(*Options)
+
+// NewOptions returns the Options that opts configure, starting from the zero
+// value; later options override earlier ones.
+func NewOptions(opts ...Option) Options {
+	if len(opts) == 0 {
+		return Options{}
+	}
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithHTML sets how raw HTML is rendered.
+func WithHTML(mode HTMLMode) Option {
+	return func(o *Options) { o.HTML = mode }
+}
+
+// WithLineBreaks sets how the line breaks inside a paragraph render.
+func WithLineBreaks(style LineBreakStyle) Option {
+	return func(o *Options) { o.LineBreaks = style }
+}
+
+// WithHeadingOffset sets the offset added to every heading level.
+func WithHeadingOffset(offset int) Option {
+	return func(o *Options) { o.HeadingOffset = offset }
+}
+
+// WithTOC sets the table of contents put before a document with headings.
+func WithTOC(mode TOCMode) Option {
+	return func(o *Options) { o.TOC = mode }
+}
+
+// WithDefaultCodeLanguage sets the language of fenced code blocks that name
+// none.
+func WithDefaultCodeLanguage(language string) Option {
+	return func(o *Options) { o.DefaultCodeLanguage = language }
+}
+
+// WithMermaidImageURL renders ```mermaid fences as images from the service
+// at url.
+func WithMermaidImageURL(url string) Option {
+	return func(o *Options) { o.MermaidImageURL = url }
+}
+
+// WithBaseURL sets the URL relative link and image URLs are resolved
+// against.
+func WithBaseURL(url string) Option {
+	return func(o *Options) { o.BaseURL = url }
+}
+
+// WithImagesAttached sets whether images named by a bare file name render
+// as attachments of the issue.
+func WithImagesAttached(attached bool) Option {
+	return func(o *Options) { o.ImagesAttached = attached }
+}
+
+// WithIssueLinks links the issue keys pattern matches in text to url
+// followed by the key. A nil pattern uses issuekey.DefaultPattern.
+func WithIssueLinks(url string, pattern *regexp.Regexp) Option {
+	return func(o *Options) {
+		o.IssueURL = url
+		o.IssuePattern = pattern
+	}
+}
+
+// WithMentionMode sets which @name text becomes a Jira user mention.
+func WithMentionMode(mode MentionMode) Option {
+	return func(o *Options) { o.MentionMode = mode }
+}
+
+// WithMentions sets the Jira users the @mentions of Git usernames link to.
+func WithMentions(users map[string]string) Option {
+	return func(o *Options) { o.Mentions = users }
+}
+
+// WithEmailResolver sets the lookup of the Jira user an @user@example.com
+// mention links to.
+func WithEmailResolver(resolve func(email string) (user string, ok bool)) Option {
+	return func(o *Options) { o.ResolveEmail = resolve }
+}
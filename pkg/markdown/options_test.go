@@ -0,0 +1,72 @@
+package markdown
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+)
+
+func TestNewOptions(t *testing.T) {
+	pattern := regexp.MustCompile(`GAIA-\d+`)
+	o := NewOptions(
+		WithHTML(HTMLEscape),
+		WithHTML(HTMLConvert),
+		WithLineBreaks(LineBreakHard),
+		WithIssueLinks("https://jira.example.com/browse/", pattern),
+		WithMentions(map[string]string{"octocat": "cat"}),
+	)
+	if o.HTML != HTMLConvert || o.LineBreaks != LineBreakHard ||
+		o.IssueURL != "https://jira.example.com/browse/" || o.IssuePattern != pattern ||
+		o.Mentions["octocat"] != "cat" {
+		t.Errorf("NewOptions() = %+v", o)
+	}
+	if o := NewOptions(); o.HTML != HTMLDrop || o.TOC != TOCNone || o.ResolveEmail != nil {
+		t.Errorf("NewOptions() = %+v, want the zero value", o)
+	}
+}
+
+func TestToJiraOptions(t *testing.T) {
+	src := "# Notes\nFixed GAIA-1 for @octocat\nsee [docs](docs/a.md)"
+	opts := []Option{
+		WithHeadingOffset(1),
+		WithLineBreaks(LineBreakSpace),
+		WithBaseURL("https://example.com/repo/"),
+		WithIssueLinks("https://jira.example.com/browse/", nil),
+		WithMentions(map[string]string{"octocat": "cat"}),
+	}
+	want := "h2. Notes\n\nFixed [GAIA-1|https://jira.example.com/browse/GAIA-1] for " +
+		"[~cat] see [docs|https://example.com/repo/docs/a.md]"
+	if got := ToJira(src, opts...); got != want {
+		t.Errorf("ToJira() = %q, want %q", got, want)
+	}
+	if got := NewJiraRenderer(opts...).Render(src); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderConcurrent(t *testing.T) {
+	r := NewJiraRenderer(WithHTML(HTMLConvert), WithTOC(TOCList))
+	docs := []string{
+		"# A\n\n* one\n  1. two\n\n> [!NOTE]\n> @octocat <a href=\"x\">link</a>",
+		"## B\n\n1. first\n   * second\n\n> quote <a href=\"y\">other</a>",
+	}
+	want := make([]string, len(docs))
+	for i, doc := range docs {
+		want[i] = r.Render(doc)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				i := (g + n) % len(docs)
+				if got := r.Render(docs[i]); got != want[i] {
+					t.Errorf("Render(%q) = %q, want %q", docs[i], got, want[i])
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
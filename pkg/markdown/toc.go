@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"strings"
+
+	"github.com/appleboy/com/bytesconv"
+	bf "github.com/russross/blackfriday/v2"
+)
+
+// TOCMode selects the table of contents put before a document with
+// headings.
+type TOCMode int
+
+const (
+	// TOCNone adds no table of contents, the default.
+	TOCNone TOCMode = iota
+	// TOCMacro adds the {toc} macro, which Jira instances with a table of
+	// contents macro installed expand.
+	TOCMacro
+	// TOCList adds a bullet list of links to the headings, nested by level,
+	// which works in any Jira.
+	TOCList
+)
+
+// tocHeading is a heading listed in the table of contents.
+type tocHeading struct {
+	level int
+	id    string
+	title string
+}
+
+// documentHeadings returns the headings of markdown outside admonitions,
+// with the IDs writeBlocks gives them.
+func documentHeadings(markdown string) []tocHeading {
+	var headings []tocHeading
+	for _, s := range splitAdmonitions(markdown) {
+		if s.admonition != nil {
+			continue
+		}
+		parser := bf.New(bf.WithExtensions(markdownExtensions))
+		parser.Parse(bytesconv.StrToBytes(s.markdown)).Walk(
+			func(node *bf.Node, entering bool) bf.WalkStatus {
+				if entering && node.Type == bf.Heading && node.HeadingID != "" {
+					headings = append(headings, tocHeading{
+						level: node.Level,
+						id:    node.HeadingID,
+						title: nodeText(node),
+					})
+				}
+				return bf.GoToNext
+			})
+	}
+	return headings
+}
+
+// tableOfContents returns the table of contents the TOC option asks for,
+// or "" when there is none.
+func tableOfContents(headings []tocHeading, mode TOCMode) string {
+	if len(headings) == 0 {
+		return ""
+	}
+	switch mode {
+	case TOCMacro:
+		return "{toc}"
+	case TOCList:
+		top := headings[0].level
+		for _, h := range headings {
+			top = min(top, h.level)
+		}
+		lines := make([]string, 0, len(headings))
+		for _, h := range headings {
+			title := strings.Join(strings.Fields(linkTextStripper.Replace(h.title)), " ")
+			lines = append(lines, strings.Repeat("*", h.level-top+1)+" ["+title+"|#"+h.id+"]")
+		}
+		return strings.Join(lines, "\n")
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package markdown
+
+import "testing"
+
+func TestToJiraTOC(t *testing.T) {
+	src := "## Release 1.2\n\nNotes.\n\n### Fixes | misc\n\n:::note\n## Hidden\n:::\n\n## Upgrade"
+	tests := []struct {
+		name string
+		mode TOCMode
+		src  string
+		want string
+	}{
+		{
+			name: "none",
+			mode: TOCNone,
+			src:  src,
+			want: "h2. Release 1.2\n\nNotes.\n\nh3. Fixes | misc\n\n{note}\nh2. Hidden\n{note}\n\n" +
+				"h2. Upgrade",
+		},
+		{
+			name: "macro",
+			mode: TOCMacro,
+			src:  src,
+			want: "{toc}\n\nh2. Release 1.2\n\nNotes.\n\nh3. Fixes | misc\n\n{note}\nh2. Hidden\n" +
+				"{note}\n\nh2. Upgrade",
+		},
+		{
+			name: "list",
+			mode: TOCList,
+			src:  src,
+			want: "* [Release 1.2|#release-1-2]\n** [Fixes misc|#fixes-misc]\n* [Upgrade|#upgrade]\n\n" +
+				"h2. {anchor:release-1-2}Release 1.2\n\nNotes.\n\nh3. {anchor:fixes-misc}Fixes | misc\n\n" +
+				"{note}\nh2. Hidden\n{note}\n\nh2. {anchor:upgrade}Upgrade",
+		},
+		{
+			name: "no headings",
+			mode: TOCList,
+			src:  "Just text.",
+			want: "Just text.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJiraWithOptions(tt.src, Options{TOC: tt.mode}); got != tt.want {
+				t.Errorf("ToJiraWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package markdown
+
+import (
+	"io"
+
+	"github.com/appleboy/com/bytesconv"
+)
+
+// ToJiraWriter converts the Markdown read from r to Jira markup written to
+// w, configured by opts as ToJira is. Only the output is streamed: each
+// admonition and the Markdown between them is written to w straight from
+// the renderer's buffer rather than collected into one string. The input is
+// not: Blackfriday parses whole documents, and reference links, footnotes,
+// and the table of contents need all of it, so r is read into memory first.
+// It returns the first error reading r or writing w.
+func ToJiraWriter(w io.Writer, r io.Reader, opts ...Option) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return convert(&partWriter{w: w}, bytesconv.BytesToStr(data), NewOptions(opts...))
+}
+
+// partWriter writes the converted parts of a document to w, separated by a
+// blank line, skipping empty ones. After a failed write it writes nothing
+// more and keeps the error.
+type partWriter struct {
+	w     io.Writer
+	parts int
+	err   error
+}
+
+func (p *partWriter) write(part []byte) {
+	if p.err != nil || len(part) == 0 {
+		return
+	}
+	if p.parts > 0 {
+		if _, p.err = io.WriteString(p.w, "\n\n"); p.err != nil {
+			return
+		}
+	}
+	_, p.err = p.w.Write(part)
+	p.parts++
+}
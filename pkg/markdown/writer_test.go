@@ -0,0 +1,63 @@
+package markdown
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestToJiraWriter(t *testing.T) {
+	docs := []string{
+		"",
+		"# Title\n\nHello @octocat",
+		"intro\n\n:::warning Careful\nbody **bold**\n:::\n\n## After\n\nend",
+		releaseNotes(20),
+	}
+	for _, doc := range docs {
+		for _, toc := range []TOCMode{TOCNone, TOCList} {
+			var b strings.Builder
+			if err := ToJiraWriter(&b, strings.NewReader(doc), WithTOC(toc)); err != nil {
+				t.Fatalf("ToJiraWriter() error = %v", err)
+			}
+			if want := ToJira(doc, WithTOC(toc)); b.String() != want {
+				t.Errorf("ToJiraWriter(%.40q) = %.80q, want %.80q", doc, b.String(), want)
+			}
+		}
+	}
+}
+
+// failingWriter accepts n writes and fails the ones after.
+type failingWriter struct {
+	n      int
+	writes int
+}
+
+var errWrite = errors.New("disk full")
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.writes++; f.writes > f.n {
+		return 0, errWrite
+	}
+	return len(p), nil
+}
+
+func TestToJiraWriterErrors(t *testing.T) {
+	errRead := errors.New("connection reset")
+	var b strings.Builder
+	if err := ToJiraWriter(&b, iotest.ErrReader(errRead)); !errors.Is(err, errRead) {
+		t.Errorf("read error = %v, want %v", err, errRead)
+	}
+	if b.Len() != 0 {
+		t.Errorf("wrote %q after a read error", b.String())
+	}
+
+	w := &failingWriter{n: 1}
+	doc := "one\n\n:::info\ntwo\n:::\n\nthree"
+	if err := ToJiraWriter(w, strings.NewReader(doc)); !errors.Is(err, errWrite) {
+		t.Errorf("write error = %v, want %v", err, errWrite)
+	}
+	if w.writes != 2 {
+		t.Errorf("%d writes, want none after the failed one", w.writes)
+	}
+}
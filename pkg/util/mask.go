@@ -0,0 +1,151 @@
+package util
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RedactedMark replaces every masked secret.
+const RedactedMark = "[REDACTED]"
+
+// minSecretLen keeps very short values (e.g. a one-character test password)
+// from masking unrelated text wherever the same characters appear.
+const minSecretLen = 4
+
+var (
+	// authHeaderPattern matches an Authorization header value, keeping the
+	// scheme so the output still shows which auth mode was used.
+	authHeaderPattern = regexp.MustCompile(
+		`(?i)(authorization"?\s*[:=]\s*"?(?:bearer|basic|negotiate|token)?\s*)[^\s",}]+`)
+	// secretFieldPattern matches key=value / "key": "value" pairs whose key
+	// names a credential, as found in query strings, JSON bodies, and dumps.
+	secretFieldPattern = regexp.MustCompile(
+		`(?i)("?(?:password|passwd|token|access_token|refresh_token|client_secret|secret)"?\s*[:=]\s*"?)[^\s"&,}]+`)
+)
+
+// maskedValues holds the literal secrets registered with Mask, so they are
+// redacted even where no key names them (e.g. echoed back inside an error
+// message).
+var maskedValues = struct {
+	sync.RWMutex
+	values []string
+}{}
+
+// Mask registers secrets to be replaced by Redact, and so by every log line
+// written through a NewRedactHandler handler, for the rest of the process,
+// like the add-mask command of GitHub Actions. Empty and very short values
+// are ignored. Longer values are replaced first so a secret that contains
+// another is masked whole.
+//
+// Parameters:
+//
+//	secrets - the secret values to mask.
+func Mask(secrets ...string) {
+	maskedValues.Lock()
+	defer maskedValues.Unlock()
+	for _, v := range secrets {
+		v = strings.TrimSpace(v)
+		if len(v) < minSecretLen || slices.Contains(maskedValues.values, v) {
+			continue
+		}
+		maskedValues.values = append(maskedValues.values, v)
+	}
+	sort.Slice(maskedValues.values, func(i, j int) bool {
+		return len(maskedValues.values[i]) > len(maskedValues.values[j])
+	})
+}
+
+// Redact replaces the secrets registered with Mask, Authorization header
+// values, and credential-named fields (password=..., "token": "...") in s
+// with RedactedMark.
+//
+// Parameters:
+//
+//	s - the text to redact.
+//
+// Returns:
+//
+//	string - s with every secret masked.
+func Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	maskedValues.RLock()
+	for _, v := range maskedValues.values {
+		s = strings.ReplaceAll(s, v, RedactedMark)
+	}
+	maskedValues.RUnlock()
+	s = authHeaderPattern.ReplaceAllString(s, "${1}"+RedactedMark)
+	return secretFieldPattern.ReplaceAllString(s, "${1}"+RedactedMark)
+}
+
+// NewRedactHandler wraps inner in a slog.Handler that passes the message and
+// every string and error attribute value, including those in groups and
+// those added with Logger.With, through Redact before inner sees them, so no
+// log line (at any level) can carry a masked secret.
+//
+// Parameters:
+//
+//	inner - the handler that writes the redacted records.
+//
+// Returns:
+//
+//	slog.Handler - the redacting handler.
+func NewRedactHandler(inner slog.Handler) slog.Handler {
+	return &redactHandler{inner: inner}
+}
+
+type redactHandler struct {
+	inner slog.Handler
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, Redact(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	masked := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		masked[i] = redactAttr(a)
+	}
+	return &redactHandler{inner: h.inner.WithAttrs(masked)}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{inner: h.inner.WithGroup(name)}
+}
+
+// redactAttr masks string and error values, recursing into groups.
+func redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		masked := make([]any, len(group))
+		for i, ga := range group {
+			masked[i] = redactAttr(ga)
+		}
+		return slog.Group(a.Key, masked...)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
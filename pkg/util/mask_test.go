@@ -0,0 +1,75 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	Mask("pat-0123456789", "abc")
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "registered value anywhere",
+			in:   "GET failed: invalid token pat-0123456789 for user",
+			want: "GET failed: invalid token [REDACTED] for user",
+		},
+		{
+			name: "bearer header",
+			in:   "Authorization: Bearer eyJhbGciOi.xyz",
+			want: "Authorization: Bearer [REDACTED]",
+		},
+		{
+			name: "basic header",
+			in:   `"Authorization":"Basic dXNlcjpwYXNz"`,
+			want: `"Authorization":"Basic [REDACTED]"`,
+		},
+		{
+			name: "JSON password field",
+			in:   `{"username":"ci","password":"hunter22"}`,
+			want: `{"username":"ci","password":"[REDACTED]"}`,
+		},
+		{
+			name: "query token",
+			in:   "https://jira/rest?refresh_token=r1r2r3&x=1",
+			want: "https://jira/rest?refresh_token=[REDACTED]&x=1",
+		},
+		{
+			name: "short values are not registered",
+			in:   "abc stays",
+			want: "abc stays",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.in); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHandlerMasksAttrsAndErrors(t *testing.T) {
+	Mask("handler-secret-1234")
+	var buf bytes.Buffer
+	log := slog.New(NewRedactHandler(slog.NewTextHandler(&buf, nil)))
+
+	log.With("token", "handler-secret-1234").Info("request handler-secret-1234",
+		"error", errors.New("401 for handler-secret-1234"),
+		slog.Group("req", "auth", "Authorization: Bearer handler-secret-1234"))
+
+	out := buf.String()
+	if strings.Contains(out, "handler-secret-1234") {
+		t.Errorf("secret leaked into log line:\n%s", out)
+	}
+	if !strings.Contains(out, RedactedMark) {
+		t.Errorf("expected redaction marker:\n%s", out)
+	}
+}
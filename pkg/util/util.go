@@ -1,34 +1,53 @@
 package util
 
 import (
+	"fmt"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GetGlobalValue returns the value of an environment variable.
 // It first checks if there is an environment variable with the format "INPUT_<KEY>",
-// where "<KEY>" is the input key converted to uppercase.
-// If the "INPUT_<KEY>" environment variable doesn't exist or is empty,
-// it returns the value of the "<KEY>" environment variable.
+// where "<KEY>" is the input key converted to uppercase, as set by GitHub Actions.
+// If that doesn't exist or is empty, it checks "PLUGIN_<KEY>", as set by Drone
+// and Woodpecker for plugin settings, and finally the "<KEY>" environment variable.
+// The precedence is therefore INPUT_<KEY> > PLUGIN_<KEY> > <KEY>.
+//
+// Several candidate keys may be given, e.g. GetGlobalValue("token",
+// "jira_token", "api_token"), to accept the naming conventions of several CI
+// systems. They are tried in order, each with the full precedence above, and
+// the first non-empty value is returned.
 //
 // Parameters:
 //
-//	key - the key of the environment variable to retrieve.
+//	keys - the keys of the environment variable to retrieve, in order of preference.
 //
 // Returns:
 //
-//	string - the value of the environment variable.
-func GetGlobalValue(key string) string {
-	key = strings.ToUpper(key) // Convert key to uppercase
+//	string - the value of the environment variable, or "" when none is set.
+func GetGlobalValue(keys ...string) string {
+	for _, key := range keys {
+		key = strings.ToUpper(key) // Convert key to uppercase
 
-	// Check if there is an environment variable with the format "INPUT_<KEY>"
-	if value := os.Getenv("INPUT_" + key); value != "" {
-		return value // Return the value of the "INPUT_<KEY>" environment variable
-	}
+		// Check if there is an environment variable with the format "INPUT_<KEY>"
+		if value := os.Getenv("INPUT_" + key); value != "" {
+			return value // Return the value of the "INPUT_<KEY>" environment variable
+		}
 
-	// If the "INPUT_<KEY>" environment variable doesn't exist or is empty,
-	// return the value of the "<KEY>" environment variable
-	return os.Getenv(key)
+		// Drone and Woodpecker pass plugin settings as "PLUGIN_<KEY>"
+		if value := os.Getenv("PLUGIN_" + key); value != "" {
+			return value
+		}
+
+		// Otherwise use the value of the "<KEY>" environment variable
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
 }
 
 // ToBool converts a string to a boolean value.
@@ -45,3 +64,207 @@ func GetGlobalValue(key string) string {
 func ToBool(s string) bool {
 	return s == "1" || strings.ToLower(s) == "true"
 }
+
+// GetInt returns the value of the environment variable for key, looked up
+// like GetGlobalValue, parsed as a base-10 integer.
+// It returns def when the variable is unset or empty, and def together with
+// the parse error when the value is not an integer.
+//
+// Parameters:
+//
+//	key - the key of the environment variable to retrieve.
+//	def - the value to return when the variable is unset or invalid.
+//
+// Returns:
+//
+//	int - the parsed value, or def.
+//	error - the parse error of an invalid value, naming the variable.
+func GetInt(key string, def int) (int, error) {
+	v := GetGlobalValue(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return def, fmt.Errorf("invalid integer %s=%q: %w", strings.ToUpper(key), v, err)
+	}
+	return n, nil
+}
+
+// GetDuration returns the value of the environment variable for key, looked
+// up like GetGlobalValue, parsed with time.ParseDuration (e.g. "30s", "5m").
+// It returns def when the variable is unset or empty, and def together with
+// the parse error when the value is not a duration.
+//
+// Parameters:
+//
+//	key - the key of the environment variable to retrieve.
+//	def - the value to return when the variable is unset or invalid.
+//
+// Returns:
+//
+//	time.Duration - the parsed value, or def.
+//	error - the parse error of an invalid value, naming the variable.
+func GetDuration(key string, def time.Duration) (time.Duration, error) {
+	v := GetGlobalValue(key)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil {
+		return def, fmt.Errorf("invalid duration %s=%q: %w", strings.ToUpper(key), v, err)
+	}
+	return d, nil
+}
+
+// GetBoolDefault returns the value of the environment variable for key,
+// looked up like GetGlobalValue, converted with ToBool, or def when the
+// variable is unset or empty. Unlike ToBool(GetGlobalValue(key)) it lets a
+// setting default to true.
+//
+// Parameters:
+//
+//	key - the key of the environment variable to retrieve.
+//	def - the value to return when the variable is unset.
+//
+// Returns:
+//
+//	bool - the boolean value of the variable, or def.
+func GetBoolDefault(key string, def bool) bool {
+	v := GetGlobalValue(key)
+	if v == "" {
+		return def
+	}
+	return ToBool(strings.TrimSpace(v))
+}
+
+// GetStringSlice returns the value of the environment variable for key,
+// looked up like GetGlobalValue, split on any of seps (a comma when none are
+// given). Items are trimmed of surrounding whitespace and empty items are
+// dropped. It returns def when the variable is unset or holds no items.
+//
+// Parameters:
+//
+//	key - the key of the environment variable to retrieve.
+//	def - the value to return when the variable is unset or empty.
+//	seps - the separators to split on, e.g. "," and "\n".
+//
+// Returns:
+//
+//	[]string - the items of the variable, or def.
+func GetStringSlice(key string, def []string, seps ...string) []string {
+	v := GetGlobalValue(key)
+	if len(seps) == 0 {
+		seps = []string{","}
+	}
+	items := []string{v}
+	for _, sep := range seps {
+		var split []string
+		for _, item := range items {
+			split = append(split, strings.Split(item, sep)...)
+		}
+		items = split
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	if len(out) == 0 {
+		return def
+	}
+	return out
+}
+
+// GetSecretValue returns the value of the environment variable for key,
+// looked up like GetGlobalValue. When it is unset or empty, the secret is
+// read from the file named by the "<KEY>_FILE" variable (looked up the same
+// way, so INPUT_<KEY>_FILE wins over PLUGIN_<KEY>_FILE and <KEY>_FILE), the convention for Docker
+// and Kubernetes secrets mounted as files. Trailing newlines are trimmed.
+// The secret is registered with Mask, so it is redacted from logs by default.
+//
+// Parameters:
+//
+//	key - the key of the environment variable to retrieve.
+//
+// Returns:
+//
+//	string - the secret, or "" when neither variable is set.
+//	error - the error reading the <KEY>_FILE file, if any.
+func GetSecretValue(key string) (string, error) {
+	if v := GetGlobalValue(key); v != "" {
+		Mask(v)
+		return v, nil
+	}
+	fileKey := strings.ToUpper(key) + "_FILE"
+	path := GetGlobalValue(fileKey)
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the operator's secret mount
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", fileKey, err)
+	}
+	v := strings.TrimRight(string(data), "\r\n")
+	Mask(v)
+	return v, nil
+}
+
+// envRefPattern matches a ${NAME} reference, or the $${NAME} escape.
+var envRefPattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandablePrefixes are the environment variables ExpandEnv may read: the
+// CI metadata GitHub Actions and GitLab CI set for every job.
+var expandablePrefixes = []string{"GITHUB_", "CI_"}
+
+// secretNameParts mark CI variables that hold credentials, such as
+// GITHUB_TOKEN or CI_JOB_TOKEN, which ExpandEnv never reads.
+var secretNameParts = []string{"TOKEN", "PASSWORD", "SECRET", "JWT"}
+
+// expandable reports whether ExpandEnv may substitute the variable name.
+func expandable(name string) bool {
+	for _, part := range secretNameParts {
+		if strings.Contains(name, part) {
+			return false
+		}
+	}
+	for _, prefix := range expandablePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandEnv replaces ${NAME} references in s with the value of the NAME
+// environment variable, or "" when it is unset. Only CI metadata variables
+// (GITHUB_* and CI_*, minus tokens and passwords) are expanded; any other
+// reference, such as ${INPUT_TOKEN}, is left as written so a value cannot
+// pull secrets out of the environment. Only the braced form is expanded, so
+// a bare "$" is left alone, and so is any other ${...} text such as
+// "${NAME:-default}". "$${NAME}" escapes the reference and yields a literal
+// "${NAME}".
+//
+// Parameters:
+//
+//	s - the string to expand.
+//
+// Returns:
+//
+//	string - s with its ${NAME} references expanded.
+func ExpandEnv(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	return envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+		name := ref[2 : len(ref)-1]
+		if !expandable(name) {
+			return ref
+		}
+		return os.Getenv(name)
+	})
+}
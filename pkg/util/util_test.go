@@ -2,7 +2,10 @@ package util
 
 import (
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestToBool(t *testing.T) {
@@ -90,6 +93,26 @@ func TestGetGlobalValue(t *testing.T) {
 			want:        "test-value",
 			shouldClear: []string{"TESTKEY"},
 		},
+		{
+			name: "PLUGIN_ prefixed value beats non-prefixed",
+			key:  "pluginkey",
+			envVars: map[string]string{
+				"PLUGIN_PLUGINKEY": "plugin-value",
+				"PLUGINKEY":        "regular-value",
+			},
+			want:        "plugin-value",
+			shouldClear: []string{"PLUGIN_PLUGINKEY", "PLUGINKEY"},
+		},
+		{
+			name: "INPUT_ prefixed value beats PLUGIN_",
+			key:  "bothkey",
+			envVars: map[string]string{
+				"INPUT_BOTHKEY":  "input-value",
+				"PLUGIN_BOTHKEY": "plugin-value",
+			},
+			want:        "input-value",
+			shouldClear: []string{"INPUT_BOTHKEY", "PLUGIN_BOTHKEY"},
+		},
 		{
 			name:        "empty when no env vars set",
 			key:         "missing",
@@ -129,3 +152,209 @@ func TestGetGlobalValue(t *testing.T) {
 		})
 	}
 }
+
+func TestGetGlobalValueMultipleKeys(t *testing.T) {
+	t.Setenv("INPUT_TOKEN", "")
+	t.Setenv("PLUGIN_TOKEN", "")
+	t.Setenv("TOKEN", "")
+	t.Setenv("JIRA_TOKEN", "jira-token")
+	t.Setenv("INPUT_API_TOKEN", "api-token")
+
+	if got := GetGlobalValue("token", "jira_token", "api_token"); got != "jira-token" {
+		t.Errorf("GetGlobalValue = %q, want the first key that is set", got)
+	}
+	t.Setenv("TOKEN", "bare-token")
+	if got := GetGlobalValue("token", "jira_token", "api_token"); got != "bare-token" {
+		t.Errorf("GetGlobalValue = %q, want an earlier key to win", got)
+	}
+	if got := GetGlobalValue("missing", "also_missing"); got != "" {
+		t.Errorf("GetGlobalValue = %q, want empty", got)
+	}
+	if got := GetGlobalValue(); got != "" {
+		t.Errorf("GetGlobalValue() = %q, want empty", got)
+	}
+}
+
+func TestGetInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "unset uses default", value: "", want: 7},
+		{name: "valid", value: "12", want: 12},
+		{name: "surrounding space", value: " 3 ", want: 3},
+		{name: "invalid uses default", value: "ten", want: 7, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INPUT_RETRIES", tt.value)
+			got, err := GetInt("retries", 7)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetInt error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetInt = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "unset uses default", value: "", want: time.Minute},
+		{name: "valid", value: "90s", want: 90 * time.Second},
+		{name: "bare number is invalid", value: "30", want: time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TIMEOUT", tt.value)
+			got, err := GetDuration("timeout", time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDuration error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetDuration = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBoolDefault(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		def   bool
+		want  bool
+	}{
+		{name: "unset uses default true", value: "", def: true, want: true},
+		{name: "unset uses default false", value: "", def: false, want: false},
+		{name: "false overrides default", value: "false", def: true, want: false},
+		{name: "1 overrides default", value: "1", def: false, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INPUT_VERIFY", tt.value)
+			if got := GetBoolDefault("verify", tt.def); got != tt.want {
+				t.Errorf("GetBoolDefault = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStringSlice(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		seps  []string
+		want  []string
+	}{
+		{name: "unset uses default", value: "", want: []string{"Bug"}},
+		{
+			name:  "comma by default",
+			value: "Bug, Task ,,Story",
+			want:  []string{"Bug", "Task", "Story"},
+		},
+		{
+			name:  "custom separators",
+			value: "GAIA;OPS\nWEB",
+			seps:  []string{";", "\n"},
+			want:  []string{"GAIA", "OPS", "WEB"},
+		},
+		{name: "only separators uses default", value: " , ", want: []string{"Bug"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INPUT_TYPES", tt.value)
+			got := GetStringSlice("types", []string{"Bug"}, tt.seps...)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetStringSlice = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSecretValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{name: "unset", want: ""},
+		{name: "value wins over file", value: "direct", file: path, want: "direct"},
+		{name: "read from file", file: path, want: "s3cret"},
+		{name: "missing file", file: filepath.Join(t.TempDir(), "nope"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INPUT_TOKEN", tt.value)
+			t.Setenv("INPUT_TOKEN_FILE", tt.file)
+			got, err := GetSecretValue("token")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSecretValue err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetSecretValue = %q, want %q", got, tt.want)
+			}
+			if got != "" && Redact("auth "+got) != "auth "+RedactedMark {
+				t.Errorf("GetSecretValue did not mask %q", got)
+			}
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("GITHUB_ACTOR", "octocat")
+	t.Setenv("GITHUB_SHA", "abc123")
+	t.Setenv("GITHUB_TOKEN", "ghs_secret")
+	t.Setenv("INPUT_TOKEN", "jira-secret")
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no references", input: "Deployed", want: "Deployed"},
+		{
+			name:  "braced references",
+			input: "Deployed by ${GITHUB_ACTOR} at ${GITHUB_SHA}",
+			want:  "Deployed by octocat at abc123",
+		},
+		{name: "unset variable", input: "by ${GITHUB_UNSET_VAR}.", want: "by ."},
+		{name: "not allowlisted", input: "${INPUT_TOKEN} ${HOME}", want: "${INPUT_TOKEN} ${HOME}"},
+		{
+			name:  "CI token kept",
+			input: "${GITHUB_TOKEN} ${CI_JOB_TOKEN}",
+			want:  "${GITHUB_TOKEN} ${CI_JOB_TOKEN}",
+		},
+		{name: "bare dollar kept", input: "pa$$word $GITHUB_SHA", want: "pa$$word $GITHUB_SHA"},
+		{name: "escaped reference", input: "$${GITHUB_ACTOR}", want: "${GITHUB_ACTOR}"},
+		{name: "shell default untouched", input: "${GITHUB_ACTOR:-x}", want: "${GITHUB_ACTOR:-x}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandEnv(tt.input); got != tt.want {
+				t.Errorf("ExpandEnv(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}